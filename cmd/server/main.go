@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 
 	"echo-golang/internal/admin"
 	"echo-golang/internal/config"
 	"echo-golang/internal/database"
 	"echo-golang/internal/handlers"
+	"echo-golang/internal/live"
 	"echo-golang/internal/middleware"
+	"echo-golang/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,10 +33,23 @@ func main() {
 	}
 	defer database.Close()
 
+	// Fan-out of live match updates, through Redis when several instances run
+	redisAddr := ""
+	if config.AppConfig.RedisHost != "" {
+		redisAddr = net.JoinHostPort(config.AppConfig.RedisHost, config.AppConfig.RedisPort)
+	}
+	if err := live.Init(context.Background(), redisAddr, config.AppConfig.RedisPassword, config.AppConfig.LiveBufferSize); err != nil {
+		log.Fatal("Failed to connect to Redis:", err)
+	}
+	defer live.DefaultBroker.Close()
+	// Drop leaderboards invalidated by any instance
+	go services.WatchLeaderboards(live.DefaultBroker)
+
 	// Initialize Gin router
 	r := gin.Default()
 
 	// Middleware
+	r.Use(middleware.RequestID())
 	r.Use(middleware.SetupCORS())
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
@@ -47,12 +64,28 @@ func main() {
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler()
+	orgHandler := handlers.NewOrganizationHandler()
+	teamHandler := handlers.NewTeamHandler()
+	playerHandler := handlers.NewPlayerHandler()
+	tournamentHandler := handlers.NewTournamentHandler()
+	matchHandler := handlers.NewMatchHandler()
+	bracketHandler := handlers.NewBracketHandler()
+	matchEventHandler := handlers.NewMatchEventHandler()
+	gameHandler := handlers.NewGameHandler()
+	liveHandler := handlers.NewLiveHandler()
+	statisticsHandler := handlers.NewStatisticsHandler()
 
 	// Create default admin user
 	if err := admin.CreateDefaultAdmin(); err != nil {
 		log.Printf("Warning: Failed to create default admin: %v", err)
 	}
 
+	// Server-rendered admin console
+	admin.SetupConsoleRoutes(r)
+
+	// WebSocket routes
+	r.GET("/ws/matches/:id/live", middleware.StreamAuth(), liveHandler.MatchSocket)
+
 	// API routes
 	api := r.Group("/api/v1")
 	{
@@ -64,6 +97,47 @@ func main() {
 			auth.POST("/refresh", authHandler.RefreshToken)
 		}
 
+		// Public team routes
+		api.GET("/teams", teamHandler.ListTeams)
+		api.GET("/teams/:id", teamHandler.GetTeam)
+		api.GET("/teams/:id/players", playerHandler.ListTeamPlayers)
+		api.GET("/teams/:id/roster", playerHandler.GetTeamRoster)
+		api.GET("/teams/:id/statistics", middleware.OptionalAuth(), statisticsHandler.GetTeamStatistics)
+		api.GET("/teams/:id/lineups", statisticsHandler.GetTeamLineups)
+
+		// Public player routes
+		api.GET("/players", playerHandler.ListPlayers)
+		api.GET("/players/:id", playerHandler.GetPlayer)
+		api.GET("/players/:id/history", playerHandler.GetPlayerHistory)
+		api.GET("/players/:id/statistics", middleware.OptionalAuth(), statisticsHandler.GetPlayerStatistics)
+
+		// Public tournament routes
+		api.GET("/tournaments", tournamentHandler.ListTournaments)
+		api.GET("/tournaments/:id", tournamentHandler.GetTournament)
+		api.GET("/tournaments/:id/teams", tournamentHandler.ListTeams)
+		api.GET("/tournaments/:id/standings", tournamentHandler.GetStandings)
+		api.GET("/tournaments/:id/matches", matchHandler.ListTournamentMatches)
+		api.GET("/tournaments/:id/bracket", bracketHandler.GetBracket)
+		api.GET("/tournaments/:id/shot-chart", statisticsHandler.GetTournamentShotChart)
+
+		// Public match routes
+		api.GET("/matches", matchHandler.ListMatches)
+		api.GET("/matches/upcoming", matchHandler.ListUpcomingMatches)
+		api.GET("/matches/live", matchHandler.ListLiveMatches)
+		api.GET("/matches/completed", matchHandler.ListCompletedMatches)
+		api.GET("/matches/:id", matchHandler.GetMatch)
+		api.GET("/matches/:id/events", middleware.OptionalAuth(), matchEventHandler.ListEvents)
+		api.GET("/matches/:id/play-by-play", middleware.OptionalAuth(), matchEventHandler.GetPlayByPlay)
+		api.GET("/matches/:id/game", middleware.OptionalAuth(), gameHandler.GetGame)
+		api.GET("/matches/:id/stream", middleware.StreamAuth(), liveHandler.MatchStream)
+		api.GET("/matches/:id/statistics", middleware.OptionalAuth(), statisticsHandler.GetMatchStatistics)
+		api.GET("/matches/:id/shot-chart", middleware.OptionalAuth(), statisticsHandler.GetMatchShotChart)
+		api.GET("/matches/:id/lineups", middleware.OptionalAuth(), statisticsHandler.GetMatchLineups)
+
+		// Public statistics routes
+		api.GET("/statistics/leaders", statisticsHandler.GetLeaders)
+		api.GET("/statistics/leaders/:stat", statisticsHandler.GetLeaders)
+
 		// Protected routes
 		protected := api.Group("")
 		protected.Use(middleware.AuthMiddleware())
@@ -71,6 +145,88 @@ func main() {
 			// Auth routes (protected)
 			protected.GET("/auth/me", authHandler.GetCurrentUser)
 
+			// Team management (org admins manage their own organization's teams)
+			teams := protected.Group("/teams")
+			teams.Use(middleware.RequireOrgAdmin())
+			{
+				teams.POST("", teamHandler.CreateTeam)
+				teams.PUT("/:id", teamHandler.UpdateTeam)
+				teams.DELETE("/:id", teamHandler.DeleteTeam)
+				teams.POST("/:id/players", playerHandler.CreateTeamPlayer)
+			}
+
+			// Tickets to open a private match's live feed from a browser
+			protected.POST("/matches/:id/stream-tickets", liveHandler.CreateStreamTicket)
+
+			// Players linked to a user account can see their own profile
+			protected.GET("/players/me", playerHandler.GetMyPlayer)
+
+			// Roster management (org admins manage their own organization's players)
+			players := protected.Group("/players")
+			players.Use(middleware.RequireOrgAdmin())
+			{
+				players.POST("", playerHandler.CreatePlayer)
+				players.PUT("/:id", playerHandler.UpdatePlayer)
+				players.DELETE("/:id", playerHandler.DeletePlayer)
+				players.POST("/:id/transfer", playerHandler.TransferPlayer)
+			}
+
+			// Tournament management (super admins, or admins of the owning organization)
+			tournaments := protected.Group("/tournaments")
+			tournaments.Use(middleware.RequireOrgAdmin())
+			{
+				tournaments.POST("", tournamentHandler.CreateTournament)
+				tournaments.PUT("/:id", tournamentHandler.UpdateTournament)
+				tournaments.DELETE("/:id", tournamentHandler.DeleteTournament)
+				tournaments.POST("/:id/teams", tournamentHandler.RegisterTeam)
+				tournaments.PUT("/:id/teams/:teamId", tournamentHandler.UpdateEntry)
+				tournaments.DELETE("/:id/teams/:teamId", tournamentHandler.WithdrawTeam)
+				tournaments.POST("/:id/standings/recompute", tournamentHandler.RecomputeStandings)
+				tournaments.POST("/:id/schedule/generate", tournamentHandler.GenerateSchedule)
+				tournaments.POST("/:id/bracket/generate", bracketHandler.GenerateBracket)
+				tournaments.DELETE("/:id/bracket", bracketHandler.DeleteBracket)
+				tournaments.POST("/:id/bracket/games/:gameId/match", bracketHandler.ScheduleMatch)
+			}
+
+			// Match scheduling (tournament managers, or admins of either team's organization)
+			matches := protected.Group("/matches")
+			matches.Use(middleware.RequireOrgAdmin())
+			{
+				matches.POST("", matchHandler.CreateMatch)
+				matches.PUT("/:id", matchHandler.UpdateMatch)
+				matches.DELETE("/:id", matchHandler.DeleteMatch)
+				matches.POST("/:id/reschedule", matchHandler.RescheduleMatch)
+				matches.POST("/:id/cancel", matchHandler.CancelMatch)
+				matches.GET("/:id/scorekeepers", matchEventHandler.ListScorekeepers)
+				matches.POST("/:id/scorekeepers", matchEventHandler.AssignScorekeeper)
+				matches.DELETE("/:id/scorekeepers/:userId", matchEventHandler.RemoveScorekeeper)
+			}
+
+			// Live scoring and game clock (match managers and assigned scorekeepers)
+			scoring := protected.Group("/matches")
+			{
+				scoring.POST("/:id/events", matchEventHandler.RecordEvent)
+				scoring.POST("/:id/events/undo", matchEventHandler.UndoEvent)
+				scoring.POST("/:id/game/periods/start", gameHandler.StartPeriod)
+				scoring.POST("/:id/game/periods/end", gameHandler.EndPeriod)
+				scoring.POST("/:id/game/clock/start", gameHandler.StartClock)
+				scoring.POST("/:id/game/clock/stop", gameHandler.StopClock)
+				scoring.PUT("/:id/game/clock", gameHandler.SetClock)
+			}
+
+			// Organization admin self-service
+			orgs := protected.Group("/orgs/:id")
+			orgs.Use(middleware.RequireOrganizationManager())
+			{
+				orgs.GET("", orgHandler.GetOrganization)
+				orgs.PUT("", orgHandler.UpdateOrganization)
+				orgs.GET("/statistics", orgHandler.GetStatistics)
+				orgs.GET("/members", orgHandler.ListMembers)
+				orgs.POST("/members", orgHandler.AddMember)
+				orgs.PUT("/members/:userId", orgHandler.UpdateMember)
+				orgs.DELETE("/members/:userId", orgHandler.RemoveMember)
+			}
+
 			// Admin routes
 			admin.SetupAdminRoutes(protected)
 		}
@@ -1,18 +1,24 @@
 package admin
 
 import (
+	"errors"
 	"log"
+	"net/http"
+	"strings"
 
-	"echo-golang/internal/config"
 	"echo-golang/internal/database"
 	"echo-golang/internal/middleware"
 	"echo-golang/internal/models"
+	"echo-golang/internal/services"
 	"echo-golang/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// minSearchLength is the shortest accepted user search query
+const minSearchLength = 2
+
 // SetupAdminRoutes sets up admin panel routes
 func SetupAdminRoutes(r *gin.RouterGroup) {
 	// Admin routes require authentication and admin role
@@ -25,6 +31,7 @@ func SetupAdminRoutes(r *gin.RouterGroup) {
 		
 		// User management
 		admin.GET("/users", GetUsers)
+		admin.GET("/users/search", SearchUsers)
 		admin.GET("/users/:id", GetUser)
 		admin.POST("/users", CreateUser)
 		admin.PUT("/users/:id", UpdateUser)
@@ -36,32 +43,80 @@ func SetupAdminRoutes(r *gin.RouterGroup) {
 		admin.POST("/organizations", CreateOrganization)
 		admin.PUT("/organizations/:id", UpdateOrganization)
 		admin.DELETE("/organizations/:id", DeleteOrganization)
+
+		// Data export
+		admin.GET("/export/users", ExportUsers)
+		admin.GET("/export/organizations", ExportOrganizations)
+		admin.GET("/export/teams", ExportTeams)
+
+		// Audit log
+		admin.GET("/audit", GetAuditEvents)
 	}
 }
 
 // AdminDashboard returns admin dashboard data
 func AdminDashboard(c *gin.Context) {
-	// Get statistics for dashboard
-	// This is a placeholder - implement actual statistics later
-	
-	utils.SuccessResponse(c, gin.H{
-		"total_users":         0,
-		"total_organizations":  0,
-		"total_teams":          0,
-		"total_matches":        0,
-		"active_matches":      0,
-	}, "Dashboard data retrieved")
+	stats, err := services.NewDashboardService().GetStats()
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch dashboard data")
+		return
+	}
+
+	utils.SuccessResponse(c, stats, "Dashboard data retrieved")
 }
 
 // GetUsers gets list of users (admin only)
 func GetUsers(c *gin.Context) {
-	// Placeholder - implement user listing
+	filters, err := userFilters(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	users, total, err := services.NewUserService().ListUsers(offset, limit, filters)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch users")
+		return
+	}
+
 	utils.SuccessResponse(c, gin.H{
-		"users": []models.User{},
-		"total": 0,
+		"users": users,
+		"total": total,
+		"page":  page,
+		"limit": limit,
 	}, "Users retrieved")
 }
 
+// SearchUsers finds users by partial name, email or phone
+func SearchUsers(c *gin.Context) {
+	term := strings.TrimSpace(c.Query("q"))
+	if len([]rune(term)) < minSearchLength {
+		utils.BadRequest(c, "Search query must be at least 2 characters", nil)
+		return
+	}
+
+	filters, err := userFilters(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	results, total, err := services.NewUserService().SearchUsers(term, offset, limit, filters)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to search users")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"results": results,
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	}, "Users found")
+}
+
 // GetUser gets a single user
 func GetUser(c *gin.Context) {
 	userID := c.Param("id")
@@ -71,8 +126,8 @@ func GetUser(c *gin.Context) {
 		return
 	}
 
-	var user models.User
-	if err := database.DB.Preload("Organization").Where("id = ?", id).First(&user).Error; err != nil {
+	user, err := services.NewUserService().GetUser(id)
+	if err != nil {
 		utils.NotFound(c, "User not found")
 		return
 	}
@@ -82,14 +137,58 @@ func GetUser(c *gin.Context) {
 
 // CreateUser creates a new user (admin only)
 func CreateUser(c *gin.Context) {
-	// Placeholder - implement user creation
-	utils.SuccessResponse(c, nil, "User created")
+	var req services.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	user, err := services.NewUserService().CreateUser(req, auditContext(c))
+	if err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyTaken) {
+			utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    user,
+		Message: "User created",
+	})
 }
 
 // UpdateUser updates a user
 func UpdateUser(c *gin.Context) {
-	// Placeholder - implement user update
-	utils.SuccessResponse(c, nil, "User updated")
+	userID := c.Param("id")
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID", nil)
+		return
+	}
+
+	var req services.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	user, err := services.NewUserService().UpdateUser(id, req, auditContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			utils.NotFound(c, "User not found")
+		case errors.Is(err, services.ErrEmailAlreadyTaken):
+			utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+		default:
+			utils.InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, user, "User updated")
 }
 
 // DeleteUser deletes a user
@@ -101,7 +200,11 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Delete(&models.User{}, id).Error; err != nil {
+	if err := services.NewUserService().DeleteUser(id, auditContext(c)); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			utils.NotFound(c, "User not found")
+			return
+		}
 		utils.InternalServerError(c, "Failed to delete user")
 		return
 	}
@@ -111,15 +214,24 @@ func DeleteUser(c *gin.Context) {
 
 // GetOrganizations gets list of organizations
 func GetOrganizations(c *gin.Context) {
-	var orgs []models.Organization
-	if err := database.DB.Preload("AdminUser").Find(&orgs).Error; err != nil {
+	filters, err := organizationFilters(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	orgs, total, err := services.NewOrganizationService().ListOrganizations(offset, limit, filters)
+	if err != nil {
 		utils.InternalServerError(c, "Failed to fetch organizations")
 		return
 	}
 
 	utils.SuccessResponse(c, gin.H{
 		"organizations": orgs,
-		"total":        len(orgs),
+		"total":         total,
+		"page":          page,
+		"limit":         limit,
 	}, "Organizations retrieved")
 }
 
@@ -132,8 +244,8 @@ func GetOrganization(c *gin.Context) {
 		return
 	}
 
-	var org models.Organization
-	if err := database.DB.Preload("AdminUser").Where("id = ?", id).First(&org).Error; err != nil {
+	org, err := services.NewOrganizationService().GetOrganization(id)
+	if err != nil {
 		utils.NotFound(c, "Organization not found")
 		return
 	}
@@ -143,14 +255,59 @@ func GetOrganization(c *gin.Context) {
 
 // CreateOrganization creates a new organization
 func CreateOrganization(c *gin.Context) {
-	// Placeholder - implement organization creation
-	utils.SuccessResponse(c, nil, "Organization created")
+	var req services.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	org, err := services.NewOrganizationService().CreateOrganization(req, auditContext(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidOrgAdmin) {
+			utils.BadRequest(c, err.Error(), nil)
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    org,
+		Message: "Organization created",
+	})
 }
 
 // UpdateOrganization updates an organization
 func UpdateOrganization(c *gin.Context) {
-	// Placeholder - implement organization update
-	utils.SuccessResponse(c, nil, "Organization updated")
+	orgID := c.Param("id")
+	id, err := uuid.Parse(orgID)
+	if err != nil {
+		utils.BadRequest(c, "Invalid organization ID", nil)
+		return
+	}
+
+	var req services.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	org, err := services.NewOrganizationService().UpdateOrganization(id, req, auditContext(c))
+	if err != nil {
+		if errors.Is(err, services.ErrOrganizationNotFound) {
+			utils.NotFound(c, "Organization not found")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidOrgAdmin) {
+			utils.BadRequest(c, err.Error(), nil)
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, org, "Organization updated")
 }
 
 // DeleteOrganization deletes an organization
@@ -162,7 +319,11 @@ func DeleteOrganization(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Delete(&models.Organization{}, id).Error; err != nil {
+	if err := services.NewOrganizationService().DeleteOrganization(id, auditContext(c)); err != nil {
+		if errors.Is(err, services.ErrOrganizationNotFound) {
+			utils.NotFound(c, "Organization not found")
+			return
+		}
 		utils.InternalServerError(c, "Failed to delete organization")
 		return
 	}
@@ -170,6 +331,35 @@ func DeleteOrganization(c *gin.Context) {
 	utils.SuccessResponse(c, nil, "Organization deleted successfully")
 }
 
+// GetAuditEvents lists audit events, filtered by actor, target and time range
+func GetAuditEvents(c *gin.Context) {
+	filters, err := auditFilters(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	events, total, err := services.NewAuditService().List(offset, limit, filters)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch audit events")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"events": events,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	}, "Audit events retrieved")
+}
+
+// auditContext builds the audit context for the current request
+func auditContext(c *gin.Context) services.AuditContext {
+	user, _ := middleware.GetUserFromContext(c)
+	return services.NewAuditContext(user, c.ClientIP(), middleware.GetRequestIDFromContext(c))
+}
+
 // CreateDefaultAdmin creates a default admin user if it doesn't exist
 func CreateDefaultAdmin() error {
 	var count int64
@@ -0,0 +1,653 @@
+package admin
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/models"
+	"echo-golang/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+// consolePath is where the HTML admin console is mounted
+const consolePath = "/admin"
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed static
+var staticFS embed.FS
+
+var consoleTemplates = parseConsoleTemplates()
+
+var consoleFuncs = template.FuncMap{
+	"formatTime": func(t interface{}) string {
+		switch v := t.(type) {
+		case time.Time:
+			return v.Format("2006-01-02 15:04")
+		case *time.Time:
+			if v != nil {
+				return v.Format("2006-01-02 15:04")
+			}
+		}
+		return "—"
+	},
+	"uuidString": func(id *uuid.UUID) string {
+		if id == nil {
+			return ""
+		}
+		return id.String()
+	},
+	"userRoles": func() []models.UserRole {
+		return []models.UserRole{models.RoleSuperAdmin, models.RoleOrgAdmin, models.RoleTeamMember, models.RolePublic}
+	},
+}
+
+// parseConsoleTemplates parses every page together with the shared layout
+func parseConsoleTemplates() map[string]*template.Template {
+	pages, err := fs.Glob(templateFS, "templates/*.html")
+	if err != nil {
+		panic(err)
+	}
+
+	templates := map[string]*template.Template{}
+	for _, page := range pages {
+		if page == "templates/layout.html" {
+			continue
+		}
+		name := page[len("templates/"):]
+		templates[name] = template.Must(template.New(name).Funcs(consoleFuncs).
+			ParseFS(templateFS, "templates/layout.html", page))
+	}
+	return templates
+}
+
+// consoleNotices maps the notice query parameter set after redirects to a message
+var consoleNotices = map[string]string{
+	"created":    "Record created.",
+	"updated":    "Record updated.",
+	"deleted":    "Record deleted.",
+	"logged_out": "You have been logged out.",
+}
+
+// SetupConsoleRoutes sets up the server-rendered admin console
+func SetupConsoleRoutes(r *gin.Engine) {
+	static, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	console := r.Group(consolePath)
+	console.Use(VerifyCSRF())
+	{
+		console.StaticFS("/static", http.FS(static))
+		console.GET("/login", ConsoleLoginPage)
+		console.POST("/login", ConsoleLogin)
+
+		authed := console.Group("")
+		authed.Use(ConsoleAuth())
+		{
+			authed.POST("/logout", ConsoleLogout)
+			authed.GET("", ConsoleDashboard)
+
+			authed.GET("/users", ConsoleUsers)
+			authed.GET("/users/new", ConsoleNewUser)
+			authed.POST("/users", ConsoleCreateUser)
+			authed.GET("/users/:id", ConsoleEditUser)
+			authed.POST("/users/:id", ConsoleUpdateUser)
+			authed.POST("/users/:id/delete", ConsoleDeleteUser)
+
+			authed.GET("/organizations", ConsoleOrganizations)
+			authed.GET("/organizations/new", ConsoleNewOrganization)
+			authed.POST("/organizations", ConsoleCreateOrganization)
+			authed.GET("/organizations/:id", ConsoleEditOrganization)
+			authed.POST("/organizations/:id", ConsoleUpdateOrganization)
+			authed.POST("/organizations/:id/delete", ConsoleDeleteOrganization)
+
+			authed.GET("/audit", ConsoleAudit)
+		}
+	}
+}
+
+// ConsoleAuth loads the super admin from the session cookie or redirects to the login page
+func ConsoleAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, err := readSessionCookie(c)
+		if err != nil {
+			c.Redirect(http.StatusSeeOther, consolePath+"/login")
+			c.Abort()
+			return
+		}
+
+		user, err := services.NewUserService().GetUser(session.UserID)
+		if err != nil || !user.IsActive() || !user.IsAdmin() {
+			clearSessionCookie(c)
+			c.Redirect(http.StatusSeeOther, consolePath+"/login")
+			c.Abort()
+			return
+		}
+
+		// Mirror AuthMiddleware so shared helpers work for console requests
+		c.Set("user_id", user.ID)
+		c.Set("user_email", user.Email)
+		c.Set("user_role", user.Role)
+		c.Set("organization_id", user.OrganizationID)
+		c.Set("user", user)
+
+		c.Next()
+	}
+}
+
+// ConsoleLoginPage renders the login form
+func ConsoleLoginPage(c *gin.Context) {
+	if _, err := readSessionCookie(c); err == nil {
+		c.Redirect(http.StatusSeeOther, consolePath)
+		return
+	}
+	renderConsole(c, http.StatusOK, "login.html", gin.H{"Title": "Log in"})
+}
+
+// ConsoleLogin authenticates a super admin and starts a console session
+func ConsoleLogin(c *gin.Context) {
+	req := services.LoginRequest{
+		Email:    c.PostForm("email"),
+		Password: c.PostForm("password"),
+	}
+	data := gin.H{"Title": "Log in", "Email": req.Email}
+
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		data["Error"] = "Enter a valid email and password."
+		renderConsole(c, http.StatusBadRequest, "login.html", data)
+		return
+	}
+
+	user, err := services.NewAuthService().ConsoleLogin(req, auditContext(c))
+	if errors.Is(err, services.ErrNotConsoleAdmin) {
+		data["Error"] = "Only super admins can use the admin console."
+		renderConsole(c, http.StatusForbidden, "login.html", data)
+		return
+	} else if err != nil {
+		data["Error"] = "Invalid email or password."
+		renderConsole(c, http.StatusUnauthorized, "login.html", data)
+		return
+	}
+
+	if err := setSessionCookie(c, user.ID); err != nil {
+		data["Error"] = "Failed to start session."
+		renderConsole(c, http.StatusInternalServerError, "login.html", data)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, consolePath)
+}
+
+// ConsoleLogout ends the console session
+func ConsoleLogout(c *gin.Context) {
+	clearSessionCookie(c)
+	c.Redirect(http.StatusSeeOther, consolePath+"/login?notice=logged_out")
+}
+
+// ConsoleDashboard renders dashboard statistics
+func ConsoleDashboard(c *gin.Context) {
+	stats, err := services.NewDashboardService().GetStats()
+	if err != nil {
+		renderConsoleError(c, http.StatusInternalServerError, "Failed to load dashboard statistics.")
+		return
+	}
+	renderConsole(c, http.StatusOK, "dashboard.html", gin.H{"Title": "Dashboard", "Stats": stats})
+}
+
+// ConsoleUsers renders the user list
+func ConsoleUsers(c *gin.Context) {
+	filters, err := userFilters(c)
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, limit, offset := consolePagination(c)
+	userService := services.NewUserService()
+
+	var users []models.User
+	var total int64
+	if term := strings.TrimSpace(c.Query("q")); len([]rune(term)) >= minSearchLength {
+		var results []services.UserSearchResult
+		results, total, err = userService.SearchUsers(term, offset, limit, filters)
+		for _, result := range results {
+			users = append(users, result.User)
+		}
+	} else {
+		users, total, err = userService.ListUsers(offset, limit, filters)
+	}
+	if err != nil {
+		renderConsoleError(c, http.StatusInternalServerError, "Failed to load users.")
+		return
+	}
+
+	renderConsole(c, http.StatusOK, "users.html", gin.H{
+		"Title":      "Users",
+		"Users":      users,
+		"Filters":    c.Request.URL.Query(),
+		"Pagination": newConsolePagination(c, page, limit, total),
+	})
+}
+
+// ConsoleNewUser renders an empty user form
+func ConsoleNewUser(c *gin.Context) {
+	renderConsole(c, http.StatusOK, "user_form.html", gin.H{
+		"Title":  "New user",
+		"Action": consolePath + "/users",
+		"User":   &models.User{Role: models.RolePublic, Status: models.UserStatusActive},
+	})
+}
+
+// ConsoleCreateUser creates a user from the submitted form
+func ConsoleCreateUser(c *gin.Context) {
+	req := services.CreateUserRequest{
+		Email:    c.PostForm("email"),
+		Password: c.PostForm("password"),
+		FullName: c.PostForm("full_name"),
+		Role:     c.PostForm("role"),
+		Phone:    c.PostForm("phone"),
+		Status:   c.PostForm("status"),
+	}
+	orgID, err := formUUID(c, "organization_id")
+
+	formUser := &models.User{
+		Email:          req.Email,
+		FullName:       req.FullName,
+		Role:           models.UserRole(req.Role),
+		OrganizationID: orgID,
+		Phone:          req.Phone,
+		Status:         models.UserStatus(req.Status),
+	}
+	data := gin.H{"Title": "New user", "Action": consolePath + "/users", "User": formUser}
+
+	if err != nil {
+		data["Error"] = err.Error()
+		renderConsole(c, http.StatusBadRequest, "user_form.html", data)
+		return
+	}
+	req.OrganizationID = orgID
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		data["Error"] = "Please check the form: " + err.Error()
+		renderConsole(c, http.StatusBadRequest, "user_form.html", data)
+		return
+	}
+
+	if _, err := services.NewUserService().CreateUser(req, auditContext(c)); err != nil {
+		data["Error"] = err.Error()
+		renderConsole(c, http.StatusBadRequest, "user_form.html", data)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, consolePath+"/users?notice=created")
+}
+
+// ConsoleEditUser renders the edit form for a user
+func ConsoleEditUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, "Invalid user ID.")
+		return
+	}
+
+	user, err := services.NewUserService().GetUser(id)
+	if err != nil {
+		renderConsoleError(c, http.StatusNotFound, "User not found.")
+		return
+	}
+
+	renderConsole(c, http.StatusOK, "user_form.html", gin.H{
+		"Title":   "Edit user",
+		"Editing": true,
+		"Action":  consolePath + "/users/" + user.ID.String(),
+		"User":    user,
+	})
+}
+
+// ConsoleUpdateUser updates a user from the submitted form
+func ConsoleUpdateUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, "Invalid user ID.")
+		return
+	}
+
+	userService := services.NewUserService()
+	user, err := userService.GetUser(id)
+	if err != nil {
+		renderConsoleError(c, http.StatusNotFound, "User not found.")
+		return
+	}
+	data := gin.H{"Title": "Edit user", "Editing": true, "Action": consolePath + "/users/" + id.String(), "User": user}
+
+	req := services.UpdateUserRequest{
+		Email:    formNonEmpty(c, "email"),
+		FullName: formString(c, "full_name"),
+		Role:     formNonEmpty(c, "role"),
+		Phone:    formString(c, "phone"),
+		Status:   formNonEmpty(c, "status"),
+	}
+	if password := c.PostForm("password"); password != "" {
+		req.Password = &password
+	}
+	if req.OrganizationID, err = formUUID(c, "organization_id"); err != nil {
+		data["Error"] = err.Error()
+		renderConsole(c, http.StatusBadRequest, "user_form.html", data)
+		return
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		data["Error"] = "Please check the form: " + err.Error()
+		renderConsole(c, http.StatusBadRequest, "user_form.html", data)
+		return
+	}
+
+	if _, err := userService.UpdateUser(id, req, auditContext(c)); err != nil {
+		data["Error"] = err.Error()
+		renderConsole(c, http.StatusBadRequest, "user_form.html", data)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, consolePath+"/users?notice=updated")
+}
+
+// ConsoleDeleteUser deletes a user
+func ConsoleDeleteUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, "Invalid user ID.")
+		return
+	}
+
+	if err := services.NewUserService().DeleteUser(id, auditContext(c)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		renderConsoleError(c, status, err.Error())
+		return
+	}
+	c.Redirect(http.StatusSeeOther, consolePath+"/users?notice=deleted")
+}
+
+// ConsoleOrganizations renders the organization list
+func ConsoleOrganizations(c *gin.Context) {
+	filters, err := organizationFilters(c)
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, limit, offset := consolePagination(c)
+	orgs, total, err := services.NewOrganizationService().ListOrganizations(offset, limit, filters)
+	if err != nil {
+		renderConsoleError(c, http.StatusInternalServerError, "Failed to load organizations.")
+		return
+	}
+
+	renderConsole(c, http.StatusOK, "organizations.html", gin.H{
+		"Title":         "Organizations",
+		"Organizations": orgs,
+		"Filters":       c.Request.URL.Query(),
+		"Pagination":    newConsolePagination(c, page, limit, total),
+	})
+}
+
+// ConsoleNewOrganization renders an empty organization form
+func ConsoleNewOrganization(c *gin.Context) {
+	renderConsole(c, http.StatusOK, "organization_form.html", gin.H{
+		"Title":        "New organization",
+		"Action":       consolePath + "/organizations",
+		"Organization": &models.Organization{Status: models.OrgStatusActive},
+	})
+}
+
+// ConsoleCreateOrganization creates an organization from the submitted form
+func ConsoleCreateOrganization(c *gin.Context) {
+	req := services.CreateOrganizationRequest{
+		Name:    c.PostForm("name"),
+		Email:   c.PostForm("email"),
+		Phone:   c.PostForm("phone"),
+		Address: c.PostForm("address"),
+		LogoURL: c.PostForm("logo_url"),
+		Status:  c.PostForm("status"),
+	}
+	adminUserID, err := formUUID(c, "admin_user_id")
+
+	formOrg := &models.Organization{
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		LogoURL:     req.LogoURL,
+		AdminUserID: adminUserID,
+		Status:      models.OrganizationStatus(req.Status),
+	}
+	data := gin.H{"Title": "New organization", "Action": consolePath + "/organizations", "Organization": formOrg}
+
+	if err != nil {
+		data["Error"] = err.Error()
+		renderConsole(c, http.StatusBadRequest, "organization_form.html", data)
+		return
+	}
+	req.AdminUserID = adminUserID
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		data["Error"] = "Please check the form: " + err.Error()
+		renderConsole(c, http.StatusBadRequest, "organization_form.html", data)
+		return
+	}
+
+	if _, err := services.NewOrganizationService().CreateOrganization(req, auditContext(c)); err != nil {
+		data["Error"] = err.Error()
+		renderConsole(c, http.StatusBadRequest, "organization_form.html", data)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, consolePath+"/organizations?notice=created")
+}
+
+// ConsoleEditOrganization renders the edit form for an organization
+func ConsoleEditOrganization(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, "Invalid organization ID.")
+		return
+	}
+
+	org, err := services.NewOrganizationService().GetOrganization(id)
+	if err != nil {
+		renderConsoleError(c, http.StatusNotFound, "Organization not found.")
+		return
+	}
+
+	renderConsole(c, http.StatusOK, "organization_form.html", gin.H{
+		"Title":        "Edit organization",
+		"Editing":      true,
+		"Action":       consolePath + "/organizations/" + org.ID.String(),
+		"Organization": org,
+	})
+}
+
+// ConsoleUpdateOrganization updates an organization from the submitted form
+func ConsoleUpdateOrganization(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, "Invalid organization ID.")
+		return
+	}
+
+	orgService := services.NewOrganizationService()
+	org, err := orgService.GetOrganization(id)
+	if err != nil {
+		renderConsoleError(c, http.StatusNotFound, "Organization not found.")
+		return
+	}
+	data := gin.H{"Title": "Edit organization", "Editing": true, "Action": consolePath + "/organizations/" + id.String(), "Organization": org}
+
+	req := services.UpdateOrganizationRequest{
+		Name:    formNonEmpty(c, "name"),
+		Email:   formNonEmpty(c, "email"),
+		Phone:   formString(c, "phone"),
+		Address: formString(c, "address"),
+		LogoURL: formString(c, "logo_url"),
+		Status:  formNonEmpty(c, "status"),
+	}
+	if req.AdminUserID, err = formUUID(c, "admin_user_id"); err != nil {
+		data["Error"] = err.Error()
+		renderConsole(c, http.StatusBadRequest, "organization_form.html", data)
+		return
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		data["Error"] = "Please check the form: " + err.Error()
+		renderConsole(c, http.StatusBadRequest, "organization_form.html", data)
+		return
+	}
+
+	if _, err := orgService.UpdateOrganization(id, req, auditContext(c)); err != nil {
+		data["Error"] = err.Error()
+		renderConsole(c, http.StatusBadRequest, "organization_form.html", data)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, consolePath+"/organizations?notice=updated")
+}
+
+// ConsoleDeleteOrganization deletes an organization
+func ConsoleDeleteOrganization(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, "Invalid organization ID.")
+		return
+	}
+
+	if err := services.NewOrganizationService().DeleteOrganization(id, auditContext(c)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrOrganizationNotFound) {
+			status = http.StatusNotFound
+		}
+		renderConsoleError(c, status, err.Error())
+		return
+	}
+	c.Redirect(http.StatusSeeOther, consolePath+"/organizations?notice=deleted")
+}
+
+// ConsoleAudit renders the audit log
+func ConsoleAudit(c *gin.Context) {
+	filters, err := auditFilters(c)
+	if err != nil {
+		renderConsoleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, limit, offset := consolePagination(c)
+	events, total, err := services.NewAuditService().List(offset, limit, filters)
+	if err != nil {
+		renderConsoleError(c, http.StatusInternalServerError, "Failed to load audit events.")
+		return
+	}
+
+	renderConsole(c, http.StatusOK, "audit.html", gin.H{
+		"Title":      "Audit log",
+		"Events":     events,
+		"Filters":    c.Request.URL.Query(),
+		"Pagination": newConsolePagination(c, page, limit, total),
+	})
+}
+
+// renderConsole renders a console page inside the shared layout
+func renderConsole(c *gin.Context, status int, page string, data gin.H) {
+	tmpl, ok := consoleTemplates[page]
+	if !ok {
+		c.String(http.StatusInternalServerError, "Unknown page")
+		return
+	}
+
+	data["BasePath"] = consolePath
+	data["CSRFToken"] = csrfToken(c)
+	if user, ok := middleware.GetUserFromContext(c); ok {
+		data["CurrentUser"] = user
+	}
+	if _, ok := data["Notice"]; !ok {
+		data["Notice"] = consoleNotices[c.Query("notice")]
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+		log.Printf("Failed to render admin console page %s: %v", page, err)
+		c.String(http.StatusInternalServerError, "Failed to render page")
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func renderConsoleError(c *gin.Context, status int, message string) {
+	renderConsole(c, status, "error.html", gin.H{"Title": "Error", "Error": message})
+}
+
+// consolePagination uses larger pages than the JSON API defaults
+func consolePagination(c *gin.Context) (int, int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := 25
+	return page, limit, (page - 1) * limit
+}
+
+// newConsolePagination builds previous/next links that keep the current filters
+func newConsolePagination(c *gin.Context, page, limit int, total int64) gin.H {
+	link := func(p int) string {
+		query := c.Request.URL.Query()
+		query.Del("notice")
+		query.Set("page", strconv.Itoa(p))
+		return c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	pagination := gin.H{"Page": page, "Total": total}
+	if page > 1 {
+		pagination["PrevURL"] = link(page - 1)
+	}
+	if int64(page*limit) < total {
+		pagination["NextURL"] = link(page + 1)
+	}
+	return pagination
+}
+
+// formString returns a pointer to a submitted form value, or nil if the field is absent
+func formString(c *gin.Context, key string) *string {
+	value, ok := c.GetPostForm(key)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// formNonEmpty is like formString but treats an empty value as absent
+func formNonEmpty(c *gin.Context, key string) *string {
+	value := c.PostForm(key)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// formUUID parses an optional UUID form field
+func formUUID(c *gin.Context, key string) (*uuid.UUID, error) {
+	value := c.PostForm(key)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, errors.New("invalid " + key)
+	}
+	return &id, nil
+}
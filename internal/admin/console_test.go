@@ -0,0 +1,174 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// withConsoleDB points the database at an empty in-memory SQLite database
+// with the tables the console's sign-in uses
+func withConsoleDB(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // Every connection would get its own database
+	if err := db.AutoMigrate(&models.Organization{}, &models.User{}, &models.AuditEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	saved := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = saved
+		sqlDB.Close()
+	})
+}
+
+// consoleUser creates a user who signs in with the password "secret-password"
+func consoleUser(t *testing.T, role models.UserRole, status models.UserStatus) *models.User {
+	t.Helper()
+	hash, err := utils.HashPassword("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{Email: uuid.NewString() + "@example.com", Password: hash, FullName: "Sam Lee",
+		Role: role, Status: status}
+	if err := database.DB.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestConsoleAuth(t *testing.T) {
+	withConsoleConfig(t)
+	withConsoleDB(t)
+	r := gin.New()
+	r.GET(consolePath, ConsoleAuth(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	admin := consoleUser(t, models.RoleSuperAdmin, models.UserStatusActive)
+	deactivated := consoleUser(t, models.RoleSuperAdmin, models.UserStatusActive)
+	demoted := consoleUser(t, models.RoleSuperAdmin, models.UserStatusActive)
+	deleted := consoleUser(t, models.RoleSuperAdmin, models.UserStatusActive)
+	session := func(user *models.User) string {
+		return sessionValue(t, consoleSession{UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	}
+	sessions := map[*models.User]string{}
+	for _, user := range []*models.User{admin, deactivated, demoted, deleted} {
+		sessions[user] = session(user)
+	}
+
+	// The sessions were issued before these changes
+	if err := database.DB.Model(deactivated).Update("status", models.UserStatusInactive).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DB.Model(demoted).Update("role", models.RoleOrgAdmin).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DB.Delete(deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		session string // Empty for no cookie
+		want    int
+		cleared bool
+	}{
+		{"active super admin", sessions[admin], http.StatusOK, false},
+		{"no session", "", http.StatusSeeOther, false},
+		{"deactivated since", sessions[deactivated], http.StatusSeeOther, true},
+		{"demoted since", sessions[demoted], http.StatusSeeOther, true},
+		{"deleted since", sessions[deleted], http.StatusSeeOther, true},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, consolePath, nil)
+		if c.session != "" {
+			req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: c.session})
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, w.Code, c.want)
+		}
+		if c.want == http.StatusSeeOther && w.Header().Get("Location") != consolePath+"/login" {
+			t.Errorf("%s: redirected to %q, want the login page", c.name, w.Header().Get("Location"))
+		}
+		cleared := false
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == sessionCookieName && cookie.MaxAge < 0 {
+				cleared = true
+			}
+		}
+		if cleared != c.cleared {
+			t.Errorf("%s: session cookie cleared is %v, want %v", c.name, cleared, c.cleared)
+		}
+	}
+}
+
+func TestConsoleLogin(t *testing.T) {
+	withConsoleConfig(t)
+	withConsoleDB(t)
+	r := gin.New()
+	r.POST(consolePath+"/login", ConsoleLogin)
+
+	cases := []struct {
+		name     string
+		role     models.UserRole
+		password string
+		want     int
+		action   models.AuditAction
+	}{
+		{"super admin", models.RoleSuperAdmin, "secret-password", http.StatusSeeOther, models.AuditActionConsoleLoginSuccess},
+		{"wrong password", models.RoleSuperAdmin, "wrong-password", http.StatusUnauthorized, models.AuditActionConsoleLoginFailure},
+		{"org admin", models.RoleOrgAdmin, "secret-password", http.StatusForbidden, models.AuditActionConsoleLoginFailure},
+		{"org admin with a wrong password", models.RoleOrgAdmin, "wrong-password", http.StatusUnauthorized, models.AuditActionConsoleLoginFailure},
+	}
+	for _, c := range cases {
+		account := consoleUser(t, c.role, models.UserStatusActive)
+		form := url.Values{"email": {account.Email}, "password": {c.password}}
+		req := httptest.NewRequest(http.MethodPost, consolePath+"/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, w.Code, c.want)
+		}
+
+		var events []models.AuditEvent
+		if err := database.DB.Where("target_id = ?", account.ID).Find(&events).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 || events[0].Action != c.action {
+			t.Errorf("%s: got audit events %v, want one %s", c.name, events, c.action)
+		}
+		var user models.User
+		if err := database.DB.First(&user, "id = ?", account.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if loggedIn := c.want == http.StatusSeeOther; (user.LastLoginAt != nil) != loggedIn {
+			t.Errorf("%s: last login %v, want it set only on success", c.name, user.LastLoginAt)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package admin
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+
+	// exportBatchSize is the number of rows loaded from the database at a time
+	exportBatchSize = 500
+)
+
+// exportRecord is a single exported row, without any sensitive fields
+type exportRecord interface {
+	csvRow() []string
+}
+
+// recordWriter writes export records in a specific format
+type recordWriter interface {
+	Write(record exportRecord) error
+	Flush() error
+}
+
+type csvRecordWriter struct {
+	w *csv.Writer
+}
+
+func newCSVRecordWriter(w io.Writer, headers []string) (*csvRecordWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(headers); err != nil {
+		return nil, err
+	}
+	return &csvRecordWriter{w: cw}, nil
+}
+
+func (w *csvRecordWriter) Write(record exportRecord) error {
+	return w.w.Write(record.csvRow())
+}
+
+func (w *csvRecordWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type ndjsonRecordWriter struct {
+	enc *json.Encoder
+}
+
+func newNDJSONRecordWriter(w io.Writer) *ndjsonRecordWriter {
+	return &ndjsonRecordWriter{enc: json.NewEncoder(w)}
+}
+
+func (w *ndjsonRecordWriter) Write(record exportRecord) error {
+	return w.enc.Encode(record)
+}
+
+func (w *ndjsonRecordWriter) Flush() error {
+	return nil
+}
+
+// userExportRecord is the exported shape of a user
+type userExportRecord struct {
+	ID             uuid.UUID  `json:"id"`
+	Email          string     `json:"email"`
+	FullName       string     `json:"full_name"`
+	Phone          string     `json:"phone"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"`
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	LastLoginAt    *time.Time `json:"last_login_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+var userExportHeaders = []string{
+	"id", "email", "full_name", "phone", "role", "status",
+	"organization_id", "last_login_at", "created_at", "updated_at",
+}
+
+func newUserExportRecord(u *models.User) userExportRecord {
+	return userExportRecord{
+		ID:             u.ID,
+		Email:          u.Email,
+		FullName:       u.FullName,
+		Phone:          u.Phone,
+		Role:           string(u.Role),
+		Status:         string(u.Status),
+		OrganizationID: u.OrganizationID,
+		LastLoginAt:    u.LastLoginAt,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
+	}
+}
+
+func (r userExportRecord) csvRow() []string {
+	return []string{
+		r.ID.String(), r.Email, r.FullName, r.Phone, r.Role, r.Status,
+		formatUUID(r.OrganizationID), formatTime(r.LastLoginAt),
+		r.CreatedAt.Format(time.RFC3339), r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// organizationExportRecord is the exported shape of an organization
+type organizationExportRecord struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Phone       string     `json:"phone"`
+	Address     string     `json:"address"`
+	LogoURL     string     `json:"logo_url"`
+	AdminUserID *uuid.UUID `json:"admin_user_id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+var organizationExportHeaders = []string{
+	"id", "name", "email", "phone", "address", "logo_url",
+	"admin_user_id", "status", "created_at", "updated_at",
+}
+
+func newOrganizationExportRecord(o *models.Organization) organizationExportRecord {
+	return organizationExportRecord{
+		ID:          o.ID,
+		Name:        o.Name,
+		Email:       o.Email,
+		Phone:       o.Phone,
+		Address:     o.Address,
+		LogoURL:     o.LogoURL,
+		AdminUserID: o.AdminUserID,
+		Status:      string(o.Status),
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+	}
+}
+
+func (r organizationExportRecord) csvRow() []string {
+	return []string{
+		r.ID.String(), r.Name, r.Email, r.Phone, r.Address, r.LogoURL,
+		formatUUID(r.AdminUserID), r.Status,
+		r.CreatedAt.Format(time.RFC3339), r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// teamExportRecord is the exported shape of a team
+type teamExportRecord struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+	LogoURL        string    `json:"logo_url"`
+	CoachName      string    `json:"coach_name"`
+	CoachPhone     string    `json:"coach_phone"`
+	CoachEmail     string    `json:"coach_email"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+var teamExportHeaders = []string{
+	"id", "organization_id", "name", "logo_url", "coach_name", "coach_phone",
+	"coach_email", "status", "created_at", "updated_at",
+}
+
+func newTeamExportRecord(t *models.Team) teamExportRecord {
+	return teamExportRecord{
+		ID:             t.ID,
+		OrganizationID: t.OrganizationID,
+		Name:           t.Name,
+		LogoURL:        t.LogoURL,
+		CoachName:      t.CoachName,
+		CoachPhone:     t.CoachPhone,
+		CoachEmail:     t.CoachEmail,
+		Status:         string(t.Status),
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
+
+func (r teamExportRecord) csvRow() []string {
+	return []string{
+		r.ID.String(), r.OrganizationID.String(), r.Name, r.LogoURL, r.CoachName, r.CoachPhone,
+		r.CoachEmail, r.Status,
+		r.CreatedAt.Format(time.RFC3339), r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ExportUsers streams users matching the listing filters as CSV or NDJSON
+func ExportUsers(c *gin.Context) {
+	filters, err := userFilters(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	repo := repositories.NewUserRepository()
+	streamExport(c, "users", userExportHeaders, func(write func(exportRecord) error) error {
+		return repo.Each(exportBatchSize, filters, func(u *models.User) error {
+			return write(newUserExportRecord(u))
+		})
+	})
+}
+
+// ExportOrganizations streams organizations matching the listing filters as CSV or NDJSON
+func ExportOrganizations(c *gin.Context) {
+	filters, err := organizationFilters(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	repo := repositories.NewOrganizationRepository()
+	streamExport(c, "organizations", organizationExportHeaders, func(write func(exportRecord) error) error {
+		return repo.Each(exportBatchSize, filters, func(o *models.Organization) error {
+			return write(newOrganizationExportRecord(o))
+		})
+	})
+}
+
+// ExportTeams streams teams matching the listing filters as CSV or NDJSON
+func ExportTeams(c *gin.Context) {
+	filters, err := teamFilters(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	repo := repositories.NewTeamRepository()
+	streamExport(c, "teams", teamExportHeaders, func(write func(exportRecord) error) error {
+		return repo.Each(exportBatchSize, filters, func(t *models.Team) error {
+			return write(newTeamExportRecord(t))
+		})
+	})
+}
+
+// streamExport writes the records produced by source to the response in the
+// requested format, flushing after every batch so rows reach the client as
+// they are read
+func streamExport(c *gin.Context, name string, headers []string, source func(write func(exportRecord) error) error) {
+	format := c.DefaultQuery("format", exportFormatCSV)
+
+	var contentType string
+	switch format {
+	case exportFormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case exportFormatNDJSON:
+		contentType = "application/x-ndjson"
+	default:
+		utils.BadRequest(c, "Invalid export format, expected csv or ndjson", nil)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	var writer recordWriter
+	if format == exportFormatCSV {
+		w, err := newCSVRecordWriter(c.Writer, headers)
+		if err != nil {
+			log.Printf("Export %s failed: %v", name, err)
+			return
+		}
+		writer = w
+	} else {
+		writer = newNDJSONRecordWriter(c.Writer)
+	}
+
+	rows := 0
+	err := source(func(record exportRecord) error {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportBatchSize == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		// Headers are already sent, so the best we can do is stop the stream
+		log.Printf("Export %s failed after %d rows: %v", name, rows, err)
+		return
+	}
+	c.Writer.Flush()
+}
+
+func formatUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"errors"
+	"time"
+
+	"echo-golang/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// userFilters builds user listing filters from query parameters
+func userFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := map[string]interface{}{}
+
+	if role := c.Query("role"); role != "" {
+		switch models.UserRole(role) {
+		case models.RoleSuperAdmin, models.RoleOrgAdmin, models.RoleTeamMember, models.RolePublic:
+			filters["role"] = role
+		default:
+			return nil, errors.New("invalid role filter")
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		switch models.UserStatus(status) {
+		case models.UserStatusActive, models.UserStatusInactive:
+			filters["status"] = status
+		default:
+			return nil, errors.New("invalid status filter")
+		}
+	}
+	if orgID := c.Query("organization_id"); orgID != "" {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			return nil, errors.New("invalid organization ID filter")
+		}
+		filters["organization_id"] = id
+	}
+
+	return filters, nil
+}
+
+// organizationFilters builds organization listing filters from query parameters
+func organizationFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := map[string]interface{}{}
+
+	if status := c.Query("status"); status != "" {
+		switch models.OrganizationStatus(status) {
+		case models.OrgStatusActive, models.OrgStatusInactive:
+			filters["status"] = status
+		default:
+			return nil, errors.New("invalid status filter")
+		}
+	}
+
+	return filters, nil
+}
+
+// teamFilters builds team listing filters from query parameters
+func teamFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := map[string]interface{}{}
+
+	if orgID := c.Query("organization_id"); orgID != "" {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			return nil, errors.New("invalid organization ID filter")
+		}
+		filters["organization_id"] = id
+	}
+	if status := c.Query("status"); status != "" {
+		switch models.TeamStatus(status) {
+		case models.TeamStatusActive, models.TeamStatusInactive:
+			filters["status"] = status
+		default:
+			return nil, errors.New("invalid status filter")
+		}
+	}
+
+	return filters, nil
+}
+
+// auditFilters builds audit log filters from query parameters
+func auditFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := map[string]interface{}{}
+
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := uuid.Parse(actorID)
+		if err != nil {
+			return nil, errors.New("invalid actor ID filter")
+		}
+		filters["actor_id"] = id
+	}
+	if action := c.Query("action"); action != "" {
+		filters["action"] = action
+	}
+	if targetType := c.Query("target_type"); targetType != "" {
+		filters["target_type"] = targetType
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		id, err := uuid.Parse(targetID)
+		if err != nil {
+			return nil, errors.New("invalid target ID filter")
+		}
+		filters["target_id"] = id
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := parseTimeFilter(from)
+		if err != nil {
+			return nil, errors.New("invalid from filter, expected RFC3339 or YYYY-MM-DD")
+		}
+		filters["from"] = t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := parseTimeFilter(to)
+		if err != nil {
+			return nil, errors.New("invalid to filter, expected RFC3339 or YYYY-MM-DD")
+		}
+		// A bare date includes the whole day
+		if len(to) == len("2006-01-02") {
+			t = t.AddDate(0, 0, 1)
+		}
+		filters["to"] = t
+	}
+
+	return filters, nil
+}
+
+// parseTimeFilter parses an RFC3339 timestamp or a plain date
+func parseTimeFilter(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
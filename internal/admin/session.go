@@ -0,0 +1,129 @@
+package admin
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"echo-golang/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	sessionCookieName = "admin_session"
+	csrfCookieName    = "admin_csrf"
+	csrfFormField     = "csrf_token"
+)
+
+var errInvalidSession = errors.New("invalid session")
+
+// consoleSession is the payload stored in the signed session cookie
+type consoleSession struct {
+	UserID    uuid.UUID `json:"uid"`
+	ExpiresAt int64     `json:"exp"`
+}
+
+// setSessionCookie signs a session for the user and stores it in a cookie
+func setSessionCookie(c *gin.Context, userID uuid.UUID) error {
+	ttl := config.AppConfig.AdminSessionExpiration
+	payload, err := json.Marshal(consoleSession{
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	value := encoded + "." + sign(encoded)
+	setCookie(c, sessionCookieName, value, int(ttl.Seconds()))
+	return nil
+}
+
+// readSessionCookie verifies the session cookie and returns its payload
+func readSessionCookie(c *gin.Context) (*consoleSession, error) {
+	value, err := c.Cookie(sessionCookieName)
+	if err != nil {
+		return nil, errInvalidSession
+	}
+
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(encoded))) {
+		return nil, errInvalidSession
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errInvalidSession
+	}
+
+	var session consoleSession
+	if err := json.Unmarshal(payload, &session); err != nil {
+		return nil, errInvalidSession
+	}
+	if time.Now().Unix() > session.ExpiresAt {
+		return nil, errInvalidSession
+	}
+	return &session, nil
+}
+
+func clearSessionCookie(c *gin.Context) {
+	setCookie(c, sessionCookieName, "", -1)
+}
+
+// csrfToken returns the CSRF token for this browser, issuing one if needed.
+// The token is kept in a cookie and must be echoed back in every form post.
+func csrfToken(c *gin.Context) string {
+	if token, err := c.Cookie(csrfCookieName); err == nil && token != "" {
+		return token
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	setCookie(c, csrfCookieName, token, 0)
+	return token
+}
+
+// VerifyCSRF rejects unsafe requests whose form token doesn't match the CSRF cookie
+func VerifyCSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		cookie, err := c.Cookie(csrfCookieName)
+		token := c.PostForm(csrfFormField)
+		if err != nil || cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(token)) != 1 {
+			c.String(http.StatusForbidden, "Invalid CSRF token")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func setCookie(c *gin.Context, name, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(name, value, maxAge, consolePath, "", config.AppConfig.Env == "production", true)
+}
+
+func sign(value string) string {
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWTSecret))
+	mac.Write([]byte("admin-session:" + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"echo-golang/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// withConsoleConfig sets the configuration the console's cookies are signed
+// and issued with
+func withConsoleConfig(t *testing.T) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	saved := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = saved })
+	config.AppConfig = &config.Config{JWTSecret: "test-secret", AdminSessionExpiration: time.Hour}
+}
+
+// sessionValue encodes a session and signs it like setSessionCookie
+func sessionValue(t *testing.T, session consoleSession) string {
+	t.Helper()
+	payload, err := json.Marshal(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(encoded)
+}
+
+func TestReadSessionCookie(t *testing.T) {
+	withConsoleConfig(t)
+	user := uuid.New()
+	valid := sessionValue(t, consoleSession{UserID: user, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	encoded, signature, _ := strings.Cut(valid, ".")
+	forged, err := json.Marshal(consoleSession{UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.AppConfig.JWTSecret = "another-secret"
+	otherSecret := sessionValue(t, consoleSession{UserID: user, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	config.AppConfig.JWTSecret = "test-secret"
+
+	cases := []struct {
+		name  string
+		value string // Empty for no cookie
+		ok    bool
+	}{
+		{"valid", valid, true},
+		{"no cookie", "", false},
+		{"unsigned", encoded, false},
+		{"empty signature", encoded + ".", false},
+		{"tampered payload", base64.RawURLEncoding.EncodeToString(forged) + "." + signature, false},
+		{"tampered signature", encoded + "." + strings.ToUpper(signature), false},
+		{"signed with another secret", otherSecret, false},
+		{"signed garbage", "!!!." + sign("!!!"), false},
+		{"expired", sessionValue(t, consoleSession{UserID: user, ExpiresAt: time.Now().Add(-time.Minute).Unix()}), false},
+	}
+	for _, c := range cases {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, consolePath, nil)
+		if c.value != "" {
+			ctx.Request.AddCookie(&http.Cookie{Name: sessionCookieName, Value: c.value})
+		}
+		session, err := readSessionCookie(ctx)
+		if c.ok {
+			if err != nil || session.UserID != user {
+				t.Errorf("%s: got %v, %v, want the session of %s", c.name, session, err, user)
+			}
+		} else if err != errInvalidSession {
+			t.Errorf("%s: got %v, want %v", c.name, err, errInvalidSession)
+		}
+	}
+}
+
+func TestVerifyCSRF(t *testing.T) {
+	withConsoleConfig(t)
+	r := gin.New()
+	r.Use(VerifyCSRF())
+	r.Any("/admin/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	cases := []struct {
+		name   string
+		method string
+		cookie string // Empty for no cookie
+		token  string // Empty for no form field
+		want   int
+	}{
+		{"get without a token", http.MethodGet, "", "", http.StatusOK},
+		{"post with the token", http.MethodPost, "token-1", "token-1", http.StatusOK},
+		{"post without a token", http.MethodPost, "token-1", "", http.StatusForbidden},
+		{"post without a cookie", http.MethodPost, "", "token-1", http.StatusForbidden},
+		{"post with another token", http.MethodPost, "token-1", "token-2", http.StatusForbidden},
+		{"post with neither", http.MethodPost, "", "", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		form := url.Values{}
+		if c.token != "" {
+			form.Set(csrfFormField, c.token)
+		}
+		req := httptest.NewRequest(c.method, "/admin/users", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if c.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: c.cookie})
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, w.Code, c.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package analytics computes advanced basketball metrics from box score lines
+// and match event logs: shooting efficiency, usage, efficiency ratings,
+// possessions and plus/minus.
+package analytics
+
+import (
+	"math"
+
+	"echo-golang/internal/models"
+)
+
+// freeThrowWeight is the share of a possession a free throw attempt stands
+// for, since and-ones and technicals don't end one and trips take two or three
+const freeThrowWeight = 0.44
+
+// Metrics are the advanced metrics of a player or team over one or more
+// games. Metrics without the attempts, minutes or possessions they are
+// measured against are left out.
+type Metrics struct {
+	TrueShootingPct       *float64 `json:"true_shooting_pct,omitempty"`
+	EffectiveFieldGoalPct *float64 `json:"effective_field_goal_pct,omitempty"`
+	AssistTurnoverRatio   *float64 `json:"assist_turnover_ratio,omitempty"`
+	Efficiency            float64  `json:"efficiency"`                 // Per game
+	UsagePct              *float64 `json:"usage_pct,omitempty"`        // Players only
+	Possessions           *float64 `json:"possessions,omitempty"`      // Teams only, per game
+	OffensiveRating       *float64 `json:"offensive_rating,omitempty"` // Teams only, points per 100 possessions
+	DefensiveRating       *float64 `json:"defensive_rating,omitempty"` // Teams only, points allowed per 100 possessions
+	NetRating             *float64 `json:"net_rating,omitempty"`       // Teams only
+}
+
+// Player computes a player's metrics over games from the player's totals and
+// the totals of the player's team in the same games
+func Player(line, team models.StatLine, games int) Metrics {
+	return Metrics{
+		TrueShootingPct:       TrueShootingPct(line),
+		EffectiveFieldGoalPct: EffectiveFieldGoalPct(line),
+		AssistTurnoverRatio:   AssistTurnoverRatio(line),
+		Efficiency:            perGame(float64(Efficiency(line)), games),
+		UsagePct:              UsagePct(line, team),
+	}
+}
+
+// Team computes a team's metrics over games from its totals and the points
+// it allowed
+func Team(line models.StatLine, opponentPoints, games int) Metrics {
+	m := Metrics{
+		TrueShootingPct:       TrueShootingPct(line),
+		EffectiveFieldGoalPct: EffectiveFieldGoalPct(line),
+		AssistTurnoverRatio:   AssistTurnoverRatio(line),
+		Efficiency:            perGame(float64(Efficiency(line)), games),
+	}
+	possessions := Possessions(line)
+	if games == 0 || possessions <= 0 {
+		return m
+	}
+	pace := round(possessions / float64(games))
+	offensive := Rating(line.Points, possessions)
+	defensive := Rating(opponentPoints, possessions)
+	net := round(*offensive - *defensive)
+	m.Possessions, m.OffensiveRating, m.DefensiveRating, m.NetRating = &pace, offensive, defensive, &net
+	return m
+}
+
+// TrueShootingPct is points per shooting possession, counting two and three
+// pointers and free throws: PTS / (2 * (FGA + 0.44 * FTA)), as a percentage
+func TrueShootingPct(l models.StatLine) *float64 {
+	attempts := float64(l.FieldGoalsAttempted) + freeThrowWeight*float64(l.FreeThrowsAttempted)
+	if attempts == 0 {
+		return nil
+	}
+	return pct(float64(l.Points) / (2 * attempts))
+}
+
+// EffectiveFieldGoalPct is the field goal percentage with three pointers
+// worth one and a half makes: (FGM + 0.5 * 3PM) / FGA
+func EffectiveFieldGoalPct(l models.StatLine) *float64 {
+	if l.FieldGoalsAttempted == 0 {
+		return nil
+	}
+	return pct((float64(l.FieldGoalsMade) + 0.5*float64(l.ThreePointersMade)) / float64(l.FieldGoalsAttempted))
+}
+
+// AssistTurnoverRatio is assists per turnover
+func AssistTurnoverRatio(l models.StatLine) *float64 {
+	if l.Turnovers == 0 {
+		return nil
+	}
+	ratio := round(float64(l.Assists) / float64(l.Turnovers))
+	return &ratio
+}
+
+// Efficiency is the linear efficiency rating: everything good minus missed
+// shots and turnovers, PTS + REB + AST + STL + BLK - missed FG - missed FT - TOV
+func Efficiency(l models.StatLine) int {
+	return l.Points + l.Rebounds + l.Assists + l.Steals + l.Blocks -
+		(l.FieldGoalsAttempted - l.FieldGoalsMade) -
+		(l.FreeThrowsAttempted - l.FreeThrowsMade) -
+		l.Turnovers
+}
+
+// Possessions estimates the possessions a team used: FGA - ORB + TOV +
+// 0.44 * FTA. Both teams of a game have about as many, so the estimate also
+// stands for the opponent's possessions.
+func Possessions(l models.StatLine) float64 {
+	return float64(l.FieldGoalsAttempted-l.OffensiveRebounds+l.Turnovers) +
+		freeThrowWeight*float64(l.FreeThrowsAttempted)
+}
+
+// Rating is points per 100 possessions
+func Rating(points int, possessions float64) *float64 {
+	if possessions <= 0 {
+		return nil
+	}
+	rating := round(100 * float64(points) / possessions)
+	return &rating
+}
+
+// UsagePct is the share of the team's plays a player used while on court:
+// 100 * (FGA + 0.44 * FTA + TOV) * (team minutes / 5) / (minutes * (team FGA +
+// 0.44 * team FTA + team TOV)). The team line needs the team's attempts,
+// turnovers and the minutes of all its players.
+func UsagePct(player, team models.StatLine) *float64 {
+	plays := func(l models.StatLine) float64 {
+		return float64(l.FieldGoalsAttempted+l.Turnovers) + freeThrowWeight*float64(l.FreeThrowsAttempted)
+	}
+	teamPlays := plays(team)
+	if player.SecondsPlayed == 0 || teamPlays == 0 {
+		return nil
+	}
+	return pct(plays(player) * float64(team.SecondsPlayed) / 5 / (float64(player.SecondsPlayed) * teamPlays))
+}
+
+func perGame(total float64, games int) float64 {
+	if games == 0 {
+		return 0
+	}
+	return round(total / float64(games))
+}
+
+// pct turns a ratio into a percentage with one decimal
+func pct(ratio float64) *float64 {
+	p := round(ratio * 100)
+	return &p
+}
+
+// round rounds to one decimal
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
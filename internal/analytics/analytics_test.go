@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"echo-golang/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the .golden files from the current output")
+
+// golden is the input of a testdata case: a player's line with the team's, a
+// team's line with the points it allowed, or a match's event log
+type golden struct {
+	Kind           string              `json:"kind"` // player, team or plus_minus
+	Line           models.StatLine     `json:"line"`
+	Team           models.StatLine     `json:"team"`
+	OpponentPoints int                 `json:"opponent_points"`
+	Games          int                 `json:"games"`
+	Events         []models.MatchEvent `json:"events"`
+}
+
+func TestGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no testdata cases")
+	}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var in golden
+			if err := json.Unmarshal(data, &in); err != nil {
+				t.Fatal(err)
+			}
+
+			var out interface{}
+			switch in.Kind {
+			case "player":
+				out = Player(in.Line, in.Team, in.Games)
+			case "team":
+				out = Team(in.Line, in.OpponentPoints, in.Games)
+			case "plus_minus":
+				out = PlusMinus(in.Events)
+			default:
+				t.Fatalf("unknown kind %q", in.Kind)
+			}
+			got, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package analytics
+
+import (
+	"echo-golang/internal/models"
+	"echo-golang/internal/oncourt"
+
+	"github.com/google/uuid"
+)
+
+// PlusMinus computes the point differential of each player's team while the
+// player was on court, from a match's events ordered by sequence. Voided
+// events are skipped. Who is on court follows the oncourt rules, so a starter
+// first seen late in the log is credited with the differential from the
+// start of the game.
+func PlusMinus(events []models.MatchEvent) map[uuid.UUID]int {
+	court := oncourt.New()
+	score := Score{}
+	plusMinus := map[uuid.UUID]int{}
+	add := func(playerID uuid.UUID, points int) { plusMinus[playerID] += points }
+	for i := range events {
+		e := &events[i]
+		if oncourt.Moves(e) {
+			score.Credit(court, e, court.Apply(e, 0), add)
+		}
+	}
+	for playerID := range court.Teams {
+		add(playerID, 0)
+	}
+	return plusMinus
+}
+
+// Score is the points each team has scored so far as a match's events are
+// applied in order, which plus/minus is credited from
+type Score map[uuid.UUID]int
+
+// Credit adds to plus/minus the effect of an event just applied to the court,
+// which found starters: each starter gets the team's differential so far, and
+// the points of a point event count for or against every player on court
+func (s Score) Credit(court *oncourt.Court, e *models.MatchEvent, starters []uuid.UUID, add func(playerID uuid.UUID, points int)) {
+	for _, playerID := range starters {
+		add(playerID, s.differential(court.Teams[playerID]))
+	}
+	if e.Type != models.MatchEventPoint {
+		return
+	}
+	for playerID := range court.OnSince {
+		if court.Teams[playerID] == *e.TeamID {
+			add(playerID, e.Points)
+		} else {
+			add(playerID, -e.Points)
+		}
+	}
+	s[*e.TeamID] += e.Points
+}
+
+func (s Score) differential(team uuid.UUID) int {
+	diff := 0
+	for t, points := range s {
+		if t == team {
+			diff += points
+		} else {
+			diff -= points
+		}
+	}
+	return diff
+}
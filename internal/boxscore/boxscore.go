@@ -0,0 +1,224 @@
+// Package boxscore derives the box score of a match from its event log.
+package boxscore
+
+import (
+	"time"
+
+	"echo-golang/internal/analytics"
+	"echo-golang/internal/models"
+	"echo-golang/internal/oncourt"
+
+	"github.com/google/uuid"
+)
+
+// Result is the box score of a match: a line for every player who appears in
+// its events, in order of first appearance, and the totals of both teams,
+// home first
+type Result struct {
+	Players []models.PlayerStatistics
+	Teams   []models.TeamStatistics
+	State   State
+}
+
+// State is what the box score of a match needs besides its lines to carry on
+// with the next event. It marshals to JSON so it can be stored between
+// events.
+type State struct {
+	Court    *oncourt.Court  `json:"court"`
+	Now      time.Duration   `json:"now"`                 // Game time of the latest event
+	LastMiss *uuid.UUID      `json:"last_miss,omitempty"` // Team of the latest missed shot not yet rebounded
+	Score    analytics.Score `json:"score"`
+}
+
+// Compute builds the box score of a match from its events ordered by
+// sequence. Voided events are skipped, so recomputing after an undo drops
+// the undone event's stats.
+//
+// Minutes come from substitutions and the game clock stamped on each event,
+// with players on court as the oncourt package places them. Players still on
+// court are counted up to the latest event. Plus/minus follows the same rule.
+func Compute(match *models.Match, events []models.MatchEvent) Result {
+	b := &builder{
+		match:   match,
+		players: map[uuid.UUID]*models.PlayerStatistics{},
+		teams:   map[uuid.UUID]*models.StatLine{match.HomeTeamID: {}, match.AwayTeamID: {}},
+		State:   State{Court: oncourt.New(), Score: analytics.Score{}},
+	}
+	for i := range events {
+		b.apply(&events[i])
+	}
+	return b.result()
+}
+
+// Update carries a box score on with the next event of its match, from the
+// lines and state the events before it left. It returns the player lines the
+// event changed, both team lines and the new state. Updating with each event
+// in turn gives the same lines as Compute over all of them.
+func Update(match *models.Match, players []models.PlayerStatistics, teams []models.TeamStatistics, state State, e *models.MatchEvent) Result {
+	b := &builder{
+		match:   match,
+		players: map[uuid.UUID]*models.PlayerStatistics{},
+		teams:   map[uuid.UUID]*models.StatLine{match.HomeTeamID: {}, match.AwayTeamID: {}},
+		State:   state,
+	}
+	before := map[uuid.UUID]models.StatLine{}
+	for i := range players {
+		line := players[i]
+		b.players[line.PlayerID] = &line
+		b.order = append(b.order, line.PlayerID)
+		before[line.PlayerID] = line.StatLine
+	}
+	for _, team := range teams {
+		if line, ok := b.teams[team.TeamID]; ok {
+			*line = team.StatLine
+		}
+	}
+	b.apply(e)
+
+	result := b.result()
+	changed := result.Players[:0]
+	for _, line := range result.Players {
+		if was, ok := before[line.PlayerID]; !ok || was != line.StatLine {
+			changed = append(changed, line)
+		}
+	}
+	result.Players = changed
+	return result
+}
+
+type builder struct {
+	match   *models.Match
+	players map[uuid.UUID]*models.PlayerStatistics
+	order   []uuid.UUID
+	teams   map[uuid.UUID]*models.StatLine
+	State
+}
+
+func (b *builder) apply(e *models.MatchEvent) {
+	if e.Voided || e.Type == models.MatchEventVoid {
+		return
+	}
+	// Clock events carry no team but still move the game time on
+	if e.Period > 0 {
+		b.Now = b.match.GameElapsed(e.Period, time.Duration(e.ClockRemainingMs)*time.Millisecond)
+		if b.Now < 0 {
+			b.Now = 0
+		}
+	}
+	if e.TeamID == nil {
+		return
+	}
+
+	team := *e.TeamID
+	b.Score.Credit(b.Court, e, b.Court.Apply(e, b.Now), func(playerID uuid.UUID, points int) {
+		b.line(b.Court.Teams[playerID], playerID).PlusMinus += points
+	})
+	switch e.Type {
+	case models.MatchEventPoint:
+		b.credit(team, e.PlayerID, func(l *models.StatLine) {
+			l.Points += e.Points
+			shot(l, e.Points, true)
+		})
+		if e.AssistPlayerID != nil {
+			b.credit(team, e.AssistPlayerID, func(l *models.StatLine) { l.Assists++ })
+		}
+		b.LastMiss = nil
+	case models.MatchEventMiss:
+		b.credit(team, e.PlayerID, func(l *models.StatLine) { shot(l, e.Points, false) })
+		b.LastMiss = &team
+	case models.MatchEventRebound:
+		offensive := b.LastMiss != nil && *b.LastMiss == team
+		b.credit(team, e.PlayerID, func(l *models.StatLine) {
+			l.Rebounds++
+			if offensive {
+				l.OffensiveRebounds++
+			} else {
+				l.DefensiveRebounds++
+			}
+		})
+		b.LastMiss = nil
+	case models.MatchEventSteal:
+		b.credit(team, e.PlayerID, func(l *models.StatLine) { l.Steals++ })
+	case models.MatchEventBlock:
+		b.credit(team, e.PlayerID, func(l *models.StatLine) { l.Blocks++ })
+	case models.MatchEventTurnover:
+		b.credit(team, e.PlayerID, func(l *models.StatLine) { l.Turnovers++ })
+	case models.MatchEventFoul:
+		b.credit(team, e.PlayerID, func(l *models.StatLine) { l.Fouls++ })
+	case models.MatchEventSubstitution:
+		for _, playerID := range []*uuid.UUID{e.SubstitutedPlayerID, e.PlayerID} {
+			if playerID != nil {
+				b.line(team, *playerID)
+			}
+		}
+	}
+}
+
+// credit applies a stat to the team's totals and, when there is one, to the
+// player's line
+func (b *builder) credit(team uuid.UUID, playerID *uuid.UUID, add func(*models.StatLine)) {
+	if line, ok := b.teams[team]; ok {
+		add(line)
+	}
+	if playerID != nil {
+		add(&b.line(team, *playerID).StatLine)
+	}
+}
+
+func (b *builder) line(team, playerID uuid.UUID) *models.PlayerStatistics {
+	line, ok := b.players[playerID]
+	if !ok {
+		line = &models.PlayerStatistics{MatchID: b.match.ID, PlayerID: playerID, TeamID: team}
+		b.players[playerID] = line
+		b.order = append(b.order, playerID)
+	}
+	return line
+}
+
+func (b *builder) result() Result {
+	result := Result{State: b.State}
+	for _, team := range b.teams {
+		team.SecondsPlayed = 0
+	}
+	for _, playerID := range b.order {
+		line := b.players[playerID]
+		line.SecondsPlayed = int(b.Court.TimePlayed(playerID, b.Now) / time.Second)
+		if team, ok := b.teams[line.TeamID]; ok {
+			team.SecondsPlayed += line.SecondsPlayed
+		}
+		result.Players = append(result.Players, *line)
+	}
+	home, away := b.teams[b.match.HomeTeamID], b.teams[b.match.AwayTeamID]
+	home.PlusMinus, away.PlusMinus = home.Points-away.Points, away.Points-home.Points
+	for _, teamID := range []uuid.UUID{b.match.HomeTeamID, b.match.AwayTeamID} {
+		result.Teams = append(result.Teams, models.TeamStatistics{
+			MatchID:  b.match.ID,
+			TeamID:   teamID,
+			StatLine: *b.teams[teamID],
+		})
+	}
+	return result
+}
+
+// shot counts a made or missed attempt worth points: free throws are worth
+// one, field goals two or three
+func shot(l *models.StatLine, points int, made bool) {
+	switch points {
+	case 1:
+		l.FreeThrowsAttempted++
+		if made {
+			l.FreeThrowsMade++
+		}
+	case 2, 3:
+		l.FieldGoalsAttempted++
+		if made {
+			l.FieldGoalsMade++
+		}
+		if points == 3 {
+			l.ThreePointersAttempted++
+			if made {
+				l.ThreePointersMade++
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package boxscore
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestUpdateMatchesCompute(t *testing.T) {
+	home, away := uuid.New(), uuid.New()
+	match := &models.Match{ID: uuid.New(), HomeTeamID: home, AwayTeamID: away, PeriodCount: 4, PeriodLength: 600}
+	h, a := make([]uuid.UUID, 6), make([]uuid.UUID, 5)
+	for _, players := range [][]uuid.UUID{h, a} {
+		for i := range players {
+			players[i] = uuid.New()
+		}
+	}
+
+	var events []models.MatchEvent
+	clock := 10 * time.Minute
+	add := func(e models.MatchEvent) {
+		clock -= 20 * time.Second
+		e.Sequence, e.Period, e.ClockRemainingMs = len(events)+1, 1, clock.Milliseconds()
+		events = append(events, e)
+	}
+	point := func(team uuid.UUID, player uuid.UUID, assist *uuid.UUID, points int) {
+		add(models.MatchEvent{Type: models.MatchEventPoint, TeamID: &team, PlayerID: &player, AssistPlayerID: assist, Points: points})
+	}
+	point(home, h[0], &h[1], 2)
+	add(models.MatchEvent{Type: models.MatchEventMiss, TeamID: &away, PlayerID: &a[0], Points: 3})
+	add(models.MatchEvent{Type: models.MatchEventRebound, TeamID: &away, PlayerID: &a[1]})
+	point(away, a[1], nil, 2)
+	add(models.MatchEvent{Type: models.MatchEventClockStop})
+	add(models.MatchEvent{Type: models.MatchEventSubstitution, TeamID: &home, PlayerID: &h[5], SubstitutedPlayerID: &h[2]})
+	add(models.MatchEvent{Type: models.MatchEventClockStart})
+	point(home, h[5], nil, 3)
+	add(models.MatchEvent{Type: models.MatchEventTurnover, TeamID: &away, PlayerID: &a[2]})
+	add(models.MatchEvent{Type: models.MatchEventFoul, TeamID: &home, PlayerID: &h[3]})
+	point(away, a[3], &a[4], 1)
+	add(models.MatchEvent{Type: models.MatchEventTimeout, TeamID: &home})
+	point(home, h[4], &h[0], 2)
+
+	// Carry the box score on event by event, storing the lines and the state
+	// in between
+	stored := Compute(match, nil)
+	players := map[uuid.UUID]models.PlayerStatistics{}
+	for i := range events {
+		data, err := json.Marshal(stored.State)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var state State
+		if err := json.Unmarshal(data, &state); err != nil {
+			t.Fatal(err)
+		}
+		var lines []models.PlayerStatistics
+		for _, line := range players {
+			lines = append(lines, line)
+		}
+		stored = Update(match, lines, stored.Teams, state, &events[i])
+		for _, line := range stored.Players {
+			players[line.PlayerID] = line
+		}
+	}
+
+	want := Compute(match, events)
+	if len(players) != len(want.Players) {
+		t.Fatalf("got %d player lines, want %d", len(players), len(want.Players))
+	}
+	for _, line := range want.Players {
+		if got := players[line.PlayerID]; got.StatLine != line.StatLine || got.TeamID != line.TeamID {
+			t.Errorf("player line %+v, want %+v", got.StatLine, line.StatLine)
+		}
+	}
+	for i, team := range want.Teams {
+		if got := stored.Teams[i]; got.TeamID != team.TeamID || got.StatLine != team.StatLine {
+			t.Errorf("team line %+v, want %+v", got.StatLine, team.StatLine)
+		}
+	}
+	if p := players[h[2]]; p.SecondsPlayed != 120 || p.PlusMinus != 0 {
+		t.Errorf("substituted starter: %d seconds at %+d, want 120 at +0 from a tied game", p.SecondsPlayed, p.PlusMinus)
+	}
+	if p := players[h[5]]; p.SecondsPlayed != 140 || p.PlusMinus != 4 {
+		t.Errorf("substitute: %d seconds at %+d, want 140 at +4", p.SecondsPlayed, p.PlusMinus)
+	}
+}
+
+func TestUpdateReturnsChangedLines(t *testing.T) {
+	home, away := uuid.New(), uuid.New()
+	match := &models.Match{ID: uuid.New(), HomeTeamID: home, AwayTeamID: away, PeriodCount: 4, PeriodLength: 600}
+	p1, p2 := uuid.New(), uuid.New()
+	events := []models.MatchEvent{
+		{Type: models.MatchEventSubstitution, TeamID: &home, PlayerID: &p2, SubstitutedPlayerID: &p1, Period: 1, ClockRemainingMs: 540000},
+	}
+	box := Compute(match, events)
+
+	// p1 is on the bench, p2 on court: a steal by the away team changes p2's
+	// minutes only
+	steal := models.MatchEvent{Type: models.MatchEventSteal, TeamID: &away, Period: 1, ClockRemainingMs: 480000}
+	got := Update(match, box.Players, box.Teams, box.State, &steal)
+	if len(got.Players) != 1 || got.Players[0].PlayerID != p2 || got.Players[0].SecondsPlayed != 60 {
+		t.Errorf("got changed lines %+v, want p2's with 60 seconds", got.Players)
+	}
+	if len(got.Teams) != 2 || got.Teams[1].Steals != 1 {
+		t.Errorf("got team lines %+v, want both with the away steal", got.Teams)
+	}
+}
@@ -0,0 +1,194 @@
+// Package bracket lays out single and double elimination brackets. It only
+// describes the shape of a bracket: which seeds meet in the first round and
+// where every winner and loser goes next.
+package bracket
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	Winners = "winners"
+	Losers  = "losers"
+	Final   = "final"
+
+	Home = "home"
+	Away = "away"
+)
+
+var (
+	ErrTooFewEntrants      = errors.New("at least two entrants are required")
+	ErrTooFewForDoubleElim = errors.New("double elimination needs at least four entrants")
+)
+
+// Link sends the winner or loser of a node to one side of another node
+type Link struct {
+	Key  string
+	Side string
+}
+
+// Node is one game of the bracket. First round winners-bracket nodes have
+// seeds; a seed beyond the number of entrants is a bye. Every other side is
+// filled by a Link from an earlier node.
+type Node struct {
+	Key      string
+	Bracket  string
+	Round    int
+	Position int
+	HomeSeed int // 0 when fed by another node
+	AwaySeed int
+	WinnerTo *Link
+	LoserTo  *Link
+}
+
+// Plan is the layout of a bracket for a number of entrants
+type Plan struct {
+	Entrants int
+	Size     int // Entrants rounded up to a power of two
+	Double   bool
+	Nodes    []Node
+}
+
+// Key names a node, e.g. W1-3 for the third game of winners round one
+func Key(bracketName string, round, position int) string {
+	if bracketName == Final {
+		return "GF"
+	}
+	return fmt.Sprintf("%c%d-%d", bracketName[0]-'a'+'A', round, position)
+}
+
+// SeedOrder returns the seeds in bracket order for a power-of-two size, so
+// that adjacent pairs meet in round one and the top seeds can only meet in
+// the last rounds: 1 8 4 5 2 7 3 6 for eight.
+func SeedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		n := len(order) * 2
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// New lays out a bracket for the entrants. The field is padded to a power of
+// two with byes, which go to the top seeds. In double elimination a team is
+// out after its second loss: first round losers fall to the losers bracket,
+// later losers drop into it round by round, and the losers bracket champion
+// meets the winners bracket champion in a single grand final.
+func New(entrants int, double bool) (*Plan, error) {
+	if entrants < 2 {
+		return nil, ErrTooFewEntrants
+	}
+	if double && entrants < 4 {
+		return nil, ErrTooFewForDoubleElim
+	}
+
+	size := 1
+	rounds := 0
+	for size < entrants {
+		size *= 2
+		rounds++
+	}
+
+	plan := &Plan{Entrants: entrants, Size: size, Double: double}
+	nodes := map[string]*Node{}
+	var keys []string
+	add := func(n Node) {
+		n.Key = Key(n.Bracket, n.Round, n.Position)
+		nodes[n.Key] = &n
+		keys = append(keys, n.Key)
+	}
+
+	// Winners bracket
+	order := SeedOrder(size)
+	for round := 1; round <= rounds; round++ {
+		games := size >> round
+		for pos := 1; pos <= games; pos++ {
+			node := Node{Bracket: Winners, Round: round, Position: pos}
+			if round == 1 {
+				node.HomeSeed = order[2*(pos-1)]
+				node.AwaySeed = order[2*(pos-1)+1]
+			}
+			add(node)
+			if round > 1 {
+				key := Key(Winners, round, pos)
+				nodes[Key(Winners, round-1, 2*pos-1)].WinnerTo = &Link{Key: key, Side: Home}
+				nodes[Key(Winners, round-1, 2*pos)].WinnerTo = &Link{Key: key, Side: Away}
+			}
+		}
+	}
+
+	if double {
+		// Losers round 1 pairs the first round losers
+		for pos := 1; pos <= size/4; pos++ {
+			add(Node{Bracket: Losers, Round: 1, Position: pos})
+			nodes[Key(Winners, 1, 2*pos-1)].LoserTo = &Link{Key: Key(Losers, 1, pos), Side: Home}
+			nodes[Key(Winners, 1, 2*pos)].LoserTo = &Link{Key: Key(Losers, 1, pos), Side: Away}
+		}
+
+		lround := 1
+		for wround := 2; wround <= rounds; wround++ {
+			// Drop-in round: losers bracket survivors meet the losers of this
+			// winners round. Every other round the order is reversed so teams
+			// that met in the winners bracket don't meet again straight away.
+			games := size >> wround
+			lround++
+			for pos := 1; pos <= games; pos++ {
+				add(Node{Bracket: Losers, Round: lround, Position: pos})
+				nodes[Key(Losers, lround-1, pos)].WinnerTo = &Link{Key: Key(Losers, lround, pos), Side: Home}
+				from := pos
+				if wround%2 == 0 {
+					from = games + 1 - pos
+				}
+				nodes[Key(Winners, wround, from)].LoserTo = &Link{Key: Key(Losers, lround, pos), Side: Away}
+			}
+			if wround == rounds {
+				break
+			}
+
+			// Pairing round: drop-in winners play each other
+			lround++
+			for pos := 1; pos <= games/2; pos++ {
+				add(Node{Bracket: Losers, Round: lround, Position: pos})
+				nodes[Key(Losers, lround-1, 2*pos-1)].WinnerTo = &Link{Key: Key(Losers, lround, pos), Side: Home}
+				nodes[Key(Losers, lround-1, 2*pos)].WinnerTo = &Link{Key: Key(Losers, lround, pos), Side: Away}
+			}
+		}
+
+		add(Node{Bracket: Final, Round: 1, Position: 1})
+		nodes[Key(Winners, rounds, 1)].WinnerTo = &Link{Key: "GF", Side: Home}
+		nodes[Key(Losers, lround, 1)].WinnerTo = &Link{Key: "GF", Side: Away}
+	}
+
+	plan.Nodes = make([]Node, len(keys))
+	for i, key := range keys {
+		plan.Nodes[i] = *nodes[key]
+	}
+	return plan, nil
+}
+
+// RoundName is a display name for a round, e.g. "Semifinal" or "Losers Round 2"
+func RoundName(bracketName string, round, rounds int) string {
+	switch bracketName {
+	case Final:
+		return "Grand Final"
+	case Losers:
+		if round == rounds {
+			return "Losers Final"
+		}
+		return fmt.Sprintf("Losers Round %d", round)
+	}
+	switch rounds - round {
+	case 0:
+		return "Final"
+	case 1:
+		return "Semifinal"
+	case 2:
+		return "Quarterfinal"
+	}
+	return fmt.Sprintf("Round of %d", 2<<(rounds-round))
+}
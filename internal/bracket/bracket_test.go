@@ -0,0 +1,189 @@
+package bracket
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSeedOrder(t *testing.T) {
+	cases := []struct {
+		size int
+		want []int
+	}{
+		{1, []int{1}},
+		{2, []int{1, 2}},
+		{4, []int{1, 4, 2, 3}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+		{16, []int{1, 16, 8, 9, 4, 13, 5, 12, 2, 15, 7, 10, 3, 14, 6, 11}},
+	}
+	for _, c := range cases {
+		if got := SeedOrder(c.size); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("size %d: got %v, want %v", c.size, got, c.want)
+		}
+	}
+}
+
+// layout describes every node of a plan as its key, first round seeds and
+// where its winner (W>) and loser (L>) go
+func layout(plan *Plan) []string {
+	var nodes []string
+	for _, n := range plan.Nodes {
+		s := n.Key
+		if n.HomeSeed > 0 {
+			s += fmt.Sprintf(" %dv%d", n.HomeSeed, n.AwaySeed)
+		}
+		if n.WinnerTo != nil {
+			s += fmt.Sprintf(" W>%s/%s", n.WinnerTo.Key, n.WinnerTo.Side)
+		}
+		if n.LoserTo != nil {
+			s += fmt.Sprintf(" L>%s/%s", n.LoserTo.Key, n.LoserTo.Side)
+		}
+		nodes = append(nodes, s)
+	}
+	return nodes
+}
+
+func TestNew(t *testing.T) {
+	single4 := []string{
+		"W1-1 1v4 W>W2-1/home",
+		"W1-2 2v3 W>W2-1/away",
+		"W2-1",
+	}
+	single8 := []string{
+		"W1-1 1v8 W>W2-1/home",
+		"W1-2 4v5 W>W2-1/away",
+		"W1-3 2v7 W>W2-2/home",
+		"W1-4 3v6 W>W2-2/away",
+		"W2-1 W>W3-1/home",
+		"W2-2 W>W3-1/away",
+		"W3-1",
+	}
+	double4 := []string{
+		"W1-1 1v4 W>W2-1/home L>L1-1/home",
+		"W1-2 2v3 W>W2-1/away L>L1-1/away",
+		"W2-1 W>GF/home L>L2-1/away",
+		"L1-1 W>L2-1/home",
+		"L2-1 W>GF/away",
+		"GF",
+	}
+	// The winners round 2 losers drop in reversed: W2-1's loser meets the
+	// survivor from W1-3 and W1-4's side of the draw
+	double8 := []string{
+		"W1-1 1v8 W>W2-1/home L>L1-1/home",
+		"W1-2 4v5 W>W2-1/away L>L1-1/away",
+		"W1-3 2v7 W>W2-2/home L>L1-2/home",
+		"W1-4 3v6 W>W2-2/away L>L1-2/away",
+		"W2-1 W>W3-1/home L>L2-2/away",
+		"W2-2 W>W3-1/away L>L2-1/away",
+		"W3-1 W>GF/home L>L4-1/away",
+		"L1-1 W>L2-1/home",
+		"L1-2 W>L2-2/home",
+		"L2-1 W>L3-1/home",
+		"L2-2 W>L3-1/away",
+		"L3-1 W>L4-1/home",
+		"L4-1 W>GF/away",
+		"GF",
+	}
+	cases := []struct {
+		entrants int
+		double   bool
+		size     int
+		want     []string
+	}{
+		{4, false, 4, single4},
+		{5, false, 8, single8},
+		{6, false, 8, single8},
+		{8, false, 8, single8},
+		{4, true, 4, double4},
+		{5, true, 8, double8},
+		{6, true, 8, double8},
+		{8, true, 8, double8},
+	}
+	for _, c := range cases {
+		plan, err := New(c.entrants, c.double)
+		if err != nil {
+			t.Errorf("%d entrants, double %v: %v", c.entrants, c.double, err)
+			continue
+		}
+		if plan.Entrants != c.entrants || plan.Size != c.size || plan.Double != c.double {
+			t.Errorf("%d entrants, double %v: got %d entrants, size %d, double %v", c.entrants, c.double,
+				plan.Entrants, plan.Size, plan.Double)
+		}
+		if got := layout(plan); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%d entrants, double %v: got\n%v\nwant\n%v", c.entrants, c.double, got, c.want)
+		}
+	}
+}
+
+func TestNewByes(t *testing.T) {
+	// The seeds beyond the entrants are byes, each facing one of the top seeds
+	cases := []struct {
+		entrants int
+		want     map[int]int // Seed with a bye -> bye seed
+	}{
+		{4, map[int]int{}},
+		{5, map[int]int{1: 8, 2: 7, 3: 6}},
+		{6, map[int]int{1: 8, 2: 7}},
+		{7, map[int]int{1: 8}},
+		{8, map[int]int{}},
+	}
+	for _, c := range cases {
+		plan, err := New(c.entrants, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[int]int{}
+		for _, n := range plan.Nodes {
+			if n.AwaySeed > c.entrants {
+				got[n.HomeSeed] = n.AwaySeed
+			}
+			if n.HomeSeed > c.entrants {
+				t.Errorf("%d entrants: bye seed %d on the home side of %s", c.entrants, n.HomeSeed, n.Key)
+			}
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%d entrants: byes %v, want %v", c.entrants, got, c.want)
+		}
+	}
+}
+
+func TestNewDropInOrder(t *testing.T) {
+	// Drop-in rounds alternate between reversed and straight, so the losers
+	// of winners rounds 2 and 3 of sixteen land on opposite halves
+	plan, err := New(16, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"W2-1": "L2-4", "W2-2": "L2-3", "W2-3": "L2-2", "W2-4": "L2-1",
+		"W3-1": "L4-1", "W3-2": "L4-2",
+		"W4-1": "L6-1",
+	}
+	for _, n := range plan.Nodes {
+		if to, ok := want[n.Key]; ok && (n.LoserTo == nil || n.LoserTo.Key != to || n.LoserTo.Side != Away) {
+			t.Errorf("%s: loser goes to %+v, want %s/away", n.Key, n.LoserTo, to)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	cases := []struct {
+		entrants int
+		double   bool
+		err      error
+	}{
+		{0, false, ErrTooFewEntrants},
+		{1, false, ErrTooFewEntrants},
+		{2, false, nil},
+		{3, false, nil},
+		{3, true, ErrTooFewForDoubleElim},
+		{4, true, nil},
+	}
+	for _, c := range cases {
+		if _, err := New(c.entrants, c.double); !errors.Is(err, c.err) {
+			t.Errorf("%d entrants, double %v: got %v, want %v", c.entrants, c.double, err, c.err)
+		}
+	}
+}
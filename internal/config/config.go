@@ -27,6 +27,33 @@ type Config struct {
 	JWTExpiration        time.Duration
 	JWTRefreshExpiration time.Duration
 
+	// Admin console
+	AdminSessionExpiration time.Duration
+
+	// Match scheduling
+	MatchDuration         time.Duration
+	MatchSchedulingBuffer time.Duration
+
+	// Game clock defaults for matches whose tournament sets no rules
+	GamePeriodCount    int
+	GamePeriodLength   time.Duration
+	GameOvertimeLength time.Duration
+
+	// Live updates: how many updates a client may fall behind before it is
+	// disconnected
+	LiveBufferSize int
+
+	// Leaderboards are cached until a match in scope completes on any API
+	// instance, and for at most this long. At most LeaderboardCacheSize are
+	// kept.
+	LeaderboardCacheTTL  time.Duration
+	LeaderboardCacheSize int
+
+	// Redis, shares live updates between API instances when RedisHost is set
+	RedisHost     string
+	RedisPort     string
+	RedisPassword string
+
 	// File Upload
 	UploadDir     string
 	MaxUploadSize int64
@@ -41,6 +68,12 @@ func LoadConfig() error {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
+	// Games are played in halves or quarters, as tournaments allow
+	periodCount := getEnv("GAME_PERIOD_COUNT", "4")
+	if periodCount != "2" && periodCount != "4" {
+		return fmt.Errorf("GAME_PERIOD_COUNT must be 2 or 4, got %q", periodCount)
+	}
+
 	AppConfig = &Config{
 		Port: getEnv("PORT", "8080"),
 		Env:  getEnv("ENV", "development"),
@@ -56,6 +89,24 @@ func LoadConfig() error {
 		JWTExpiration:        parseDuration(getEnv("JWT_EXPIRATION", "15m")),
 		JWTRefreshExpiration: parseDuration(getEnv("JWT_REFRESH_EXPIRATION", "168h")),
 
+		AdminSessionExpiration: parseDuration(getEnv("ADMIN_SESSION_EXPIRATION", "8h")),
+
+		MatchDuration:         parseDuration(getEnv("MATCH_DURATION", "2h")),
+		MatchSchedulingBuffer: parseDuration(getEnv("MATCH_SCHEDULING_BUFFER", "30m")),
+
+		GamePeriodCount:    parseInt(periodCount, 4),
+		GamePeriodLength:   parseDuration(getEnv("GAME_PERIOD_LENGTH", "10m")),
+		GameOvertimeLength: parseDuration(getEnv("GAME_OVERTIME_LENGTH", "5m")),
+
+		LiveBufferSize: parseInt(getEnv("LIVE_BUFFER_SIZE", "64"), 64),
+
+		LeaderboardCacheTTL:  parseDuration(getEnv("LEADERBOARD_CACHE_TTL", "5m")),
+		LeaderboardCacheSize: parseInt(getEnv("LEADERBOARD_CACHE_SIZE", "500"), 500),
+
+		RedisHost:     getEnv("REDIS_HOST", ""),
+		RedisPort:     getEnv("REDIS_PORT", "6379"),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+
 		UploadDir:     getEnv("UPLOAD_DIR", "./uploads"),
 		MaxUploadSize: parseInt64(getEnv("MAX_UPLOAD_SIZE", "10485760")), // 10MB
 
@@ -114,6 +165,14 @@ func parseInt64(s string) int64 {
 	return val
 }
 
+func parseInt(s string, defaultValue int) int {
+	val, err := strconv.Atoi(s)
+	if err != nil {
+		return defaultValue
+	}
+	return val
+}
+
 func parseDuration(s string) time.Duration {
 	duration, err := time.ParseDuration(s)
 	if err != nil {
@@ -3,12 +3,14 @@ package database
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"echo-golang/internal/config"
 	"echo-golang/internal/models"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -28,7 +30,8 @@ func Connect() error {
 	}
 
 	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: gormLogger,
+		Logger:         gormLogger,
+		TranslateError: true, // Unique index violations come back as gorm.ErrDuplicatedKey
 	})
 
 	if err != nil {
@@ -46,10 +49,100 @@ func Connect() error {
 }
 
 func AutoMigrate() error {
-	return DB.AutoMigrate(
+	if err := DB.AutoMigrate(
 		&models.User{},
 		&models.Organization{},
-	)
+		&models.Team{},
+		&models.Player{},
+		&models.RosterMembership{},
+		&models.Tournament{},
+		&models.TournamentEntry{},
+		&models.Match{},
+		&models.VenueLock{},
+		&models.BracketNode{},
+		&models.MatchEvent{},
+		&models.MatchScorekeeper{},
+		&models.PlayerStatistics{},
+		&models.TeamStatistics{},
+		&models.BoxScoreState{},
+		&models.StatAggregate{},
+		&models.Standing{},
+		&models.AuditEvent{},
+	); err != nil {
+		return err
+	}
+
+	if err := runDataMigrations(); err != nil {
+		return err
+	}
+	return ensureSearchIndexes()
+}
+
+// schemaMigration records a data migration that has run
+type schemaMigration struct {
+	Version   string    `gorm:"type:varchar(100);primary_key"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// dataMigrations are one-time fix-ups of existing rows, run in order. Each is
+// recorded by its version once it has run, so it never runs again.
+var dataMigrations = []struct {
+	version string
+	run     func(tx *gorm.DB) error
+}{
+	{"0001_release_deleted_player_users", releaseDeletedPlayerUsers},
+}
+
+// runDataMigrations runs the data migrations that have not run yet
+func runDataMigrations() error {
+	if err := DB.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+	for _, m := range dataMigrations {
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			var applied int64
+			if err := tx.Model(&schemaMigration{}).Where("version = ?", m.version).Count(&applied).Error; err != nil {
+				return err
+			}
+			if applied > 0 {
+				return nil
+			}
+			if err := m.run(tx); err != nil {
+				return err
+			}
+			// Another instance starting at the same time may have run it too
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&schemaMigration{Version: m.version, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("data migration %s: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+// releaseDeletedPlayerUsers clears the user link of players deleted before
+// deleting a player cleared it, so the user can be linked to a new player
+func releaseDeletedPlayerUsers(tx *gorm.DB) error {
+	return tx.Model(&models.Player{}).Unscoped().
+		Where("deleted_at IS NOT NULL AND user_id IS NOT NULL").
+		UpdateColumn("user_id", nil).Error
+}
+
+// ensureSearchIndexes creates the FULLTEXT indexes used by user search. They
+// are MySQL specific, so other databases fall back to LIKE based search.
+func ensureSearchIndexes() error {
+	if DB.Dialector.Name() != "mysql" {
+		return nil
+	}
+	if DB.Migrator().HasIndex(&models.User{}, "idx_users_fulltext") {
+		return nil
+	}
+	return DB.Exec("CREATE FULLTEXT INDEX idx_users_fulltext ON users (full_name, email)").Error
 }
 
 func Close() error {
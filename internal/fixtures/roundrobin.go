@@ -0,0 +1,85 @@
+// Package fixtures generates tournament fixtures and assigns them to date
+// and venue slots.
+package fixtures
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTooFewTeams   = errors.New("at least two teams are required")
+	ErrDuplicateTeam = errors.New("teams must be unique")
+	ErrInvalidLegs   = errors.New("legs must be 1 (single) or 2 (double round-robin)")
+)
+
+// Fixture is one pairing of a round-robin. Rounds are numbered from 1 across
+// both legs, so the second leg of a 6-team league starts at round 6.
+type Fixture struct {
+	Round int       `json:"round"`
+	Leg   int       `json:"leg"`
+	Home  uuid.UUID `json:"home_team_id"`
+	Away  uuid.UUID `json:"away_team_id"`
+}
+
+// RoundRobin pairs every team with every other team once per leg using the
+// circle method. The first team stays fixed while the others rotate; an odd
+// field gets a bye, and the team drawn against it sits the round out.
+//
+// Home and away alternate so that over a single leg no team hosts more than
+// one game more than it visits (exactly as many in odd fields), and the
+// second leg mirrors the first with venues swapped.
+func RoundRobin(teams []uuid.UUID, legs int) ([]Fixture, error) {
+	if legs != 1 && legs != 2 {
+		return nil, ErrInvalidLegs
+	}
+	if len(teams) < 2 {
+		return nil, ErrTooFewTeams
+	}
+	seen := make(map[uuid.UUID]bool, len(teams))
+	for _, team := range teams {
+		if seen[team] || team == uuid.Nil {
+			return nil, ErrDuplicateTeam
+		}
+		seen[team] = true
+	}
+
+	circle := append([]uuid.UUID(nil), teams...)
+	if len(circle)%2 == 1 {
+		// The bye takes the fixed position so every real team rotates and
+		// home and away even out over the leg
+		circle = append([]uuid.UUID{uuid.Nil}, circle...)
+	}
+	n := len(circle)
+	rounds := n - 1
+
+	var first []Fixture
+	for round := 0; round < rounds; round++ {
+		for i := 0; i < n/2; i++ {
+			a, b := circle[i], circle[n-1-i]
+			if a == uuid.Nil || b == uuid.Nil {
+				continue
+			}
+			// The fixed team alternates by round; the rotating pairs alternate
+			// by their distance from it, which flips every round as they move
+			home, away := a, b
+			if (i == 0 && round%2 == 1) || (i > 0 && i%2 == 1) {
+				home, away = b, a
+			}
+			first = append(first, Fixture{Round: round + 1, Leg: 1, Home: home, Away: away})
+		}
+		// Rotate every team but the first one place clockwise
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+
+	fixtures := first
+	if legs == 2 {
+		for _, f := range first {
+			fixtures = append(fixtures, Fixture{Round: f.Round + rounds, Leg: 2, Home: f.Away, Away: f.Home})
+		}
+	}
+	return fixtures, nil
+}
@@ -0,0 +1,149 @@
+package fixtures
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTeams(n int) []uuid.UUID {
+	teams := make([]uuid.UUID, n)
+	for i := range teams {
+		teams[i] = uuid.New()
+	}
+	return teams
+}
+
+// pair keys a meeting regardless of venue
+func pair(a, b uuid.UUID) [2]uuid.UUID {
+	if a.String() < b.String() {
+		return [2]uuid.UUID{a, b}
+	}
+	return [2]uuid.UUID{b, a}
+}
+
+func TestRoundRobin(t *testing.T) {
+	for teams := 2; teams <= 12; teams++ {
+		for _, legs := range []int{1, 2} {
+			field := newTeams(teams)
+			fixtures, err := RoundRobin(field, legs)
+			if err != nil {
+				t.Fatalf("%d teams, %d legs: %v", teams, legs, err)
+			}
+
+			rounds := teams - 1
+			if teams%2 == 1 {
+				rounds = teams // One more round for the bye
+			}
+			perRound := teams / 2
+			if want := rounds * perRound * legs; len(fixtures) != want {
+				t.Errorf("%d teams, %d legs: %d fixtures, want %d", teams, legs, len(fixtures), want)
+			}
+
+			meetings := map[int]map[[2]uuid.UUID]int{}
+			inRound := map[int]map[uuid.UUID]bool{}
+			for _, f := range fixtures {
+				if f.Home == f.Away || f.Home == uuid.Nil || f.Away == uuid.Nil {
+					t.Fatalf("%d teams: bad fixture %+v", teams, f)
+				}
+				if f.Round < 1 || f.Round > rounds*legs || f.Leg != (f.Round-1)/rounds+1 {
+					t.Errorf("%d teams, %d legs: round %d in leg %d", teams, legs, f.Round, f.Leg)
+				}
+				if meetings[f.Leg] == nil {
+					meetings[f.Leg] = map[[2]uuid.UUID]int{}
+				}
+				meetings[f.Leg][pair(f.Home, f.Away)]++
+				if inRound[f.Round] == nil {
+					inRound[f.Round] = map[uuid.UUID]bool{}
+				}
+				for _, team := range []uuid.UUID{f.Home, f.Away} {
+					if inRound[f.Round][team] {
+						t.Errorf("%d teams: team plays twice in round %d", teams, f.Round)
+					}
+					inRound[f.Round][team] = true
+				}
+			}
+
+			for leg := 1; leg <= legs; leg++ {
+				for i := range field {
+					for j := i + 1; j < len(field); j++ {
+						if n := meetings[leg][pair(field[i], field[j])]; n != 1 {
+							t.Errorf("%d teams, leg %d: pair met %d times", teams, leg, n)
+						}
+					}
+				}
+			}
+
+			if teams%2 == 1 {
+				// Every team sits out exactly one round per leg
+				for _, team := range field {
+					byes := 0
+					for round := 1; round <= rounds*legs; round++ {
+						if !inRound[round][team] {
+							byes++
+						}
+					}
+					if byes != legs {
+						t.Errorf("%d teams, %d legs: team has %d byes", teams, legs, byes)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRoundRobinHomeAway(t *testing.T) {
+	for teams := 2; teams <= 12; teams++ {
+		field := newTeams(teams)
+		single, _ := RoundRobin(field, 1)
+		home, away := map[uuid.UUID]int{}, map[uuid.UUID]int{}
+		for _, f := range single {
+			home[f.Home]++
+			away[f.Away]++
+		}
+		for _, team := range field {
+			diff := home[team] - away[team]
+			if diff < -1 || diff > 1 {
+				t.Errorf("%d teams: home %d away %d", teams, home[team], away[team])
+			}
+			if teams%2 == 1 && diff != 0 {
+				t.Errorf("%d teams (odd): home %d away %d, want equal", teams, home[team], away[team])
+			}
+		}
+
+		// The second leg swaps the venue of every first leg fixture
+		double, _ := RoundRobin(field, 2)
+		legs := map[int]map[[2]uuid.UUID]bool{1: {}, 2: {}}
+		for _, f := range double {
+			legs[f.Leg][[2]uuid.UUID{f.Home, f.Away}] = true
+		}
+		for venue := range legs[1] {
+			if !legs[2][[2]uuid.UUID{venue[1], venue[0]}] {
+				t.Errorf("%d teams: second leg doesn't mirror a first leg fixture", teams)
+			}
+		}
+	}
+}
+
+func TestRoundRobinErrors(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	cases := []struct {
+		name  string
+		teams []uuid.UUID
+		legs  int
+		want  error
+	}{
+		{"no teams", nil, 1, ErrTooFewTeams},
+		{"one team", []uuid.UUID{a}, 1, ErrTooFewTeams},
+		{"duplicate", []uuid.UUID{a, b, a}, 1, ErrDuplicateTeam},
+		{"nil team", []uuid.UUID{a, uuid.Nil}, 1, ErrDuplicateTeam},
+		{"zero legs", []uuid.UUID{a, b}, 0, ErrInvalidLegs},
+		{"three legs", []uuid.UUID{a, b}, 3, ErrInvalidLegs},
+	}
+	for _, c := range cases {
+		if _, err := RoundRobin(c.teams, c.legs); !errors.Is(err, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package fixtures
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const dayLayout = "2006-01-02"
+
+// Slot is a start time at a venue that can host one match
+type Slot struct {
+	Start time.Time `json:"scheduled_at"`
+	Venue string    `json:"venue"`
+}
+
+// Blackouts lists, per team, the dates on which it cannot play
+type Blackouts map[uuid.UUID][]time.Time
+
+// Assignment is a fixture placed in a slot
+type Assignment struct {
+	Fixture
+	Slot
+}
+
+// Assign places fixtures, in order, into the earliest slot that
+//   - no other fixture uses,
+//   - falls on a day neither team is blacked out or already playing, and
+//   - starts after both teams' previously assigned matches, so each team
+//     plays its rounds in order.
+//
+// Fixtures that fit no slot are returned unassigned so callers can ask for
+// more dates or venues. Days are compared in each slot's own location.
+func Assign(fixtures []Fixture, slots []Slot, blackouts Blackouts) ([]Assignment, []Fixture) {
+	ordered := append([]Slot(nil), slots...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].Start.Equal(ordered[j].Start) {
+			return ordered[i].Start.Before(ordered[j].Start)
+		}
+		return ordered[i].Venue < ordered[j].Venue
+	})
+
+	blocked := make(map[uuid.UUID]map[string]bool, len(blackouts))
+	for team, dates := range blackouts {
+		blocked[team] = make(map[string]bool, len(dates))
+		for _, date := range dates {
+			blocked[team][date.Format(dayLayout)] = true
+		}
+	}
+
+	used := make([]bool, len(ordered))
+	playing := make(map[uuid.UUID]map[string]bool)
+	latest := make(map[uuid.UUID]time.Time)
+
+	free := func(team uuid.UUID, slot Slot) bool {
+		day := slot.Start.Format(dayLayout)
+		if blocked[team][day] || playing[team][day] {
+			return false
+		}
+		last, ok := latest[team]
+		return !ok || slot.Start.After(last)
+	}
+
+	var assigned []Assignment
+	var unassigned []Fixture
+	for _, fixture := range fixtures {
+		placed := false
+		for i, slot := range ordered {
+			if used[i] || !free(fixture.Home, slot) || !free(fixture.Away, slot) {
+				continue
+			}
+			used[i] = true
+			day := slot.Start.Format(dayLayout)
+			for _, team := range []uuid.UUID{fixture.Home, fixture.Away} {
+				if playing[team] == nil {
+					playing[team] = make(map[string]bool)
+				}
+				playing[team][day] = true
+				latest[team] = slot.Start
+			}
+			assigned = append(assigned, Assignment{Fixture: fixture, Slot: slot})
+			placed = true
+			break
+		}
+		if !placed {
+			unassigned = append(unassigned, fixture)
+		}
+	}
+	return assigned, unassigned
+}
+
+// Slots builds the slots for every combination of date, start time and venue.
+// Start times are offsets from midnight of each date.
+func Slots(dates []time.Time, startTimes []time.Duration, venues []string) []Slot {
+	slots := make([]Slot, 0, len(dates)*len(startTimes)*len(venues))
+	for _, date := range dates {
+		midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+		for _, start := range startTimes {
+			for _, venue := range venues {
+				slots = append(slots, Slot{Start: midnight.Add(start), Venue: venue})
+			}
+		}
+	}
+	return slots
+}
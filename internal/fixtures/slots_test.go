@@ -0,0 +1,124 @@
+package fixtures
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func days(from time.Time, n int) []time.Time {
+	dates := make([]time.Time, n)
+	for i := range dates {
+		dates[i] = from.AddDate(0, 0, i)
+	}
+	return dates
+}
+
+func TestSlots(t *testing.T) {
+	day := time.Date(2026, time.March, 7, 15, 30, 0, 0, time.UTC)
+	slots := Slots(days(day, 2), []time.Duration{18 * time.Hour, 20 * time.Hour}, []string{"Court 1", "Court 2"})
+	if len(slots) != 8 {
+		t.Fatalf("got %d slots, want 8", len(slots))
+	}
+	first := time.Date(2026, time.March, 7, 18, 0, 0, 0, time.UTC)
+	if !slots[0].Start.Equal(first) || slots[0].Venue != "Court 1" {
+		t.Errorf("first slot %+v, want %v at Court 1", slots[0], first)
+	}
+	if last := first.AddDate(0, 0, 1).Add(2 * time.Hour); !slots[7].Start.Equal(last) || slots[7].Venue != "Court 2" {
+		t.Errorf("last slot %+v, want %v at Court 2", slots[7], last)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	start := time.Date(2026, time.March, 7, 0, 0, 0, 0, time.UTC)
+	evening := []time.Duration{19 * time.Hour}
+	two := []string{"Court 1", "Court 2"}
+
+	cases := []struct {
+		name       string
+		teams      int
+		legs       int
+		dates      int
+		startTimes []time.Duration
+		venues     []string
+		blackout   int // Days from start the first team can't play, or -1
+		unassigned int
+	}{
+		{"even field, enough slots", 4, 1, 3, evening, two, -1, 0},
+		{"odd field, enough slots", 5, 1, 5, evening, two, -1, 0},
+		{"double round-robin", 4, 2, 6, evening, two, -1, 0},
+		{"one venue forces extra days", 4, 1, 6, evening, []string{"Court 1"}, -1, 0},
+		{"second start time doesn't double book teams", 4, 1, 3, []time.Duration{17 * time.Hour, 19 * time.Hour}, two, -1, 0},
+		{"slots run out", 4, 1, 2, evening, two, -1, 2},
+		{"one venue and too few days", 6, 1, 5, evening, []string{"Court 1"}, -1, 10},
+		{"blackout pushes fixtures past the last date", 4, 1, 3, evening, two, 0, 2},
+		{"blackout absorbed by a spare date", 4, 1, 4, evening, two, 1, 0},
+	}
+	for _, c := range cases {
+		teams := newTeams(c.teams)
+		fixtures, err := RoundRobin(teams, c.legs)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		blackouts := Blackouts{}
+		if c.blackout >= 0 {
+			blackouts[teams[0]] = []time.Time{start.AddDate(0, 0, c.blackout)}
+		}
+		slots := Slots(days(start, c.dates), c.startTimes, c.venues)
+		assigned, unassigned := Assign(fixtures, slots, blackouts)
+
+		if len(unassigned) != c.unassigned {
+			t.Errorf("%s: %d unassigned, want %d", c.name, len(unassigned), c.unassigned)
+		}
+		if len(assigned)+len(unassigned) != len(fixtures) {
+			t.Errorf("%s: %d assigned and %d unassigned of %d fixtures", c.name, len(assigned), len(unassigned), len(fixtures))
+		}
+
+		used := map[Slot]bool{}
+		playing := map[uuid.UUID]map[string]bool{}
+		latest := map[uuid.UUID]Assignment{}
+		for _, a := range assigned {
+			if used[a.Slot] {
+				t.Errorf("%s: slot %+v used twice", c.name, a.Slot)
+			}
+			used[a.Slot] = true
+			day := a.Start.Format(dayLayout)
+			for _, team := range []uuid.UUID{a.Home, a.Away} {
+				for _, date := range blackouts[team] {
+					if date.Format(dayLayout) == day {
+						t.Errorf("%s: team plays on its blackout date %s", c.name, day)
+					}
+				}
+				if playing[team] == nil {
+					playing[team] = map[string]bool{}
+				}
+				if playing[team][day] {
+					t.Errorf("%s: team plays twice on %s", c.name, day)
+				}
+				playing[team][day] = true
+				if prev, ok := latest[team]; ok && (!a.Start.After(prev.Start) || a.Round < prev.Round) {
+					t.Errorf("%s: round %d at %v doesn't follow round %d at %v", c.name, a.Round, a.Start, prev.Round, prev.Start)
+				}
+				latest[team] = a
+			}
+		}
+	}
+}
+
+func TestAssignBlackoutDayInSlotLocation(t *testing.T) {
+	// 23:30 on the 7th in New York is already the 8th in UTC
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	home, away := uuid.New(), uuid.New()
+	fixtures := []Fixture{{Round: 1, Leg: 1, Home: home, Away: away}}
+	slots := []Slot{{Start: time.Date(2026, time.March, 7, 23, 30, 0, 0, ny), Venue: "Court 1"}}
+	blackouts := Blackouts{away: {time.Date(2026, time.March, 7, 0, 0, 0, 0, ny)}}
+
+	assigned, unassigned := Assign(fixtures, slots, blackouts)
+	if len(assigned) != 0 || len(unassigned) != 1 {
+		t.Errorf("got %d assigned and %d unassigned, want the fixture unassigned", len(assigned), len(unassigned))
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"echo-golang/internal/utils"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -38,7 +37,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(req)
+	response, err := h.authService.Login(req, auditContext(c))
 	if err != nil {
 		utils.Unauthorized(c, err.Error())
 		return
@@ -66,7 +65,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.Register(req)
+	user, err := h.authService.Register(req, auditContext(c))
 	if err != nil {
 		utils.BadRequest(c, err.Error(), nil)
 		return
@@ -124,37 +123,21 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Validate refresh token
-	claims, err := utils.ValidateToken(req.RefreshToken)
+	response, err := h.authService.RefreshToken(req.RefreshToken, auditContext(c))
 	if err != nil {
-		utils.Unauthorized(c, "Invalid refresh token")
-		return
-	}
-
-	// Get user
-	user, err := h.authService.GetCurrentUser(claims.UserID)
-	if err != nil {
-		utils.Unauthorized(c, "User not found")
-		return
-	}
-
-	// Generate new tokens
-	accessToken, err := utils.GenerateToken(user)
-	if err != nil {
-		utils.InternalServerError(c, "Failed to generate token")
-		return
-	}
-
-	refreshToken, err := utils.GenerateRefreshToken(user)
-	if err != nil {
-		utils.InternalServerError(c, "Failed to generate refresh token")
+		utils.Unauthorized(c, err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, gin.H{
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-		"expires_in":    900, // 15 minutes
+		"access_token":  response.AccessToken,
+		"refresh_token": response.RefreshToken,
+		"expires_in":    response.ExpiresIn,
 	}, "Token refreshed successfully")
 }
 
+// auditContext builds the audit context for the current request
+func auditContext(c *gin.Context) services.AuditContext {
+	user, _ := middleware.GetUserFromContext(c)
+	return services.NewAuditContext(user, c.ClientIP(), middleware.GetRequestIDFromContext(c))
+}
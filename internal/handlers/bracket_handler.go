@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BracketHandler struct {
+	bracketService *services.BracketService
+}
+
+func NewBracketHandler() *BracketHandler {
+	return &BracketHandler{
+		bracketService: services.NewBracketService(),
+	}
+}
+
+// GetBracket returns a tournament's knockout bracket round by round
+// @Summary Get tournament bracket
+// @Tags brackets
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} services.BracketView
+// @Failure 404 {object} utils.APIResponse
+// @Router /tournaments/{id}/bracket [get]
+func (h *BracketHandler) GetBracket(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	view, err := h.bracketService.GetBracket(id)
+	if err != nil {
+		respondBracketError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, view, "Bracket retrieved")
+}
+
+// GenerateBracket seeds the registered teams into a knockout bracket
+// @Summary Generate tournament bracket
+// @Tags brackets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param request body services.GenerateBracketRequest true "Format, seeding and number of teams"
+// @Success 201 {object} services.BracketView
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tournaments/{id}/bracket/generate [post]
+func (h *BracketHandler) GenerateBracket(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	var req services.GenerateBracketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	view, err := h.bracketService.GenerateBracket(actor, id, req, auditContext(c))
+	if err != nil {
+		respondBracketError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    view,
+		Message: "Bracket generated",
+	})
+}
+
+// DeleteBracket removes a bracket before any of its games are scheduled
+// @Summary Delete tournament bracket
+// @Tags brackets
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tournaments/{id}/bracket [delete]
+func (h *BracketHandler) DeleteBracket(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	if err := h.bracketService.DeleteBracket(actor, id, auditContext(c)); err != nil {
+		respondBracketError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Bracket deleted")
+}
+
+// ScheduleMatch creates the match of a bracket game whose teams are known
+// @Summary Schedule bracket game
+// @Tags brackets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param gameId path string true "Bracket game ID"
+// @Param request body services.ScheduleBracketMatchRequest true "Time and venue"
+// @Success 201 {object} models.Match
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tournaments/{id}/bracket/games/{gameId}/match [post]
+func (h *BracketHandler) ScheduleMatch(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+	gameID, err := uuid.Parse(c.Param("gameId"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid bracket game ID", nil)
+		return
+	}
+
+	var req services.ScheduleBracketMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	match, err := h.bracketService.ScheduleMatch(actor, id, gameID, req, auditContext(c))
+	if err != nil {
+		respondBracketError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    match,
+		Message: "Bracket match scheduled",
+	})
+}
+
+func respondBracketError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrBracketNotFound),
+		errors.Is(err, services.ErrBracketNodeNotFound):
+		utils.NotFound(c, err.Error())
+	case errors.Is(err, services.ErrBracketExists),
+		errors.Is(err, services.ErrBracketStarted),
+		errors.Is(err, services.ErrBracketNodeNotReady),
+		errors.Is(err, services.ErrBracketNodeScheduled):
+		utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+	case errors.Is(err, services.ErrInvalidBracketSize),
+		errors.Is(err, services.ErrTooFewForDoubleElim):
+		utils.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, services.ErrScheduleConflict),
+		errors.Is(err, services.ErrOutsideTournamentDates),
+		errors.Is(err, services.ErrTeamNotInTournament):
+		respondMatchError(c, err)
+	default:
+		respondTournamentError(c, err)
+	}
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GameHandler struct {
+	gameService *services.GameService
+}
+
+func NewGameHandler() *GameHandler {
+	return &GameHandler{
+		gameService: services.NewGameService(),
+	}
+}
+
+// GetGame returns the period and game clock of a match
+// @Summary Get game state
+// @Tags game
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} services.GameClock
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/game [get]
+func (h *GameHandler) GetGame(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	game, err := h.gameService.GetGame(viewer, id)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, game, "Game state retrieved")
+}
+
+// StartPeriod starts the next period of a live match
+// @Summary Start period
+// @Tags game
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} services.GameClock
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/game/periods/start [post]
+func (h *GameHandler) StartPeriod(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	game, err := h.gameService.StartPeriod(actor, id)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, game, "Period started")
+}
+
+// EndPeriod ends the current period once its clock has run out
+// @Summary End period
+// @Tags game
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} services.GameClock
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/game/periods/end [post]
+func (h *GameHandler) EndPeriod(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	game, err := h.gameService.EndPeriod(actor, id, auditContext(c))
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, game, "Period ended")
+}
+
+// StartClock starts the game clock
+// @Summary Start game clock
+// @Tags game
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} services.GameClock
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/game/clock/start [post]
+func (h *GameHandler) StartClock(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	game, err := h.gameService.StartClock(actor, id)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, game, "Clock started")
+}
+
+// StopClock stops the game clock
+// @Summary Stop game clock
+// @Tags game
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} services.GameClock
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/game/clock/stop [post]
+func (h *GameHandler) StopClock(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	game, err := h.gameService.StopClock(actor, id)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, game, "Clock stopped")
+}
+
+// SetClock corrects the time left on a stopped clock
+// @Summary Set game clock
+// @Tags game
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param request body services.SetClockRequest true "Time left"
+// @Success 200 {object} services.GameClock
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/game/clock [put]
+func (h *GameHandler) SetClock(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	var req services.SetClockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	game, err := h.gameService.SetClock(actor, id, req)
+	if err != nil {
+		respondGameError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, game, "Clock set")
+}
+
+func respondGameError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidGameTransition),
+		errors.Is(err, services.ErrClockRunning),
+		errors.Is(err, services.ErrClockNotRunning),
+		errors.Is(err, services.ErrClockExpired),
+		errors.Is(err, services.ErrPeriodNotOver):
+		utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+	case errors.Is(err, services.ErrInvalidClock):
+		utils.BadRequest(c, err.Error(), nil)
+	default:
+		respondMatchEventError(c, err)
+	}
+}
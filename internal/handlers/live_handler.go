@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-golang/internal/config"
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	liveWriteWait  = 10 * time.Second // Time allowed to write a message to the client
+	livePongWait   = 60 * time.Second // Time allowed between pongs from the client
+	livePingPeriod = livePongWait * 9 / 10
+	liveReadLimit  = 512 // Clients only send control frames
+
+	streamHeartbeat = 15 * time.Second // Keeps proxies from closing an idle event stream
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	CheckOrigin:     allowedOrigin,
+}
+
+// allowedOrigin accepts a WebSocket upgrade from an origin listed in
+// CORS_ALLOWED_ORIGINS. CORS doesn't apply to WebSockets, so this is the only
+// check against other sites opening a feed in a visitor's browser. A "*"
+// entry doesn't open the feed to every site: it allows the server's own
+// origin only, as when no origins are listed. Requests without an Origin come
+// from clients other than browsers and are let through.
+func allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range config.AppConfig.CORSAllowedOrigins {
+		if allowed != "*" && strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+type LiveHandler struct {
+	liveService *services.LiveService
+}
+
+func NewLiveHandler() *LiveHandler {
+	return &LiveHandler{
+		liveService: services.NewLiveService(),
+	}
+}
+
+// CreateStreamTicket issues a short-lived ticket that opens the live feed of
+// a match, for browsers that can't send the token in a header
+// @Summary Create live stream ticket
+// @Tags live
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 201 {object} services.StreamTicket
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/stream-tickets [post]
+func (h *LiveHandler) CreateStreamTicket(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	user, _ := middleware.GetUserFromContext(c)
+	ticket, err := h.liveService.IssueStreamTicket(user, id)
+	if err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    ticket,
+		Message: "Stream ticket issued",
+	})
+}
+
+// MatchSocket streams the live updates of a match over a WebSocket: the events
+// after ?after= when resuming, a snapshot of the game, then every event and
+// match change as it happens. Private matches need a token in the
+// Authorization header or a stream ticket as ?ticket=.
+// @Summary Follow live match updates
+// @Tags live
+// @Param id path string true "Match ID"
+// @Param after query int false "Resume after this event sequence number"
+// @Param ticket query string false "Stream ticket for private matches"
+// @Success 101
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /ws/matches/{id}/live [get]
+func (h *LiveHandler) MatchSocket(c *gin.Context) {
+	feed, ok := h.follow(c, c.Query("after"))
+	if !ok {
+		return
+	}
+	defer feed.Close()
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade has already responded
+		return
+	}
+	defer conn.Close()
+
+	// Reads only serve to handle pongs and notice the client leaving
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		conn.SetReadLimit(liveReadLimit)
+		conn.SetReadDeadline(time.Now().Add(livePongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(livePongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(payload []byte) error {
+		conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+		return conn.WriteMessage(websocket.TextMessage, payload)
+	}
+	for _, msg := range feed.Backlog {
+		if err := send(msg.Payload); err != nil {
+			return
+		}
+	}
+	if err := send(feed.Snapshot.Payload); err != nil {
+		return
+	}
+
+	ping := time.NewTicker(livePingPeriod)
+	defer ping.Stop()
+	for {
+		select {
+		case msg, ok := <-feed.Subscription.C:
+			if !ok {
+				// The hub dropped a client that fell a full buffer behind; it
+				// reconnects and catches up from the last sequence it saw
+				if feed.Subscription.Dropped() {
+					reason := fmt.Sprintf("too far behind, reconnect with after=%d", feed.Sequence)
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, reason),
+						time.Now().Add(liveWriteWait))
+				}
+				return
+			}
+			for _, msg := range feed.Deliver(msg) {
+				if err := send(msg.Payload); err != nil {
+					return
+				}
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(liveWriteWait)); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+// MatchStream streams the live updates of a match as Server-Sent Events, for
+// clients whose networks can't keep a WebSocket open. It sends the same
+// messages as the WebSocket, each event with its sequence number as the event
+// ID so EventSource resumes through Last-Event-ID on its own.
+// @Summary Stream live match updates
+// @Tags live
+// @Produce text/event-stream
+// @Param id path string true "Match ID"
+// @Param Last-Event-ID header int false "Resume after this event sequence number"
+// @Param after query int false "Resume after this event sequence number, when Last-Event-ID is not sent"
+// @Param ticket query string false "Stream ticket for private matches"
+// @Success 200
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/stream [get]
+func (h *LiveHandler) MatchStream(c *gin.Context) {
+	resume := c.GetHeader("Last-Event-ID")
+	if resume == "" {
+		resume = c.Query("after")
+	}
+	feed, ok := h.follow(c, resume)
+	if !ok {
+		return
+	}
+	defer feed.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	// A client that stops reading blocks the write until the deadline; the
+	// hub meanwhile drops it once its buffer fills up
+	rc := http.NewResponseController(c.Writer)
+	send := func(event sse.Event) bool {
+		rc.SetWriteDeadline(time.Now().Add(liveWriteWait))
+		c.Render(-1, event)
+		c.Writer.Flush()
+		return !c.IsAborted() && c.Request.Context().Err() == nil
+	}
+	message := func(sequence int, payload []byte) sse.Event {
+		event := sse.Event{Data: string(payload)}
+		if sequence > 0 {
+			event.Id = strconv.Itoa(sequence)
+		}
+		return event
+	}
+
+	for _, msg := range feed.Backlog {
+		if !send(message(msg.Sequence, msg.Payload)) {
+			return
+		}
+	}
+	if !send(message(feed.Sequence, feed.Snapshot.Payload)) {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case msg, ok := <-feed.Subscription.C:
+			if !ok {
+				// Dropped for falling behind: EventSource reconnects with
+				// the last event ID it saw
+				return
+			}
+			for _, msg := range feed.Deliver(msg) {
+				if !send(message(msg.Sequence, msg.Payload)) {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			rc.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// follow opens the live feed of the requested match, resuming after the
+// given sequence number, or responds with the error
+func (h *LiveHandler) follow(c *gin.Context, resume string) (*services.LiveFeed, bool) {
+	id, ok := matchID(c)
+	if !ok {
+		return nil, false
+	}
+	after, ok := resumeAfter(c, resume)
+	if !ok {
+		return nil, false
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	feed, err := h.liveService.Follow(viewer, id, after)
+	if err != nil {
+		respondMatchError(c, err)
+		return nil, false
+	}
+	return feed, true
+}
+
+// resumeAfter parses the sequence number a client resumes after; -1 when the
+// client isn't resuming
+func resumeAfter(c *gin.Context, value string) (int, bool) {
+	if value == "" {
+		return -1, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		utils.BadRequest(c, "after must be a sequence number", nil)
+		return 0, false
+	}
+	return n, true
+}
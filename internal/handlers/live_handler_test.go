@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"echo-golang/internal/config"
+)
+
+func TestAllowedOrigin(t *testing.T) {
+	saved := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = saved })
+
+	cases := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{"listed origin", []string{"https://app.example.com"}, "https://app.example.com", true},
+		{"listed with a trailing slash", []string{"https://app.example.com/"}, "https://app.example.com", true},
+		{"unlisted origin", []string{"https://app.example.com"}, "https://evil.example.net", false},
+		{"other scheme", []string{"https://app.example.com"}, "http://app.example.com", false},
+		{"wildcard allows the server's own origin", []string{"*"}, "https://api.example.com", true},
+		{"wildcard allows no other site", []string{"*"}, "https://evil.example.net", false},
+		{"no origin from a non-browser client", []string{"https://app.example.com"}, "", true},
+		{"malformed origin", []string{"*"}, "://", false},
+	}
+	for _, c := range cases {
+		config.AppConfig = &config.Config{CORSAllowedOrigins: c.allowed}
+		r := httptest.NewRequest("GET", "http://api.example.com/ws/matches/1/live", nil)
+		if c.origin != "" {
+			r.Header.Set("Origin", c.origin)
+		}
+		if got := allowedOrigin(r); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/narration"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MatchEventHandler struct {
+	eventService *services.MatchEventService
+}
+
+func NewMatchEventHandler() *MatchEventHandler {
+	return &MatchEventHandler{
+		eventService: services.NewMatchEventService(),
+	}
+}
+
+// ListEvents lists the live events of a match. Clients resync by passing the
+// last sequence number they have seen.
+// @Summary List match events
+// @Tags match-events
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param after query int false "Only events with a greater sequence number"
+// @Success 200 {object} services.MatchEventLog
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/events [get]
+func (h *MatchEventHandler) ListEvents(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+	after := 0
+	if value := c.Query("after"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			utils.BadRequest(c, "after must be a sequence number", nil)
+			return
+		}
+		after = n
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	log, err := h.eventService.ListEvents(viewer, id, after)
+	if err != nil {
+		respondMatchEventError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, log, "Events retrieved")
+}
+
+// GetPlayByPlay returns a page of a match's events narrated as commentary,
+// newest first, with the running score, period and clock of each
+// @Summary Get match play-by-play
+// @Tags match-events
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param before query int false "Only plays older than this sequence number"
+// @Param after query int false "Only plays newer than this sequence number"
+// @Param limit query int false "Plays per page (default 25, max 100)"
+// @Param scoring query bool false "Only scoring plays"
+// @Param player_id query string false "Only plays involving a player"
+// @Param lang query string false "Narration language: en (default), es or fr; Accept-Language is used without it"
+// @Success 200 {object} services.PlayByPlay
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/play-by-play [get]
+func (h *MatchEventHandler) GetPlayByPlay(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+	q := services.PlayByPlayQuery{
+		Limit:    services.DefaultPlayByPlayLimit,
+		Language: narration.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language")),
+	}
+	for key, target := range map[string]*int{"before": &q.Before, "after": &q.After} {
+		if value := c.Query(key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				utils.BadRequest(c, key+" must be a sequence number", nil)
+				return
+			}
+			*target = n
+		}
+	}
+	if q.Before > 0 && q.After > 0 {
+		utils.BadRequest(c, "Use either before or after, not both", nil)
+		return
+	}
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 || n > services.MaxPlayByPlayLimit {
+			utils.BadRequest(c, "limit must be between 1 and "+strconv.Itoa(services.MaxPlayByPlayLimit), nil)
+			return
+		}
+		q.Limit = n
+	}
+	if value := c.Query("scoring"); value != "" {
+		scoring, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.BadRequest(c, "scoring must be true or false", nil)
+			return
+		}
+		q.ScoringOnly = scoring
+	}
+	if value := c.Query("player_id"); value != "" {
+		playerID, err := uuid.Parse(value)
+		if err != nil {
+			utils.BadRequest(c, "Invalid player_id", nil)
+			return
+		}
+		q.PlayerID = &playerID
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	feed, err := h.eventService.GetPlayByPlay(viewer, id, q)
+	if err != nil {
+		respondMatchEventError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, feed, "Play-by-play retrieved")
+}
+
+// RecordEvent records a live event of a match
+// @Summary Record match event
+// @Tags match-events
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param request body services.RecordEventRequest true "Event"
+// @Success 201 {object} models.MatchEvent
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/events [post]
+func (h *MatchEventHandler) RecordEvent(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	var req services.RecordEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	event, err := h.eventService.RecordEvent(actor, id, req)
+	if err != nil {
+		respondMatchEventError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    event,
+		Message: "Event recorded",
+	})
+}
+
+// UndoEvent voids the latest event of a match that is still in effect
+// @Summary Undo last match event
+// @Tags match-events
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 201 {object} models.MatchEvent
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/events/undo [post]
+func (h *MatchEventHandler) UndoEvent(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	void, err := h.eventService.UndoLastEvent(actor, id)
+	if err != nil {
+		respondMatchEventError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    void,
+		Message: "Event voided",
+	})
+}
+
+// ListScorekeepers lists the scorekeepers of a match
+// @Summary List match scorekeepers
+// @Tags match-events
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {array} models.MatchScorekeeper
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/scorekeepers [get]
+func (h *MatchEventHandler) ListScorekeepers(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	scorekeepers, err := h.eventService.ListScorekeepers(id)
+	if err != nil {
+		respondMatchEventError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, scorekeepers, "Scorekeepers retrieved")
+}
+
+// AssignScorekeeper allows a user to record the events of a match
+// @Summary Assign match scorekeeper
+// @Tags match-events
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param request body services.AssignScorekeeperRequest true "User"
+// @Success 201 {object} models.MatchScorekeeper
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/scorekeepers [post]
+func (h *MatchEventHandler) AssignScorekeeper(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	var req services.AssignScorekeeperRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	scorekeeper, err := h.eventService.AssignScorekeeper(actor, id, req, auditContext(c))
+	if err != nil {
+		respondMatchEventError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    scorekeeper,
+		Message: "Scorekeeper assigned",
+	})
+}
+
+// RemoveScorekeeper withdraws a user's scorekeeping rights for a match
+// @Summary Remove match scorekeeper
+// @Tags match-events
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param userId path string true "User ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/scorekeepers/{userId} [delete]
+func (h *MatchEventHandler) RemoveScorekeeper(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID", nil)
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	if err := h.eventService.RemoveScorekeeper(actor, id, userID, auditContext(c)); err != nil {
+		respondMatchEventError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Scorekeeper removed")
+}
+
+func respondMatchEventError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		utils.Forbidden(c, "Only the match's managers and scorekeepers can record its events")
+	case errors.Is(err, services.ErrUserNotFound):
+		utils.NotFound(c, "User not found")
+	case errors.Is(err, services.ErrScorekeeperNotFound):
+		utils.NotFound(c, err.Error())
+	case errors.Is(err, services.ErrMatchNotLive),
+		errors.Is(err, services.ErrGameNotStarted),
+		errors.Is(err, services.ErrNoEventToUndo),
+		errors.Is(err, services.ErrScorekeeperExists):
+		utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+	case errors.Is(err, services.ErrInvalidEvent),
+		errors.Is(err, services.ErrEventTeam),
+		errors.Is(err, services.ErrEventPlayer),
+		errors.Is(err, services.ErrShotLocation),
+		errors.Is(err, services.ErrAlreadyOnCourt),
+		errors.Is(err, services.ErrNotOnCourt),
+		errors.Is(err, services.ErrTooManyOnCourt),
+		errors.Is(err, services.ErrInactiveScorekeeper):
+		utils.BadRequest(c, err.Error(), nil)
+	default:
+		respondMatchError(c, err)
+	}
+}
@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/models"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MatchHandler struct {
+	matchService *services.MatchService
+}
+
+func NewMatchHandler() *MatchHandler {
+	return &MatchHandler{
+		matchService: services.NewMatchService(),
+	}
+}
+
+// ListMatches lists matches
+// @Summary List matches
+// @Tags matches
+// @Produce json
+// @Param tournament_id query string false "Filter by tournament"
+// @Param team_id query string false "Filter by team (home or away)"
+// @Param status query string false "Filter by status (scheduled, live, completed, cancelled)"
+// @Param venue query string false "Filter by partial venue"
+// @Param date_from query string false "Matches starting at or after (YYYY-MM-DD or RFC3339)"
+// @Param date_to query string false "Matches starting before, inclusive of a YYYY-MM-DD day"
+// @Success 200 {object} utils.APIResponse
+// @Router /matches [get]
+func (h *MatchHandler) ListMatches(c *gin.Context) {
+	filters, ok := matchFilters(c)
+	if !ok {
+		return
+	}
+	h.listMatches(c, filters)
+}
+
+// ListTournamentMatches lists the matches of a tournament
+// @Summary List tournament matches
+// @Tags tournaments
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param status query string false "Filter by status"
+// @Success 200 {object} utils.APIResponse
+// @Router /tournaments/{id}/matches [get]
+func (h *MatchHandler) ListTournamentMatches(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+	filters, ok := matchFilters(c)
+	if !ok {
+		return
+	}
+	filters["tournament_id"] = id
+	h.listMatches(c, filters)
+}
+
+// ListUpcomingMatches lists scheduled matches that have not started yet
+// @Summary List upcoming matches
+// @Tags matches
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Router /matches/upcoming [get]
+func (h *MatchHandler) ListUpcomingMatches(c *gin.Context) {
+	h.listMatches(c, map[string]interface{}{
+		"status": models.MatchStatusScheduled,
+		"from":   time.Now().UTC(),
+	})
+}
+
+// ListLiveMatches lists matches being played
+// @Summary List live matches
+// @Tags matches
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Router /matches/live [get]
+func (h *MatchHandler) ListLiveMatches(c *gin.Context) {
+	h.listMatches(c, map[string]interface{}{"status": models.MatchStatusLive})
+}
+
+// ListCompletedMatches lists finished matches
+// @Summary List completed matches
+// @Tags matches
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Router /matches/completed [get]
+func (h *MatchHandler) ListCompletedMatches(c *gin.Context) {
+	h.listMatches(c, map[string]interface{}{"status": models.MatchStatusCompleted})
+}
+
+func (h *MatchHandler) listMatches(c *gin.Context, filters map[string]interface{}) {
+	page, limit, offset := utils.GetPagination(c)
+	matches, total, err := h.matchService.ListMatches(offset, limit, filters)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch matches")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"matches": matches,
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	}, "Matches retrieved")
+}
+
+// GetMatch gets a single match
+// @Summary Get match
+// @Tags matches
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} models.Match
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id} [get]
+func (h *MatchHandler) GetMatch(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	match, err := h.matchService.GetMatch(id)
+	if err != nil {
+		utils.NotFound(c, "Match not found")
+		return
+	}
+
+	utils.SuccessResponse(c, match, "Match retrieved")
+}
+
+// CreateMatch schedules a match
+// @Summary Create match
+// @Tags matches
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreateMatchRequest true "Match data"
+// @Success 201 {object} models.Match
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches [post]
+func (h *MatchHandler) CreateMatch(c *gin.Context) {
+	var req services.CreateMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	match, err := h.matchService.CreateMatch(actor, req, auditContext(c))
+	if err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    match,
+		Message: "Match created",
+	})
+}
+
+// UpdateMatch updates a match's officials, notes, status or scores
+// @Summary Update match
+// @Tags matches
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param request body services.UpdateMatchRequest true "Match fields"
+// @Success 200 {object} models.Match
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id} [put]
+func (h *MatchHandler) UpdateMatch(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	match, err := h.matchService.UpdateMatch(actor, id, req, auditContext(c))
+	if err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, match, "Match updated")
+}
+
+// RescheduleMatch moves a scheduled match
+// @Summary Reschedule match
+// @Tags matches
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param request body services.RescheduleMatchRequest true "New time, venue and reason"
+// @Success 200 {object} models.Match
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/reschedule [post]
+func (h *MatchHandler) RescheduleMatch(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	var req services.RescheduleMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	match, err := h.matchService.RescheduleMatch(actor, id, req, auditContext(c))
+	if err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, match, "Match rescheduled")
+}
+
+// CancelMatch cancels a match
+// @Summary Cancel match
+// @Tags matches
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param request body services.CancelMatchRequest true "Cancellation reason"
+// @Success 200 {object} models.Match
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id}/cancel [post]
+func (h *MatchHandler) CancelMatch(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CancelMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	match, err := h.matchService.CancelMatch(actor, id, req, auditContext(c))
+	if err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, match, "Match cancelled")
+}
+
+// DeleteMatch deletes a match
+// @Summary Delete match
+// @Tags matches
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /matches/{id} [delete]
+func (h *MatchHandler) DeleteMatch(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	if err := h.matchService.DeleteMatch(actor, id, auditContext(c)); err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Match deleted successfully")
+}
+
+// matchID parses the :id parameter, responding with 400 when invalid
+func matchID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid match ID", nil)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// matchFilters builds the listing filters from the query string, responding
+// with 400 when one is invalid
+func matchFilters(c *gin.Context) (map[string]interface{}, bool) {
+	filters := map[string]interface{}{}
+	for _, key := range []string{"tournament_id", "team_id"} {
+		if value := c.Query(key); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				utils.BadRequest(c, "Invalid "+key+" filter", nil)
+				return nil, false
+			}
+			filters[key] = id
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		switch models.MatchStatus(status) {
+		case models.MatchStatusScheduled, models.MatchStatusLive, models.MatchStatusCompleted, models.MatchStatusCancelled:
+			filters["status"] = status
+		default:
+			utils.BadRequest(c, "Invalid status filter", nil)
+			return nil, false
+		}
+	}
+	if venue := c.Query("venue"); venue != "" {
+		filters["venue"] = venue
+	}
+	for key, filter := range map[string]string{"date_from": "from", "date_to": "to"} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			filters[filter] = t
+			continue
+		}
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			utils.BadRequest(c, "Invalid "+key+" filter, expected YYYY-MM-DD or RFC3339", nil)
+			return nil, false
+		}
+		if filter == "to" {
+			// A bare date includes the whole day
+			day = day.AddDate(0, 0, 1)
+		}
+		filters[filter] = day
+	}
+	return filters, true
+}
+
+// respondMatchError maps match service errors to HTTP responses
+func respondMatchError(c *gin.Context, err error) {
+	var conflict *services.ScheduleConflictError
+	switch {
+	case errors.As(err, &conflict):
+		utils.ErrorResponse(c, http.StatusConflict, "SCHEDULE_CONFLICT", conflict.Error(), gin.H{
+			"reason":       conflict.Reason,
+			"match_id":     conflict.Match.ID,
+			"scheduled_at": conflict.Match.ScheduledAt,
+			"venue":        conflict.Match.Venue,
+		})
+	case errors.Is(err, services.ErrMatchNotFound):
+		utils.NotFound(c, "Match not found")
+	case errors.Is(err, services.ErrTeamNotFound):
+		utils.NotFound(c, "Team not found")
+	case errors.Is(err, services.ErrTournamentNotFound):
+		utils.NotFound(c, "Tournament not found")
+	case errors.Is(err, services.ErrForbidden):
+		utils.Forbidden(c, "You can only manage matches of your own organization's teams or tournaments")
+	case errors.Is(err, services.ErrPrivateMatch):
+		if _, ok := middleware.GetUserFromContext(c); ok {
+			utils.Forbidden(c, "This match is private to its teams' organizations")
+		} else {
+			utils.Unauthorized(c, "Sign in to follow this private match")
+		}
+	case errors.Is(err, services.ErrInvalidMatchTransition),
+		errors.Is(err, services.ErrMatchNotScheduled),
+		errors.Is(err, services.ErrMatchLocked),
+		errors.Is(err, services.ErrScoresNotEditable),
+		errors.Is(err, services.ErrScoresFromEvents),
+		errors.Is(err, services.ErrTournamentCompleted),
+		errors.Is(err, services.ErrBracketLocked):
+		utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+	case errors.Is(err, services.ErrSameTeams),
+		errors.Is(err, services.ErrTeamInactive),
+		errors.Is(err, services.ErrTeamNotInTournament),
+		errors.Is(err, services.ErrOutsideTournamentDates),
+		errors.Is(err, services.ErrMatchTied):
+		utils.BadRequest(c, err.Error(), nil)
+	default:
+		utils.InternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/models"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OrganizationHandler serves the organization admin self-service portal.
+// Every route is scoped to the :id organization by RequireOrganizationManager.
+type OrganizationHandler struct {
+	orgService  *services.OrganizationService
+	userService *services.UserService
+}
+
+func NewOrganizationHandler() *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService:  services.NewOrganizationService(),
+		userService: services.NewUserService(),
+	}
+}
+
+// GetOrganization returns the organization profile
+// @Summary Get organization profile
+// @Tags organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {object} models.Organization
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /orgs/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	org, err := h.orgService.GetOrganization(organizationID(c))
+	if err != nil {
+		utils.NotFound(c, "Organization not found")
+		return
+	}
+
+	utils.SuccessResponse(c, org, "Organization retrieved")
+}
+
+// UpdateOrganization updates the organization profile
+// @Summary Update organization profile
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param request body services.UpdateOrganizationProfileRequest true "Profile fields"
+// @Success 200 {object} models.Organization
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /orgs/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	var req services.UpdateOrganizationProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	org, err := h.orgService.UpdateOrganizationProfile(organizationID(c), req, auditContext(c))
+	if err != nil {
+		if errors.Is(err, services.ErrOrganizationNotFound) {
+			utils.NotFound(c, "Organization not found")
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, org, "Organization updated")
+}
+
+// GetStatistics returns organization-level statistics
+// @Summary Get organization statistics
+// @Tags organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {object} services.OrganizationStats
+// @Failure 403 {object} utils.APIResponse
+// @Router /orgs/{id}/statistics [get]
+func (h *OrganizationHandler) GetStatistics(c *gin.Context) {
+	stats, err := h.orgService.GetOrganizationStats(organizationID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrOrganizationNotFound) {
+			utils.NotFound(c, "Organization not found")
+			return
+		}
+		utils.InternalServerError(c, "Failed to fetch organization statistics")
+		return
+	}
+
+	utils.SuccessResponse(c, stats, "Organization statistics retrieved")
+}
+
+// ListMembers lists the users of the organization
+// @Summary List organization members
+// @Tags organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param role query string false "Filter by role (org_admin, team_member)"
+// @Param status query string false "Filter by status (active, inactive)"
+// @Success 200 {object} utils.APIResponse
+// @Router /orgs/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	filters := map[string]interface{}{"organization_id": organizationID(c)}
+	if role := c.Query("role"); role != "" {
+		if role != string(models.RoleOrgAdmin) && role != string(models.RoleTeamMember) {
+			utils.BadRequest(c, "invalid role filter", nil)
+			return
+		}
+		filters["role"] = role
+	}
+	if status := c.Query("status"); status != "" {
+		if status != string(models.UserStatusActive) && status != string(models.UserStatusInactive) {
+			utils.BadRequest(c, "invalid status filter", nil)
+			return
+		}
+		filters["status"] = status
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	members, total, err := h.userService.ListUsers(offset, limit, filters)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch members")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"members": members,
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	}, "Members retrieved")
+}
+
+// AddMember creates a new user in the organization
+// @Summary Add organization member
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param request body services.CreateMemberRequest true "Member data"
+// @Success 201 {object} models.User
+// @Failure 400 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /orgs/{id}/members [post]
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	var req services.CreateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	member, err := h.userService.AddOrganizationMember(organizationID(c), req, auditContext(c))
+	if err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyTaken) {
+			utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    member,
+		Message: "Member added",
+	})
+}
+
+// UpdateMember updates a member of the organization
+// @Summary Update organization member
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param userId path string true "User ID"
+// @Param request body services.UpdateMemberRequest true "Member fields"
+// @Success 200 {object} models.User
+// @Failure 404 {object} utils.APIResponse
+// @Router /orgs/{id}/members/{userId} [put]
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID", nil)
+		return
+	}
+
+	var req services.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	if currentUserID, _ := middleware.GetUserIDFromContext(c); currentUserID == userID && (req.Role != nil || req.Status != nil) {
+		utils.BadRequest(c, "You cannot change your own role or status", nil)
+		return
+	}
+
+	member, err := h.userService.UpdateOrganizationMember(organizationID(c), userID, req, auditContext(c))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			utils.NotFound(c, "Member not found")
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, member, "Member updated")
+}
+
+// RemoveMember removes a user from the organization without deleting the account
+// @Summary Remove organization member
+// @Tags organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param userId path string true "User ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /orgs/{id}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID", nil)
+		return
+	}
+
+	if currentUserID, _ := middleware.GetUserIDFromContext(c); currentUserID == userID {
+		utils.BadRequest(c, "You cannot remove yourself from the organization", nil)
+		return
+	}
+
+	if err := h.userService.RemoveOrganizationMember(organizationID(c), userID, auditContext(c)); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			utils.NotFound(c, "Member not found")
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Member removed")
+}
+
+// organizationID returns the :id parameter, already validated by RequireOrganizationManager
+func organizationID(c *gin.Context) uuid.UUID {
+	id, _ := uuid.Parse(c.Param("id"))
+	return id
+}
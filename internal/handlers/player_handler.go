@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/models"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PlayerHandler struct {
+	playerService *services.PlayerService
+}
+
+func NewPlayerHandler() *PlayerHandler {
+	return &PlayerHandler{
+		playerService: services.NewPlayerService(),
+	}
+}
+
+// ListPlayers lists players
+// @Summary List players
+// @Tags players
+// @Produce json
+// @Param team_id query string false "Filter by team"
+// @Param organization_id query string false "Filter by organization"
+// @Param position query string false "Filter by position (PG, SG, SF, PF, C)"
+// @Param status query string false "Filter by status (active, inactive)"
+// @Success 200 {object} utils.APIResponse
+// @Router /players [get]
+func (h *PlayerHandler) ListPlayers(c *gin.Context) {
+	filters := map[string]interface{}{}
+	for _, key := range []string{"team_id", "organization_id"} {
+		if value := c.Query(key); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				utils.BadRequest(c, "Invalid "+key+" filter", nil)
+				return
+			}
+			filters[key] = id
+		}
+	}
+	h.listPlayers(c, filters)
+}
+
+// ListTeamPlayers lists the roster of a team
+// @Summary List team roster
+// @Tags players
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param position query string false "Filter by position (PG, SG, SF, PF, C)"
+// @Param status query string false "Filter by status (active, inactive)"
+// @Success 200 {object} utils.APIResponse
+// @Router /teams/{id}/players [get]
+func (h *PlayerHandler) ListTeamPlayers(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+	h.listPlayers(c, map[string]interface{}{"team_id": teamID})
+}
+
+func (h *PlayerHandler) listPlayers(c *gin.Context, filters map[string]interface{}) {
+	if position := c.Query("position"); position != "" {
+		if !models.PlayerPosition(position).IsValid() {
+			utils.BadRequest(c, "Invalid position filter", nil)
+			return
+		}
+		filters["position"] = position
+	}
+	if status := c.Query("status"); status != "" {
+		if status != string(models.PlayerStatusActive) && status != string(models.PlayerStatusInactive) {
+			utils.BadRequest(c, "Invalid status filter", nil)
+			return
+		}
+		filters["status"] = status
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	players, total, err := h.playerService.ListPlayers(offset, limit, filters)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch players")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"players": players,
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	}, "Players retrieved")
+}
+
+// GetPlayer gets a single player
+// @Summary Get player
+// @Tags players
+// @Produce json
+// @Param id path string true "Player ID"
+// @Success 200 {object} models.Player
+// @Failure 404 {object} utils.APIResponse
+// @Router /players/{id} [get]
+func (h *PlayerHandler) GetPlayer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid player ID", nil)
+		return
+	}
+
+	player, err := h.playerService.GetPlayer(id)
+	if err != nil {
+		utils.NotFound(c, "Player not found")
+		return
+	}
+
+	utils.SuccessResponse(c, player, "Player retrieved")
+}
+
+// GetMyPlayer returns the player profile linked to the current user
+// @Summary Get own player profile
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.Player
+// @Failure 404 {object} utils.APIResponse
+// @Router /players/me [get]
+func (h *PlayerHandler) GetMyPlayer(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		utils.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	player, err := h.playerService.GetPlayerForUser(userID)
+	if err != nil {
+		utils.NotFound(c, "No player profile is linked to this account")
+		return
+	}
+
+	utils.SuccessResponse(c, player, "Player retrieved")
+}
+
+// CreatePlayer adds a player to a team
+// @Summary Create player
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreatePlayerRequest true "Player data"
+// @Success 201 {object} models.Player
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /players [post]
+func (h *PlayerHandler) CreatePlayer(c *gin.Context) {
+	var req services.CreatePlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+	if req.TeamID == uuid.Nil {
+		utils.BadRequest(c, "team_id is required", nil)
+		return
+	}
+	h.createPlayer(c, req)
+}
+
+// CreateTeamPlayer adds a player to the roster of a team
+// @Summary Add player to team roster
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body services.CreatePlayerRequest true "Player data"
+// @Success 201 {object} models.Player
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /teams/{id}/players [post]
+func (h *PlayerHandler) CreateTeamPlayer(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+
+	var req services.CreatePlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+	req.TeamID = teamID
+	h.createPlayer(c, req)
+}
+
+func (h *PlayerHandler) createPlayer(c *gin.Context, req services.CreatePlayerRequest) {
+	actor, _ := middleware.GetUserFromContext(c)
+	player, err := h.playerService.CreatePlayer(actor, req, auditContext(c))
+	if err != nil {
+		respondPlayerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    player,
+		Message: "Player created",
+	})
+}
+
+// UpdatePlayer updates a player
+// @Summary Update player
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Player ID"
+// @Param request body services.UpdatePlayerRequest true "Player fields"
+// @Success 200 {object} models.Player
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /players/{id} [put]
+func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid player ID", nil)
+		return
+	}
+
+	var req services.UpdatePlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	player, err := h.playerService.UpdatePlayer(actor, id, req, auditContext(c))
+	if err != nil {
+		respondPlayerError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, player, "Player updated")
+}
+
+// DeletePlayer deletes a player
+// @Summary Delete player
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Player ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /players/{id} [delete]
+func (h *PlayerHandler) DeletePlayer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid player ID", nil)
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	if err := h.playerService.DeletePlayer(actor, id, auditContext(c)); err != nil {
+		respondPlayerError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Player deleted successfully")
+}
+
+// TransferPlayer moves a player to another team
+// @Summary Transfer player
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Player ID"
+// @Param request body services.TransferPlayerRequest true "Destination team"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /players/{id}/transfer [post]
+func (h *PlayerHandler) TransferPlayer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid player ID", nil)
+		return
+	}
+
+	var req services.TransferPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	player, err := h.playerService.TransferPlayer(actor, id, req, auditContext(c))
+	if err != nil {
+		respondPlayerError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, player, "Player transferred")
+}
+
+// GetPlayerHistory lists the roster stints of a player
+// @Summary Get player roster history
+// @Tags players
+// @Produce json
+// @Param id path string true "Player ID"
+// @Success 200 {array} models.RosterMembership
+// @Failure 404 {object} utils.APIResponse
+// @Router /players/{id}/history [get]
+func (h *PlayerHandler) GetPlayerHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid player ID", nil)
+		return
+	}
+
+	history, err := h.playerService.GetPlayerHistory(id)
+	if err != nil {
+		respondPlayerError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, history, "Player history retrieved")
+}
+
+// GetTeamRoster lists the players who were on a team on a given date
+// @Summary Get team roster as of a date
+// @Tags players
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param date query string false "Date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /teams/{id}/roster [get]
+func (h *PlayerHandler) GetTeamRoster(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+
+	date := time.Now().UTC()
+	if value := c.Query("date"); value != "" {
+		date, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			utils.BadRequest(c, "Invalid date, expected YYYY-MM-DD", nil)
+			return
+		}
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	page, limit, offset := utils.GetPagination(c)
+	roster, total, err := h.playerService.GetTeamRoster(teamID, date, offset, limit)
+	if err != nil {
+		respondPlayerError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"date":   date.Format("2006-01-02"),
+		"roster": roster,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	}, "Roster retrieved")
+}
+
+// respondPlayerError maps player service errors to HTTP responses
+func respondPlayerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPlayerNotFound):
+		utils.NotFound(c, "Player not found")
+	case errors.Is(err, services.ErrTeamNotFound):
+		utils.NotFound(c, "Team not found")
+	case errors.Is(err, services.ErrForbidden):
+		utils.Forbidden(c, "You can only manage players of your own organization")
+	case errors.Is(err, services.ErrJerseyNumberTaken), errors.Is(err, services.ErrUserAlreadyLinked):
+		utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+	case errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrInvalidPlayerUser),
+		errors.Is(err, services.ErrInvalidDateOfBirth),
+		errors.Is(err, services.ErrInvalidDate),
+		errors.Is(err, services.ErrSameTeamTransfer),
+		errors.Is(err, services.ErrInvalidTransferDate),
+		errors.Is(err, services.ErrTransferBeforeMatch):
+		utils.BadRequest(c, err.Error(), nil)
+	default:
+		utils.InternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,467 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/models"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StatisticsHandler struct {
+	statsService       *services.StatisticsService
+	leaderboardService *services.LeaderboardService
+	shotChartService   *services.ShotChartService
+	lineupService      *services.LineupService
+}
+
+func NewStatisticsHandler() *StatisticsHandler {
+	return &StatisticsHandler{
+		statsService:       services.NewStatisticsService(),
+		leaderboardService: services.NewLeaderboardService(),
+		shotChartService:   services.NewShotChartService(),
+		lineupService:      services.NewLineupService(),
+	}
+}
+
+// GetMatchStatistics returns the box score of a match for both teams
+// @Summary Get match box score
+// @Tags statistics
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} services.BoxScore
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/statistics [get]
+func (h *StatisticsHandler) GetMatchStatistics(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	box, err := h.statsService.GetBoxScore(viewer, id)
+	if err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, box, "Match statistics retrieved")
+}
+
+// GetPlayerStatistics returns a player's totals, per-game averages and
+// shooting percentages for a match, tournament, season or career
+// @Summary Get player statistics
+// @Tags statistics
+// @Produce json
+// @Param id path string true "Player ID"
+// @Param scope query string false "match, tournament, season or career (default)"
+// @Param match_id query string false "Match for scope=match"
+// @Param tournament_id query string false "Tournament for scope=tournament"
+// @Param season query int false "Season year for scope=season"
+// @Param location query string false "Split: home or away"
+// @Param opponent_id query string false "Split: games against a team"
+// @Param last query int false "Split: latest N games"
+// @Success 200 {object} services.StatisticsSummary
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /players/{id}/statistics [get]
+func (h *StatisticsHandler) GetPlayerStatistics(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid player ID", nil)
+		return
+	}
+	query, ok := statisticsQuery(c)
+	if !ok {
+		return
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	summary, err := h.statsService.GetPlayerStatistics(viewer, id, query)
+	if err != nil {
+		respondStatisticsError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, summary, "Player statistics retrieved")
+}
+
+// GetTeamStatistics returns a team's totals, per-game averages and shooting
+// percentages for a match, tournament, season or all its games
+// @Summary Get team statistics
+// @Tags statistics
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param scope query string false "match, tournament, season or career (default)"
+// @Param match_id query string false "Match for scope=match"
+// @Param tournament_id query string false "Tournament for scope=tournament"
+// @Param season query int false "Season year for scope=season"
+// @Param location query string false "Split: home or away"
+// @Param opponent_id query string false "Split: games against a team"
+// @Param last query int false "Split: latest N games"
+// @Success 200 {object} services.StatisticsSummary
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /teams/{id}/statistics [get]
+func (h *StatisticsHandler) GetTeamStatistics(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+	query, ok := statisticsQuery(c)
+	if !ok {
+		return
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	summary, err := h.statsService.GetTeamStatistics(viewer, id, query)
+	if err != nil {
+		respondStatisticsError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, summary, "Team statistics retrieved")
+}
+
+// statisticsQuery builds a statistics query from the query string, responding
+// with 400 when it is invalid
+func statisticsQuery(c *gin.Context) (services.StatisticsQuery, bool) {
+	q := services.StatisticsQuery{Scope: c.Query("scope")}
+	switch q.Scope {
+	case "", services.StatScopeMatch, models.StatScopeTournament, models.StatScopeSeason, models.StatScopeCareer:
+	default:
+		utils.BadRequest(c, "Invalid scope, expected match, tournament, season or career", nil)
+		return q, false
+	}
+
+	for key, target := range map[string]**uuid.UUID{
+		"match_id":      &q.MatchID,
+		"tournament_id": &q.TournamentID,
+		"opponent_id":   &q.OpponentID,
+	} {
+		if value := c.Query(key); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				utils.BadRequest(c, "Invalid "+key, nil)
+				return q, false
+			}
+			*target = &id
+		}
+	}
+	for key, target := range map[string]*int{"season": &q.Season, "last": &q.LastN} {
+		if value := c.Query(key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				utils.BadRequest(c, key+" must be a positive number", nil)
+				return q, false
+			}
+			*target = n
+		}
+	}
+	if location := c.Query("location"); location != "" {
+		if location != "home" && location != "away" {
+			utils.BadRequest(c, "Invalid location, expected home or away", nil)
+			return q, false
+		}
+		q.Location = location
+	}
+
+	if err := q.Validate(); err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return q, false
+	}
+	return q, true
+}
+
+func respondStatisticsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPlayerNotFound):
+		utils.NotFound(c, "Player not found")
+	case errors.Is(err, services.ErrTeamNotFound):
+		utils.NotFound(c, "Team not found")
+	default:
+		respondMatchError(c, err)
+	}
+}
+
+// GetLeaders ranks players or teams by a stat over completed matches
+// @Summary Get statistical leaders
+// @Tags statistics
+// @Produce json
+// @Param stat query string true "Stat to rank by, such as points, rebounds or field_goal_pct; also taken from the path"
+// @Param mode query string false "per_game (default) or total; percentages are always over totals"
+// @Param subject query string false "player (default) or team"
+// @Param tournament_id query string false "Only matches of a tournament"
+// @Param organization_id query string false "Only games of an organization's teams"
+// @Param season query int false "Only matches of a season year"
+// @Param min_games query int false "Minimum games played to qualify"
+// @Param min_attempts query int false "Minimum attempts to qualify for percentage stats"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {object} services.Leaderboard
+// @Failure 400 {object} utils.APIResponse
+// @Router /statistics/leaders [get]
+// @Router /statistics/leaders/{stat} [get]
+func (h *StatisticsHandler) GetLeaders(c *gin.Context) {
+	stat := c.Param("stat")
+	if stat == "" {
+		stat = c.Query("stat")
+	}
+	q := services.LeaderboardQuery{
+		Stat:        stat,
+		Mode:        c.DefaultQuery("mode", services.LeaderModePerGame),
+		SubjectType: c.DefaultQuery("subject", models.StatSubjectPlayer),
+	}
+	if !services.IsLeaderStat(q.Stat) {
+		utils.BadRequest(c, "Invalid or missing stat", nil)
+		return
+	}
+	if q.Mode != services.LeaderModePerGame && q.Mode != services.LeaderModeTotal {
+		utils.BadRequest(c, "Invalid mode, expected per_game or total", nil)
+		return
+	}
+	if q.SubjectType != models.StatSubjectPlayer && q.SubjectType != models.StatSubjectTeam {
+		utils.BadRequest(c, "Invalid subject, expected player or team", nil)
+		return
+	}
+
+	for key, target := range map[string]**uuid.UUID{
+		"tournament_id":   &q.TournamentID,
+		"organization_id": &q.OrganizationID,
+	} {
+		if value := c.Query(key); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				utils.BadRequest(c, "Invalid "+key, nil)
+				return
+			}
+			*target = &id
+		}
+	}
+	for key, target := range map[string]*int{"season": &q.Season, "min_games": &q.MinGames, "min_attempts": &q.MinAttempts} {
+		if value := c.Query(key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				utils.BadRequest(c, key+" must be a non-negative number", nil)
+				return
+			}
+			*target = n
+		}
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	board, err := h.leaderboardService.GetLeaderboard(q, page, limit, offset)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch leaders")
+		return
+	}
+
+	utils.SuccessResponse(c, board, "Leaders retrieved")
+}
+
+// GetMatchShotChart returns the shot charts of a match's teams and players by
+// zone and on a heat map grid
+// @Summary Get match shot chart
+// @Tags statistics
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param team_id query string false "Only a team's shots"
+// @Param player_id query string false "Only a player's shots"
+// @Param grid query int false "Heat map cells per side (default 10, max 50)"
+// @Success 200 {object} services.ShotChartReport
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/shot-chart [get]
+func (h *StatisticsHandler) GetMatchShotChart(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+	query, ok := shotChartQuery(c)
+	if !ok {
+		return
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	report, err := h.shotChartService.GetMatchShotChart(viewer, id, query)
+	if err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, report, "Shot chart retrieved")
+}
+
+// GetTournamentShotChart returns the shot charts of the teams and players of
+// a tournament's completed matches
+// @Summary Get tournament shot chart
+// @Tags statistics
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param team_id query string false "Only a team's shots"
+// @Param player_id query string false "Only a player's shots"
+// @Param grid query int false "Heat map cells per side (default 10, max 50)"
+// @Success 200 {object} services.ShotChartReport
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /tournaments/{id}/shot-chart [get]
+func (h *StatisticsHandler) GetTournamentShotChart(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+	query, ok := shotChartQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.shotChartService.GetTournamentShotChart(id, query)
+	if err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, report, "Shot chart retrieved")
+}
+
+// shotChartQuery builds a shot chart query from the query string, responding
+// with 400 when it is invalid
+func shotChartQuery(c *gin.Context) (services.ShotChartQuery, bool) {
+	q := services.ShotChartQuery{GridSize: services.DefaultShotGridSize}
+	for key, target := range map[string]**uuid.UUID{
+		"team_id":   &q.TeamID,
+		"player_id": &q.PlayerID,
+	} {
+		if value := c.Query(key); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				utils.BadRequest(c, "Invalid "+key, nil)
+				return q, false
+			}
+			*target = &id
+		}
+	}
+	if value := c.Query("grid"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 || n > services.MaxShotGridSize {
+			utils.BadRequest(c, "grid must be between 1 and "+strconv.Itoa(services.MaxShotGridSize), nil)
+			return q, false
+		}
+		q.GridSize = n
+	}
+	return q, true
+}
+
+// GetMatchLineups returns the stints of a match's teams and how each lineup,
+// or each pair or trio of players, did on court
+// @Summary Get match lineups
+// @Tags statistics
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param size query int false "Players per combination: 5 (default), 2 or 3"
+// @Param team_id query string false "Only one team"
+// @Param min_minutes query int false "Minimum minutes on court together"
+// @Success 200 {object} services.MatchLineups
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /matches/{id}/lineups [get]
+func (h *StatisticsHandler) GetMatchLineups(c *gin.Context) {
+	id, ok := matchID(c)
+	if !ok {
+		return
+	}
+	query, ok := lineupQuery(c)
+	if !ok {
+		return
+	}
+
+	viewer, _ := middleware.GetUserFromContext(c)
+	result, err := h.lineupService.GetMatchLineups(viewer, id, query)
+	if err != nil {
+		respondMatchError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, "Lineups retrieved")
+}
+
+// GetTeamLineups returns how a team's lineups, or pairs or trios of its
+// players, did on court over its completed matches
+// @Summary Get team lineups
+// @Tags statistics
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param size query int false "Players per combination: 5 (default), 2 or 3"
+// @Param tournament_id query string false "Only matches of a tournament"
+// @Param season query int false "Only matches of a season year"
+// @Param min_minutes query int false "Minimum minutes on court together"
+// @Success 200 {object} services.TeamLineups
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /teams/{id}/lineups [get]
+func (h *StatisticsHandler) GetTeamLineups(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+	query, ok := lineupQuery(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.lineupService.GetTeamLineups(id, query)
+	if err != nil {
+		respondStatisticsError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, "Lineups retrieved")
+}
+
+// lineupQuery builds a lineup query from the query string, responding with
+// 400 when it is invalid
+func lineupQuery(c *gin.Context) (services.LineupQuery, bool) {
+	var q services.LineupQuery
+	for key, target := range map[string]**uuid.UUID{
+		"team_id":       &q.TeamID,
+		"tournament_id": &q.TournamentID,
+	} {
+		if value := c.Query(key); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				utils.BadRequest(c, "Invalid "+key, nil)
+				return q, false
+			}
+			*target = &id
+		}
+	}
+	for key, target := range map[string]*int{"size": &q.Size, "season": &q.Season, "min_minutes": &q.MinMinutes} {
+		if value := c.Query(key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				utils.BadRequest(c, key+" must be a non-negative number", nil)
+				return q, false
+			}
+			*target = n
+		}
+	}
+
+	if err := q.Validate(); err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return q, false
+	}
+	return q, true
+}
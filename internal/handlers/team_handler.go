@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/models"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TeamHandler struct {
+	teamService *services.TeamService
+}
+
+func NewTeamHandler() *TeamHandler {
+	return &TeamHandler{
+		teamService: services.NewTeamService(),
+	}
+}
+
+// ListTeams lists teams
+// @Summary List teams
+// @Tags teams
+// @Produce json
+// @Param organization_id query string false "Filter by organization"
+// @Param status query string false "Filter by status (active, inactive)"
+// @Param name query string false "Filter by partial name"
+// @Success 200 {object} utils.APIResponse
+// @Router /teams [get]
+func (h *TeamHandler) ListTeams(c *gin.Context) {
+	filters := map[string]interface{}{}
+	if orgID := c.Query("organization_id"); orgID != "" {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			utils.BadRequest(c, "Invalid organization ID filter", nil)
+			return
+		}
+		filters["organization_id"] = id
+	}
+	if status := c.Query("status"); status != "" {
+		if status != string(models.TeamStatusActive) && status != string(models.TeamStatusInactive) {
+			utils.BadRequest(c, "Invalid status filter", nil)
+			return
+		}
+		filters["status"] = status
+	}
+	if name := c.Query("name"); name != "" {
+		filters["name"] = name
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	teams, total, err := h.teamService.ListTeams(offset, limit, filters)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch teams")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"teams": teams,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	}, "Teams retrieved")
+}
+
+// GetTeam gets a single team
+// @Summary Get team
+// @Tags teams
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {object} models.Team
+// @Failure 404 {object} utils.APIResponse
+// @Router /teams/{id} [get]
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+
+	team, err := h.teamService.GetTeam(id)
+	if err != nil {
+		utils.NotFound(c, "Team not found")
+		return
+	}
+
+	utils.SuccessResponse(c, team, "Team retrieved")
+}
+
+// CreateTeam creates a team
+// @Summary Create team
+// @Tags teams
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreateTeamRequest true "Team data"
+// @Success 201 {object} models.Team
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /teams [post]
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	var req services.CreateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	team, err := h.teamService.CreateTeam(actor, req, auditContext(c))
+	if err != nil {
+		respondTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    team,
+		Message: "Team created",
+	})
+}
+
+// UpdateTeam updates a team
+// @Summary Update team
+// @Tags teams
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body services.UpdateTeamRequest true "Team fields"
+// @Success 200 {object} models.Team
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /teams/{id} [put]
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+
+	var req services.UpdateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	team, err := h.teamService.UpdateTeam(actor, id, req, auditContext(c))
+	if err != nil {
+		respondTeamError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, team, "Team updated")
+}
+
+// DeleteTeam deletes a team
+// @Summary Delete team
+// @Tags teams
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /teams/{id} [delete]
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	if err := h.teamService.DeleteTeam(actor, id, auditContext(c)); err != nil {
+		respondTeamError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Team deleted successfully")
+}
+
+// respondTeamError maps team service errors to HTTP responses
+func respondTeamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTeamNotFound):
+		utils.NotFound(c, "Team not found")
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		utils.NotFound(c, "Organization not found")
+	case errors.Is(err, services.ErrForbidden):
+		utils.Forbidden(c, "You can only manage teams of your own organization")
+	case errors.Is(err, services.ErrOrganizationRequired):
+		utils.BadRequest(c, err.Error(), nil)
+	default:
+		utils.InternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,471 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"echo-golang/internal/middleware"
+	"echo-golang/internal/models"
+	"echo-golang/internal/services"
+	"echo-golang/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TournamentHandler struct {
+	tournamentService *services.TournamentService
+	scheduleService   *services.ScheduleService
+}
+
+func NewTournamentHandler() *TournamentHandler {
+	return &TournamentHandler{
+		tournamentService: services.NewTournamentService(),
+		scheduleService:   services.NewScheduleService(),
+	}
+}
+
+// ListTournaments lists tournaments
+// @Summary List tournaments
+// @Tags tournaments
+// @Produce json
+// @Param organization_id query string false "Filter by organization"
+// @Param status query string false "Filter by status (upcoming, ongoing, completed)"
+// @Param name query string false "Filter by partial name"
+// @Success 200 {object} utils.APIResponse
+// @Router /tournaments [get]
+func (h *TournamentHandler) ListTournaments(c *gin.Context) {
+	filters := map[string]interface{}{}
+	if orgID := c.Query("organization_id"); orgID != "" {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			utils.BadRequest(c, "Invalid organization ID filter", nil)
+			return
+		}
+		filters["organization_id"] = id
+	}
+	if status := c.Query("status"); status != "" {
+		switch models.TournamentStatus(status) {
+		case models.TournamentStatusUpcoming, models.TournamentStatusOngoing, models.TournamentStatusCompleted:
+			filters["status"] = status
+		default:
+			utils.BadRequest(c, "Invalid status filter", nil)
+			return
+		}
+	}
+	if name := c.Query("name"); name != "" {
+		filters["name"] = name
+	}
+
+	page, limit, offset := utils.GetPagination(c)
+	tournaments, total, err := h.tournamentService.ListTournaments(offset, limit, filters)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch tournaments")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"tournaments": tournaments,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+	}, "Tournaments retrieved")
+}
+
+// GetTournament gets a single tournament
+// @Summary Get tournament
+// @Tags tournaments
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} models.Tournament
+// @Failure 404 {object} utils.APIResponse
+// @Router /tournaments/{id} [get]
+func (h *TournamentHandler) GetTournament(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	tournament, err := h.tournamentService.GetTournament(id)
+	if err != nil {
+		utils.NotFound(c, "Tournament not found")
+		return
+	}
+
+	utils.SuccessResponse(c, tournament, "Tournament retrieved")
+}
+
+// CreateTournament creates a tournament
+// @Summary Create tournament
+// @Tags tournaments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreateTournamentRequest true "Tournament data"
+// @Success 201 {object} models.Tournament
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /tournaments [post]
+func (h *TournamentHandler) CreateTournament(c *gin.Context) {
+	var req services.CreateTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	tournament, err := h.tournamentService.CreateTournament(actor, req, auditContext(c))
+	if err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    tournament,
+		Message: "Tournament created",
+	})
+}
+
+// UpdateTournament updates a tournament, including its status
+// @Summary Update tournament
+// @Tags tournaments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param request body services.UpdateTournamentRequest true "Tournament fields"
+// @Success 200 {object} models.Tournament
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tournaments/{id} [put]
+func (h *TournamentHandler) UpdateTournament(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	tournament, err := h.tournamentService.UpdateTournament(actor, id, req, auditContext(c))
+	if err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, tournament, "Tournament updated")
+}
+
+// DeleteTournament deletes a tournament
+// @Summary Delete tournament
+// @Tags tournaments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /tournaments/{id} [delete]
+func (h *TournamentHandler) DeleteTournament(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	if err := h.tournamentService.DeleteTournament(actor, id, auditContext(c)); err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Tournament deleted successfully")
+}
+
+// ListTeams lists the teams registered in a tournament
+// @Summary List tournament teams
+// @Tags tournaments
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {array} models.TournamentEntry
+// @Failure 404 {object} utils.APIResponse
+// @Router /tournaments/{id}/teams [get]
+func (h *TournamentHandler) ListTeams(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.tournamentService.ListEntries(id)
+	if err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, entries, "Tournament teams retrieved")
+}
+
+// RegisterTeam enters a team into a tournament
+// @Summary Register team in tournament
+// @Tags tournaments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param request body services.RegisterTeamRequest true "Team entry"
+// @Success 201 {object} models.TournamentEntry
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tournaments/{id}/teams [post]
+func (h *TournamentHandler) RegisterTeam(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	var req services.RegisterTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	entry, err := h.tournamentService.RegisterTeam(actor, id, req, auditContext(c))
+	if err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Data:    entry,
+		Message: "Team registered",
+	})
+}
+
+// WithdrawTeam removes a team from a tournament
+// @Summary Withdraw team from tournament
+// @Tags tournaments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param teamId path string true "Team ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tournaments/{id}/teams/{teamId} [delete]
+func (h *TournamentHandler) WithdrawTeam(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	if err := h.tournamentService.WithdrawTeam(actor, id, teamID, auditContext(c)); err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, "Team withdrawn")
+}
+
+// UpdateEntry changes the seed, group or tiebreak rank of a registered team
+// @Summary Update tournament entry
+// @Tags tournaments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param teamId path string true "Team ID"
+// @Param request body services.UpdateEntryRequest true "Entry changes"
+// @Success 200 {object} models.TournamentEntry
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tournaments/{id}/teams/{teamId} [put]
+func (h *TournamentHandler) UpdateEntry(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid team ID", nil)
+		return
+	}
+
+	var req services.UpdateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	entry, err := h.tournamentService.UpdateEntry(actor, id, teamID, req, auditContext(c))
+	if err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, entry, "Entry updated")
+}
+
+// RecomputeStandings rebuilds the tournament standings from its match results
+// @Summary Recompute tournament standings
+// @Tags tournaments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {array} models.Standing
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /tournaments/{id}/standings/recompute [post]
+func (h *TournamentHandler) RecomputeStandings(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	standings, err := h.tournamentService.RecomputeStandings(actor, id, auditContext(c))
+	if err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, standings, "Standings recomputed")
+}
+
+// GetStandings returns the tournament standings
+// @Summary Get tournament standings
+// @Tags tournaments
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {array} models.Standing
+// @Failure 404 {object} utils.APIResponse
+// @Router /tournaments/{id}/standings [get]
+func (h *TournamentHandler) GetStandings(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	standings, err := h.tournamentService.GetStandings(id)
+	if err != nil {
+		respondTournamentError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, standings, "Standings retrieved")
+}
+
+// GenerateSchedule generates a round-robin for the registered teams. The
+// schedule is only previewed unless the request sets commit.
+// @Summary Generate round-robin schedule
+// @Tags tournaments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param request body services.GenerateScheduleRequest true "Format, slots and blackouts"
+// @Success 200 {object} services.SchedulePreview
+// @Success 201 {object} services.SchedulePreview
+// @Failure 400 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tournaments/{id}/schedule/generate [post]
+func (h *TournamentHandler) GenerateSchedule(c *gin.Context) {
+	id, ok := tournamentID(c)
+	if !ok {
+		return
+	}
+
+	var req services.GenerateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	actor, _ := middleware.GetUserFromContext(c)
+	preview, err := h.scheduleService.GenerateRoundRobin(actor, id, req, auditContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrScheduleIncomplete),
+			errors.Is(err, services.ErrScheduleExists):
+			utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+		case errors.Is(err, services.ErrInvalidStartTime),
+			errors.Is(err, services.ErrInvalidTimezone),
+			errors.Is(err, services.ErrBlackoutTeam):
+			utils.BadRequest(c, err.Error(), nil)
+		case errors.Is(err, services.ErrScheduleConflict),
+			errors.Is(err, services.ErrOutsideTournamentDates):
+			respondMatchError(c, err)
+		default:
+			respondTournamentError(c, err)
+		}
+		return
+	}
+
+	if preview.Committed {
+		c.JSON(http.StatusCreated, utils.APIResponse{
+			Success: true,
+			Data:    preview,
+			Message: "Schedule created",
+		})
+		return
+	}
+	utils.SuccessResponse(c, preview, "Schedule preview generated")
+}
+
+// tournamentID parses the :id parameter, responding with 400 when invalid
+func tournamentID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid tournament ID", nil)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondTournamentError maps tournament service errors to HTTP responses
+func respondTournamentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTournamentNotFound):
+		utils.NotFound(c, "Tournament not found")
+	case errors.Is(err, services.ErrTeamNotFound):
+		utils.NotFound(c, "Team not found")
+	case errors.Is(err, services.ErrTeamNotRegistered):
+		utils.NotFound(c, err.Error())
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		utils.NotFound(c, "Organization not found")
+	case errors.Is(err, services.ErrForbidden):
+		utils.Forbidden(c, "Only super admins or the owning organization's admins can modify this tournament")
+	case errors.Is(err, services.ErrTeamAlreadyRegistered),
+		errors.Is(err, services.ErrRegistrationClosed),
+		errors.Is(err, services.ErrTournamentCompleted),
+		errors.Is(err, services.ErrInvalidStatusTransition),
+		errors.Is(err, services.ErrNotEnoughEntries):
+		utils.ErrorResponse(c, http.StatusConflict, "CONFLICT", err.Error(), nil)
+	case errors.Is(err, services.ErrOrganizationRequired),
+		errors.Is(err, services.ErrInvalidDate),
+		errors.Is(err, services.ErrInvalidTournamentDates),
+		errors.Is(err, services.ErrInvalidTiebreakers),
+		errors.Is(err, services.ErrTeamInactive):
+		utils.BadRequest(c, err.Error(), nil)
+	default:
+		utils.InternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,211 @@
+// Package lineups splits a match into the stints each team played with the
+// same players, and totals the combinations of players over stints.
+package lineups
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"echo-golang/internal/models"
+	"echo-golang/internal/oncourt"
+
+	"github.com/google/uuid"
+)
+
+// Stint is a stretch of game time a team played with the same players
+type Stint struct {
+	MatchID       uuid.UUID
+	TeamID        uuid.UUID
+	Players       []uuid.UUID   // Sorted by ID
+	Start         time.Duration // Game time
+	End           time.Duration
+	Line          models.StatLine // The team's points, attempts, offensive rebounds and turnovers
+	PointsAgainst int
+}
+
+// Complete checks if the stint had a full lineup on court
+func (s *Stint) Complete() bool {
+	return len(s.Players) == oncourt.Size
+}
+
+// Stints splits a match into the stints of both teams, home first, each in
+// game order, from its events ordered by sequence. Voided events are
+// skipped. Stints in which nothing happened, such as between the
+// substitutions of a multiple change, are left out. Lineups that can't be
+// reconstructed, because the log shows more or fewer than five players on
+// court, still get stints but are not Complete.
+func Stints(match *models.Match, events []models.MatchEvent) []Stint {
+	teams := []uuid.UUID{match.HomeTeamID, match.AwayTeamID}
+	// Replaying the whole log first finds the starters, who are on court
+	// before any event
+	court, replayed := oncourt.New(), oncourt.Replay(events)
+	for player, started := range replayed.Started {
+		if started {
+			court.Appear(replayed.Teams[player], player, true)
+		}
+	}
+
+	open := map[uuid.UUID]*Stint{}
+	closed := make([][]Stint, len(teams))
+	var now time.Duration
+	start := func(team uuid.UUID) {
+		open[team] = &Stint{MatchID: match.ID, TeamID: team, Players: court.Lineup(team), Start: now}
+	}
+	end := func(i int, team uuid.UUID) {
+		s := open[team]
+		s.End = now
+		if s.End > s.Start || s.Line.Points > 0 || s.PointsAgainst > 0 || s.Line.FieldGoalsAttempted > 0 ||
+			s.Line.FreeThrowsAttempted > 0 || s.Line.Turnovers > 0 {
+			closed[i] = append(closed[i], *s)
+		}
+	}
+	for _, team := range teams {
+		start(team)
+	}
+
+	var lastMiss *uuid.UUID // Team of the latest missed shot not yet rebounded
+	for i := range events {
+		e := &events[i]
+		if e.Voided || e.Type == models.MatchEventVoid {
+			continue
+		}
+		if e.Period > 0 {
+			now = match.GameElapsed(e.Period, time.Duration(e.ClockRemainingMs)*time.Millisecond)
+			if now < 0 {
+				now = 0
+			}
+		}
+		if e.TeamID == nil {
+			continue
+		}
+		side := indexOf(teams, *e.TeamID)
+		if side < 0 {
+			continue
+		}
+		team, stint, opponent := *e.TeamID, open[*e.TeamID], open[teams[1-side]]
+
+		switch e.Type {
+		case models.MatchEventSubstitution:
+			end(side, team)
+			court.Apply(e, now)
+			start(team)
+		case models.MatchEventPoint:
+			stint.Line.Points += e.Points
+			opponent.PointsAgainst += e.Points
+			attempt(&stint.Line, e.Points)
+			lastMiss = nil
+		case models.MatchEventMiss:
+			attempt(&stint.Line, e.Points)
+			lastMiss = &team
+		case models.MatchEventRebound:
+			if lastMiss != nil && *lastMiss == team {
+				stint.Line.OffensiveRebounds++
+			}
+			lastMiss = nil
+		case models.MatchEventTurnover:
+			stint.Line.Turnovers++
+		}
+	}
+
+	var stints []Stint
+	for i, team := range teams {
+		end(i, team)
+		stints = append(stints, closed[i]...)
+	}
+	return stints
+}
+
+// Combination is a group of players of one team and their totals over the
+// stints they were on court together
+type Combination struct {
+	TeamID        uuid.UUID
+	Players       []uuid.UUID // Sorted by ID
+	Matches       int
+	Stints        int
+	Time          time.Duration
+	Line          models.StatLine
+	PointsAgainst int
+}
+
+// Combine totals every combination of size players found in the complete
+// stints, most time on court first. A size of five gives the full lineups.
+func Combine(stints []Stint, size int) []Combination {
+	combos := map[string]*Combination{}
+	matches := map[string]map[uuid.UUID]bool{}
+	for _, s := range stints {
+		if !s.Complete() || size < 1 || size > len(s.Players) {
+			continue
+		}
+		for _, players := range subsets(s.Players, size) {
+			key := s.TeamID.String() + ":" + joinIDs(players)
+			c, ok := combos[key]
+			if !ok {
+				c = &Combination{TeamID: s.TeamID, Players: players}
+				combos[key] = c
+				matches[key] = map[uuid.UUID]bool{}
+			}
+			c.Stints++
+			c.Time += s.End - s.Start
+			c.Line.Add(s.Line)
+			c.PointsAgainst += s.PointsAgainst
+			matches[key][s.MatchID] = true
+		}
+	}
+
+	result := make([]Combination, 0, len(combos))
+	for key, c := range combos {
+		c.Matches = len(matches[key])
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Time != result[j].Time {
+			return result[i].Time > result[j].Time
+		}
+		return joinIDs(result[i].Players) < joinIDs(result[j].Players)
+	})
+	return result
+}
+
+// attempt counts a made or missed shot worth points
+func attempt(l *models.StatLine, points int) {
+	if points == 1 {
+		l.FreeThrowsAttempted++
+	} else {
+		l.FieldGoalsAttempted++
+	}
+}
+
+// subsets lists the combinations of size players, each sorted like players
+func subsets(players []uuid.UUID, size int) [][]uuid.UUID {
+	var result [][]uuid.UUID
+	var pick func(from int, chosen []uuid.UUID)
+	pick = func(from int, chosen []uuid.UUID) {
+		if len(chosen) == size {
+			result = append(result, append([]uuid.UUID(nil), chosen...))
+			return
+		}
+		for i := from; i <= len(players)-(size-len(chosen)); i++ {
+			pick(i+1, append(chosen, players[i]))
+		}
+	}
+	pick(0, nil)
+	return result
+}
+
+func joinIDs(ids []uuid.UUID) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = id.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func indexOf(ids []uuid.UUID, id uuid.UUID) int {
+	for i := range ids {
+		if ids[i] == id {
+			return i
+		}
+	}
+	return -1
+}
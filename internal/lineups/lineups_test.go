@@ -0,0 +1,70 @@
+package lineups
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestStints(t *testing.T) {
+	home, away := uuid.New(), uuid.New()
+	match := &models.Match{ID: uuid.New(), HomeTeamID: home, AwayTeamID: away, PeriodCount: 4, PeriodLength: 600}
+	p := make([]uuid.UUID, 6)
+	for i := range p {
+		p[i] = uuid.New()
+	}
+	at := func(e models.MatchEvent, remaining time.Duration) models.MatchEvent {
+		e.Period, e.ClockRemainingMs = 1, remaining.Milliseconds()
+		return e
+	}
+	point := func(player uuid.UUID, remaining time.Duration) models.MatchEvent {
+		return at(models.MatchEvent{Type: models.MatchEventPoint, TeamID: &home, PlayerID: &player, Points: 2}, remaining)
+	}
+
+	// p0-p3 are seen scoring, p4 only leaving: all five started. p5 replaces
+	// p4 at 6:00 and scores after.
+	events := []models.MatchEvent{
+		point(p[0], 9*time.Minute),
+		point(p[1], 8*time.Minute),
+		at(models.MatchEvent{Type: models.MatchEventSubstitution, TeamID: &home, PlayerID: &p[5], SubstitutedPlayerID: &p[4]}, 6*time.Minute),
+		point(p[2], 5*time.Minute),
+		point(p[3], 4*time.Minute),
+		point(p[5], 3*time.Minute),
+	}
+	voided := point(p[0], 2*time.Minute)
+	voided.Voided = true
+	events = append(events, voided)
+
+	stints := Stints(match, events)
+	if len(stints) != 3 || stints[2].TeamID != away || stints[2].Complete() {
+		t.Fatalf("got %d stints, want 2 of the home team then an incomplete one of the away team", len(stints))
+	}
+	cases := []struct {
+		starting   []uuid.UUID
+		start, end time.Duration
+		points     int
+	}{
+		{p[:5], 0, 4 * time.Minute, 4},
+		{append(append([]uuid.UUID(nil), p[:4]...), p[5]), 4 * time.Minute, 7 * time.Minute, 6},
+	}
+	for i, c := range cases {
+		s := stints[i]
+		if !s.Complete() || joinIDs(s.Players) != joinIDs(sorted(c.starting)) {
+			t.Errorf("stint %d: got players %v, want %v", i, s.Players, c.starting)
+		}
+		if s.Start != c.start || s.End != c.end || s.Line.Points != c.points {
+			t.Errorf("stint %d: got %v-%v with %d points, want %v-%v with %d",
+				i, s.Start, s.End, s.Line.Points, c.start, c.end, c.points)
+		}
+	}
+}
+
+func sorted(ids []uuid.UUID) []uuid.UUID {
+	players := append([]uuid.UUID(nil), ids...)
+	sort.Slice(players, func(i, j int) bool { return players[i].String() < players[j].String() })
+	return players
+}
@@ -0,0 +1,57 @@
+// Package live fans out realtime match updates to the clients following them.
+// It moves opaque payloads between topics and subscribers.
+package live
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// DefaultBufferSize is the number of updates a subscriber may fall behind by
+const DefaultBufferSize = 64
+
+// Broker carries messages from their publishers to the subscribers of their
+// topic. A broker shared between API instances, like RedisBroker, lets
+// clients follow updates written on any of them.
+type Broker interface {
+	// Publish sends a message to the subscribers of its topic
+	Publish(msg Message) error
+	// Subscribe starts receiving the messages published on a topic
+	Subscribe(topic string) *Subscription
+	// Close stops the broker and closes its subscriptions
+	Close() error
+}
+
+// DefaultBroker is the broker of the running server, set up by Init
+var DefaultBroker Broker
+
+// Init sets up the server's broker: Redis when an address is given, so every
+// instance sees every update, otherwise an in-memory hub
+func Init(ctx context.Context, redisAddr, redisPassword string, bufferSize int) error {
+	if redisAddr == "" {
+		DefaultBroker = NewHub(bufferSize)
+		return nil
+	}
+	broker, err := NewRedisBroker(ctx, redisAddr, redisPassword, bufferSize)
+	if err != nil {
+		return err
+	}
+	DefaultBroker = broker
+	log.Printf("Live updates shared through Redis at %s", redisAddr)
+	return nil
+}
+
+// MatchTopic is the topic of a match's updates
+func MatchTopic(matchID uuid.UUID) string {
+	return "match:" + matchID.String()
+}
+
+// Message is one update published on a topic. Sequence is the match event
+// sequence number it carries, or 0 for updates outside the event log.
+type Message struct {
+	Topic    string
+	Sequence int
+	Payload  []byte // JSON
+}
@@ -0,0 +1,114 @@
+package live
+
+import (
+	"sync"
+)
+
+// Subscription receives the messages of one topic on C until it is closed.
+// C is also closed when the subscriber falls a full buffer behind; Dropped
+// then reports true and the client should resume from its last sequence.
+type Subscription struct {
+	C <-chan Message
+
+	ch      chan Message
+	topic   string
+	hub     *Hub
+	dropped bool
+	closed  bool
+}
+
+// Dropped reports whether the subscription was closed for being too slow
+func (s *Subscription) Dropped() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.dropped
+}
+
+// Close stops the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Hub delivers published messages to every subscriber of their topic in this
+// process. It is the in-memory Broker, and the local fan-out of the others.
+// Publishing never blocks: each subscriber has a bounded buffer, and one
+// that lets it fill up is disconnected instead of slowing the others down.
+type Hub struct {
+	mu         sync.Mutex
+	topics     map[string]map[*Subscription]struct{}
+	bufferSize int
+}
+
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Hub{
+		topics:     map[string]map[*Subscription]struct{}{},
+		bufferSize: bufferSize,
+	}
+}
+
+// Subscribe starts receiving the messages published on a topic
+func (h *Hub) Subscribe(topic string) *Subscription {
+	ch := make(chan Message, h.bufferSize)
+	sub := &Subscription{C: ch, ch: ch, topic: topic, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.topics[topic] == nil {
+		h.topics[topic] = map[*Subscription]struct{}{}
+	}
+	h.topics[topic][sub] = struct{}{}
+	return sub
+}
+
+// Publish delivers a message to the current subscribers of its topic
+func (h *Hub) Publish(msg Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.topics[msg.Topic] {
+		select {
+		case sub.ch <- msg:
+		default:
+			sub.dropped = true
+			h.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Close closes every subscription
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subs := range h.topics {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribers counts the subscribers of a topic
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.topics[topic])
+}
+
+// remove unregisters a subscription and closes its channel. The caller holds
+// the lock.
+func (h *Hub) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.ch)
+	delete(h.topics[sub.topic], sub)
+	if len(h.topics[sub.topic]) == 0 {
+		delete(h.topics, sub.topic)
+	}
+}
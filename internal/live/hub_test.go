@@ -0,0 +1,107 @@
+package live
+
+import (
+	"testing"
+	"time"
+)
+
+// receive waits briefly for the next message of a subscription
+func receive(t *testing.T, sub *Subscription) (Message, bool) {
+	t.Helper()
+	select {
+	case msg, ok := <-sub.C:
+		return msg, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a message")
+		return Message{}, false
+	}
+}
+
+func TestHubFanOut(t *testing.T) {
+	hub := NewHub(4)
+	defer hub.Close()
+
+	first, second := hub.Subscribe("match:a"), hub.Subscribe("match:a")
+	other := hub.Subscribe("match:b")
+	if n := hub.Subscribers("match:a"); n != 2 {
+		t.Fatalf("got %d subscribers, want 2", n)
+	}
+
+	hub.Publish(Message{Topic: "match:a", Sequence: 1, Payload: []byte(`{}`)})
+	hub.Publish(Message{Topic: "match:b", Sequence: 7})
+	hub.Publish(Message{Topic: "match:c", Sequence: 9}) // Nobody follows it
+
+	for _, sub := range []*Subscription{first, second} {
+		if msg, ok := receive(t, sub); !ok || msg.Topic != "match:a" || msg.Sequence != 1 {
+			t.Errorf("got %+v, want sequence 1 on match:a", msg)
+		}
+	}
+	if msg, ok := receive(t, other); !ok || msg.Topic != "match:b" || msg.Sequence != 7 {
+		t.Errorf("got %+v, want sequence 7 on match:b", msg)
+	}
+	for _, sub := range []*Subscription{first, second, other} {
+		if len(sub.C) != 0 {
+			t.Errorf("%s: %d unexpected messages", sub.topic, len(sub.C))
+		}
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(2)
+	defer hub.Close()
+
+	slow, fast := hub.Subscribe("match:a"), hub.Subscribe("match:a")
+	for seq := 1; seq <= 3; seq++ {
+		hub.Publish(Message{Topic: "match:a", Sequence: seq})
+		if msg, _ := receive(t, fast); msg.Sequence != seq {
+			t.Fatalf("fast subscriber got sequence %d, want %d", msg.Sequence, seq)
+		}
+	}
+
+	if !slow.Dropped() {
+		t.Error("slow subscriber not dropped after its buffer filled up")
+	}
+	if fast.Dropped() {
+		t.Error("fast subscriber dropped")
+	}
+	// The buffered messages are still delivered before the channel closes
+	for seq := 1; seq <= 2; seq++ {
+		if msg, ok := receive(t, slow); !ok || msg.Sequence != seq {
+			t.Errorf("got %+v, want buffered sequence %d", msg, seq)
+		}
+	}
+	if _, ok := receive(t, slow); ok {
+		t.Error("dropped subscription still open")
+	}
+	if n := hub.Subscribers("match:a"); n != 1 {
+		t.Errorf("got %d subscribers, want 1", n)
+	}
+}
+
+func TestSubscriptionCloseIdempotent(t *testing.T) {
+	hub := NewHub(0)
+	sub := hub.Subscribe("match:a")
+	sub.Close()
+	sub.Close()
+	if _, ok := <-sub.C; ok {
+		t.Error("closed subscription still open")
+	}
+	if sub.Dropped() {
+		t.Error("closed subscription reported as dropped")
+	}
+	if n := hub.Subscribers("match:a"); n != 0 {
+		t.Errorf("got %d subscribers, want 0", n)
+	}
+
+	// Publishing after the close neither panics nor reaches it
+	hub.Publish(Message{Topic: "match:a", Sequence: 1})
+
+	// Closing the hub closes the rest, and closing again is harmless
+	open := hub.Subscribe("match:b")
+	hub.Close()
+	hub.Close()
+	open.Close()
+	if _, ok := <-open.C; ok {
+		t.Error("subscription open after the hub closed")
+	}
+}
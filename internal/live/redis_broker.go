@@ -0,0 +1,108 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisChannelPrefix namespaces the broker's topics among other Redis users
+const redisChannelPrefix = "live:"
+
+const redisPublishTimeout = 2 * time.Second
+
+// redisEnvelope is a message as it travels through Redis
+type redisEnvelope struct {
+	Sequence int             `json:"sequence"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// RedisBroker shares messages between API instances through Redis pub/sub.
+// Each instance subscribes once to every topic and fans the messages out to
+// its own subscribers through a Hub, so publishing reaches the clients
+// connected to any instance. Messages published while an instance is cut off
+// from Redis are lost to it; clients catch up by event sequence.
+type RedisBroker struct {
+	client *redis.Client
+	pubsub *redis.PubSub
+	hub    *Hub
+	done   chan struct{}
+}
+
+// NewRedisBroker connects to Redis and starts relaying its messages
+func NewRedisBroker(ctx context.Context, addr, password string, bufferSize int) (*RedisBroker, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	pubsub := client.PSubscribe(ctx, redisChannelPrefix+"*")
+	// Wait for the subscription to be confirmed so nothing published from
+	// here on is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		client.Close()
+		return nil, err
+	}
+
+	b := &RedisBroker{
+		client: client,
+		pubsub: pubsub,
+		hub:    NewHub(bufferSize),
+		done:   make(chan struct{}),
+	}
+	go b.relay()
+	return b, nil
+}
+
+// Publish sends a message to the subscribers of its topic on every instance
+func (b *RedisBroker) Publish(msg Message) error {
+	data, err := json.Marshal(redisEnvelope{Sequence: msg.Sequence, Payload: msg.Payload})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisPublishTimeout)
+	defer cancel()
+	return b.client.Publish(ctx, redisChannelPrefix+msg.Topic, data).Err()
+}
+
+// Subscribe starts receiving the messages published on a topic by any instance
+func (b *RedisBroker) Subscribe(topic string) *Subscription {
+	return b.hub.Subscribe(topic)
+}
+
+// Close unsubscribes from Redis and closes the local subscriptions
+func (b *RedisBroker) Close() error {
+	err := b.pubsub.Close()
+	<-b.done
+	b.hub.Close()
+	if cerr := b.client.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// relay hands the messages received from Redis to the local subscribers
+func (b *RedisBroker) relay() {
+	defer close(b.done)
+	for m := range b.pubsub.Channel() {
+		var envelope redisEnvelope
+		if err := json.Unmarshal([]byte(m.Payload), &envelope); err != nil {
+			log.Printf("Warning: Dropped malformed live message on %s: %v", m.Channel, err)
+			continue
+		}
+		b.hub.Publish(Message{
+			Topic:    strings.TrimPrefix(m.Channel, redisChannelPrefix),
+			Sequence: envelope.Sequence,
+			Payload:  envelope.Payload,
+		})
+	}
+}
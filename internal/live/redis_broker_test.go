@@ -0,0 +1,61 @@
+package live
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRedisBrokerRelaysBetweenInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	first, err := NewRedisBroker(ctx, server.Addr(), "", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	second, err := NewRedisBroker(ctx, server.Addr(), "", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	onFirst, onSecond := first.Subscribe("match:a"), second.Subscribe("match:a")
+
+	if err := first.Publish(Message{Topic: "match:a", Sequence: 3, Payload: []byte(`{"from":"first"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, sub := range []*Subscription{onFirst, onSecond} {
+		msg, ok := receive(t, sub)
+		if !ok || msg.Topic != "match:a" || msg.Sequence != 3 || string(msg.Payload) != `{"from":"first"}` {
+			t.Errorf("got %+v, want the first instance's message", msg)
+		}
+	}
+
+	if err := second.Publish(Message{Topic: "match:a", Sequence: 4, Payload: []byte(`{"from":"second"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, sub := range []*Subscription{onFirst, onSecond} {
+		msg, ok := receive(t, sub)
+		if !ok || msg.Sequence != 4 || string(msg.Payload) != `{"from":"second"}` {
+			t.Errorf("got %+v, want the second instance's message", msg)
+		}
+	}
+}
+
+func TestRedisBrokerCloseClosesSubscriptions(t *testing.T) {
+	server := miniredis.RunT(t)
+	broker, err := NewRedisBroker(context.Background(), server.Addr(), "", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := broker.Subscribe("match:a")
+	if err := broker.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := receive(t, sub); ok {
+		t.Error("subscription open after the broker closed")
+	}
+}
@@ -28,38 +28,97 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Validate token
-		claims, err := utils.ValidateToken(token)
-		if err != nil {
-			utils.Unauthorized(c, "Invalid or expired token")
+		if !authenticate(c, token) {
 			c.Abort()
 			return
 		}
 
-		// Get user from database to ensure they still exist and are active
-		var user models.User
-		if err := database.DB.Where("id = ?", claims.UserID).First(&user).Error; err != nil {
-			utils.Unauthorized(c, "User not found")
+		c.Next()
+	}
+}
+
+// OptionalAuth sets the user in context when the request carries a token and
+// lets anonymous requests through
+func OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := utils.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+		if token == "" {
+			c.Next()
+			return
+		}
+		if !authenticate(c, token) {
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Check if user is active
-		if !user.IsActive() {
-			utils.Forbidden(c, "Account is inactive")
+// StreamAuth is OptionalAuth for the live feed of the match in the path.
+// Browsers can't set headers on WebSocket and EventSource requests, so they
+// pass a stream ticket for the match as ?ticket= instead of the token.
+func StreamAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			OptionalAuth()(c)
+			return
+		}
+		matchID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			utils.Unauthorized(c, "Invalid or expired ticket")
 			c.Abort()
 			return
 		}
+		claims, err := utils.ValidateStreamTicket(ticket, matchID)
+		if err != nil {
+			utils.Unauthorized(c, "Invalid or expired ticket")
+			c.Abort()
+			return
+		}
+		if !signIn(c, claims) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate validates a token and sets its user in context, responding
+// with the error when it can't
+func authenticate(c *gin.Context, token string) bool {
+	// Validate token
+	claims, err := utils.ValidateToken(token)
+	if err != nil {
+		utils.Unauthorized(c, "Invalid or expired token")
+		return false
+	}
+	return signIn(c, claims)
+}
 
-		// Set user data in context
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
-		c.Set("user_role", claims.Role)
-		c.Set("organization_id", claims.OrganizationID)
-		c.Set("user", &user)
+// signIn sets the user of validated claims in context, responding with the
+// error when the user can't sign in
+func signIn(c *gin.Context, claims *utils.JWTClaims) bool {
+	// Get user from database to ensure they still exist and are active
+	var user models.User
+	if err := database.DB.Where("id = ?", claims.UserID).First(&user).Error; err != nil {
+		utils.Unauthorized(c, "User not found")
+		return false
+	}
 
-		c.Next()
+	// Check if user is active
+	if !user.IsActive() {
+		utils.Forbidden(c, "Account is inactive")
+		return false
 	}
+
+	// Set user data in context
+	c.Set("user_id", claims.UserID)
+	c.Set("user_email", claims.Email)
+	c.Set("user_role", claims.Role)
+	c.Set("organization_id", claims.OrganizationID)
+	c.Set("user", &user)
+	return true
 }
 
 // GetUserFromContext gets the user ID from context
@@ -72,6 +131,16 @@ func GetUserIDFromContext(c *gin.Context) (uuid.UUID, bool) {
 	return id, ok
 }
 
+// GetUserFromContext gets the authenticated user from context
+func GetUserFromContext(c *gin.Context) (*models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		return nil, false
+	}
+	u, ok := user.(*models.User)
+	return u, ok
+}
+
 // GetUserRoleFromContext gets the user role from context
 func GetUserRoleFromContext(c *gin.Context) (models.UserRole, bool) {
 	role, exists := c.Get("user_role")
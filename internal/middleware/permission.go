@@ -5,6 +5,7 @@ import (
 	"echo-golang/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // RequireRole checks if user has required role
@@ -79,3 +80,30 @@ func CheckOrganizationAccess(orgIDGetter func(*gin.Context) uuid.UUID) gin.Handl
 	}
 }
 
+// RequireOrganizationManager checks that the user can manage the organization
+// identified by the :id route parameter (super admin, or that organization's admin)
+func RequireOrganizationManager() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orgID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			utils.BadRequest(c, "Invalid organization ID", nil)
+			c.Abort()
+			return
+		}
+
+		user, exists := GetUserFromContext(c)
+		if !exists {
+			utils.Unauthorized(c, "User not found in context")
+			c.Abort()
+			return
+		}
+
+		if !user.CanManageOrganization(orgID) {
+			utils.Forbidden(c, "Access denied to this organization")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"echo-golang/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequireOrganizationManager(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	org, other := uuid.New(), uuid.New()
+
+	cases := []struct {
+		name string
+		user *models.User // Nil when not authenticated
+		id   string
+		want int
+	}{
+		{"super admin", &models.User{Role: models.RoleSuperAdmin}, org.String(), http.StatusOK},
+		{"org admin of the organization", &models.User{Role: models.RoleOrgAdmin, OrganizationID: &org}, org.String(), http.StatusOK},
+		{"org admin of another organization", &models.User{Role: models.RoleOrgAdmin, OrganizationID: &other}, org.String(), http.StatusForbidden},
+		{"org admin of no organization", &models.User{Role: models.RoleOrgAdmin}, org.String(), http.StatusForbidden},
+		{"team member of the organization", &models.User{Role: models.RoleTeamMember, OrganizationID: &org}, org.String(), http.StatusForbidden},
+		{"malformed ID", &models.User{Role: models.RoleSuperAdmin}, "not-a-uuid", http.StatusBadRequest},
+		{"not authenticated", nil, org.String(), http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		r := gin.New()
+		r.PUT("/organizations/:id", func(ctx *gin.Context) {
+			if c.user != nil {
+				ctx.Set("user", c.user)
+			}
+		}, RequireOrganizationManager(), func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/organizations/"+c.id, nil))
+		if w.Code != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, w.Code, c.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditAction string
+
+const (
+	AuditActionUserCreate                AuditAction = "user.create"
+	AuditActionUserUpdate                AuditAction = "user.update"
+	AuditActionUserDelete                AuditAction = "user.delete"
+	AuditActionOrganizationCreate        AuditAction = "organization.create"
+	AuditActionOrganizationUpdate        AuditAction = "organization.update"
+	AuditActionOrganizationDelete        AuditAction = "organization.delete"
+	AuditActionTeamCreate                AuditAction = "team.create"
+	AuditActionTeamUpdate                AuditAction = "team.update"
+	AuditActionTeamDelete                AuditAction = "team.delete"
+	AuditActionPlayerCreate              AuditAction = "player.create"
+	AuditActionPlayerUpdate              AuditAction = "player.update"
+	AuditActionPlayerDelete              AuditAction = "player.delete"
+	AuditActionPlayerTransfer            AuditAction = "player.transfer"
+	AuditActionTournamentCreate          AuditAction = "tournament.create"
+	AuditActionTournamentUpdate          AuditAction = "tournament.update"
+	AuditActionTournamentDelete          AuditAction = "tournament.delete"
+	AuditActionTournamentRegisterTeam    AuditAction = "tournament.register_team"
+	AuditActionTournamentWithdrawTeam    AuditAction = "tournament.withdraw_team"
+	AuditActionTournamentGenerateBracket AuditAction = "tournament.generate_bracket"
+	AuditActionTournamentDeleteBracket   AuditAction = "tournament.delete_bracket"
+	AuditActionTournamentUpdateEntry     AuditAction = "tournament.update_entry"
+	AuditActionTournamentRecompute       AuditAction = "tournament.recompute_standings"
+	AuditActionMatchCreate               AuditAction = "match.create"
+	AuditActionMatchUpdate               AuditAction = "match.update"
+	AuditActionMatchReschedule           AuditAction = "match.reschedule"
+	AuditActionMatchCancel               AuditAction = "match.cancel"
+	AuditActionMatchDelete               AuditAction = "match.delete"
+	AuditActionMatchAssignScorekeeper    AuditAction = "match.assign_scorekeeper"
+	AuditActionMatchRemoveScorekeeper    AuditAction = "match.remove_scorekeeper"
+	AuditActionLoginSuccess              AuditAction = "auth.login_success"
+	AuditActionLoginFailure              AuditAction = "auth.login_failure"
+	AuditActionConsoleLoginSuccess       AuditAction = "auth.console_login_success"
+	AuditActionConsoleLoginFailure       AuditAction = "auth.console_login_failure"
+	AuditActionTokenRefresh              AuditAction = "auth.token_refresh"
+	AuditActionRegister                  AuditAction = "auth.register"
+)
+
+const (
+	AuditTargetUser         = "user"
+	AuditTargetOrganization = "organization"
+	AuditTargetTeam         = "team"
+	AuditTargetPlayer       = "player"
+	AuditTargetTournament   = "tournament"
+	AuditTargetMatch        = "match"
+)
+
+// ErrAuditEventImmutable is returned when an audit event is updated or deleted
+var ErrAuditEventImmutable = errors.New("audit events are immutable")
+
+// AuditEvent records an administrative or security relevant action
+type AuditEvent struct {
+	ID         uuid.UUID       `gorm:"type:char(36);primary_key" json:"id"`
+	ActorID    *uuid.UUID      `gorm:"type:char(36);index" json:"actor_id,omitempty"`
+	ActorEmail string          `gorm:"type:varchar(255)" json:"actor_email,omitempty"`
+	Action     AuditAction     `gorm:"type:varchar(50);not null;index" json:"action"`
+	TargetType string          `gorm:"type:varchar(50);index:idx_audit_target" json:"target_type,omitempty"`
+	TargetID   *uuid.UUID      `gorm:"type:char(36);index:idx_audit_target" json:"target_id,omitempty"`
+	Changes    json.RawMessage `gorm:"type:json" json:"changes,omitempty"` // Field -> {before, after}
+	IPAddress  string          `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	RequestID  string          `gorm:"type:varchar(64);index" json:"request_id,omitempty"`
+	CreatedAt  time.Time       `gorm:"index" json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeUpdate prevents audit events from being modified
+func (e *AuditEvent) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditEventImmutable
+}
+
+// BeforeDelete prevents audit events from being removed
+func (e *AuditEvent) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditEventImmutable
+}
+
+// TableName specifies the table name
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
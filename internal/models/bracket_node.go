@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BracketNode is one game of a tournament's knockout bracket. Each side is
+// either a team, a bye, or still waiting on the node that feeds it; the winner
+// and, in double elimination, the loser move on to the linked nodes.
+type BracketNode struct {
+	ID           uuid.UUID  `gorm:"type:char(36);primary_key" json:"id"`
+	TournamentID uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex:idx_bracket_node_key" json:"tournament_id"`
+	Key          string     `gorm:"type:varchar(10);not null;uniqueIndex:idx_bracket_node_key" json:"key"` // e.g. W1-3, L2-1, GF
+	Bracket      string     `gorm:"type:varchar(10);not null" json:"bracket"`                              // winners, losers or final
+	Round        int        `gorm:"not null" json:"round"`
+	Position     int        `gorm:"not null" json:"position"`
+	HomeSeed     *int       `json:"home_seed,omitempty"`
+	AwaySeed     *int       `json:"away_seed,omitempty"`
+	HomeTeamID   *uuid.UUID `gorm:"type:char(36)" json:"home_team_id,omitempty"`
+	AwayTeamID   *uuid.UUID `gorm:"type:char(36)" json:"away_team_id,omitempty"`
+	HomeBye      bool       `gorm:"not null;default:false" json:"home_bye"`
+	AwayBye      bool       `gorm:"not null;default:false" json:"away_bye"`
+	MatchID      *uuid.UUID `gorm:"type:char(36);uniqueIndex" json:"match_id,omitempty"`
+	Decided      bool       `gorm:"not null;default:false" json:"decided"`
+	WinnerTeamID *uuid.UUID `gorm:"type:char(36)" json:"winner_team_id,omitempty"` // nil on a decided node when both sides were byes
+	WinnerNodeID *uuid.UUID `gorm:"type:char(36)" json:"winner_node_id,omitempty"`
+	WinnerSide   string     `gorm:"type:varchar(4)" json:"winner_side,omitempty"`
+	LoserNodeID  *uuid.UUID `gorm:"type:char(36)" json:"loser_node_id,omitempty"`
+	LoserSide    string     `gorm:"type:varchar(4)" json:"loser_side,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relationships
+	HomeTeam *Team  `gorm:"foreignKey:HomeTeamID" json:"home_team,omitempty"`
+	AwayTeam *Team  `gorm:"foreignKey:AwayTeamID" json:"away_team,omitempty"`
+	Match    *Match `gorm:"foreignKey:MatchID" json:"match,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (n *BracketNode) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (BracketNode) TableName() string {
+	return "bracket_nodes"
+}
+
+// IsReady checks if both sides of the node are known
+func (n *BracketNode) IsReady() bool {
+	return (n.HomeTeamID != nil || n.HomeBye) && (n.AwayTeamID != nil || n.AwayBye)
+}
+
+// HasBye checks if either side of the node is a bye
+func (n *BracketNode) HasBye() bool {
+	return n.HomeBye || n.AwayBye
+}
+
+// Place fills one side of the node; a nil team is a bye
+func (n *BracketNode) Place(side string, teamID *uuid.UUID) {
+	if side == "home" {
+		n.HomeTeamID, n.HomeBye = teamID, teamID == nil
+	} else {
+		n.AwayTeamID, n.AwayBye = teamID, teamID == nil
+	}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// GameState is where a live match is in its periods
+type GameState string
+
+const (
+	GameStatePreGame  GameState = "pre_game"
+	GameStatePeriod   GameState = "in_period"
+	GameStateBreak    GameState = "break" // Between quarters, or before overtime
+	GameStateHalftime GameState = "halftime"
+	GameStateFinal    GameState = "final"
+)
+
+// ClockRemaining is the time left in the current period at now
+func (m *Match) ClockRemaining(now time.Time) time.Duration {
+	remaining := time.Duration(m.ClockRemainingMs) * time.Millisecond
+	if m.ClockStartedAt != nil {
+		remaining -= now.Sub(*m.ClockStartedAt)
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// ClockRunning checks if the game clock is running
+func (m *Match) ClockRunning() bool {
+	return m.ClockStartedAt != nil
+}
+
+// StartClock runs the clock from the time it was stopped at
+func (m *Match) StartClock(now time.Time) {
+	m.ClockStartedAt = &now
+}
+
+// StopClock freezes the clock at the time left at now
+func (m *Match) StopClock(now time.Time) {
+	m.ClockRemainingMs = m.ClockRemaining(now).Milliseconds()
+	m.ClockStartedAt = nil
+}
+
+// IsOvertime checks if the current period is an overtime period
+func (m *Match) IsOvertime() bool {
+	return m.PeriodCount > 0 && m.Period > m.PeriodCount
+}
+
+// CurrentPeriodLength is the full length of the current period
+func (m *Match) CurrentPeriodLength() time.Duration {
+	return m.periodLength(m.Period)
+}
+
+// GameElapsed is the game time played when a period's clock reads remaining
+func (m *Match) GameElapsed(period int, remaining time.Duration) time.Duration {
+	var elapsed time.Duration
+	for p := 1; p < period; p++ {
+		elapsed += m.periodLength(p)
+	}
+	return elapsed + m.periodLength(period) - remaining
+}
+
+func (m *Match) periodLength(period int) time.Duration {
+	if m.PeriodCount > 0 && period > m.PeriodCount {
+		return time.Duration(m.OvertimeLength) * time.Second
+	}
+	return time.Duration(m.PeriodLength) * time.Second
+}
+
+// PeriodLabel names the current period: Q1-Q4 for quarters, H1-H2 for halves
+// and OT1, OT2... for overtime
+func (m *Match) PeriodLabel() string {
+	return m.PeriodName(m.Period)
+}
+
+// PeriodName names a period of the match like PeriodLabel
+func (m *Match) PeriodName(period int) string {
+	switch {
+	case period == 0:
+		return ""
+	case m.PeriodCount > 0 && period > m.PeriodCount:
+		return fmt.Sprintf("OT%d", period-m.PeriodCount)
+	case m.PeriodCount == 4:
+		return fmt.Sprintf("Q%d", period)
+	case m.PeriodCount == 2:
+		return fmt.Sprintf("H%d", period)
+	}
+	return fmt.Sprintf("P%d", period)
+}
+
+// FormatClock formats a game clock as M:SS, with tenths of a second during
+// the last minute like a scoreboard
+func FormatClock(d time.Duration) string {
+	if d < time.Minute {
+		tenths := int(d / (100 * time.Millisecond))
+		return fmt.Sprintf("%d.%d", tenths/10, tenths%10)
+	}
+	seconds := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
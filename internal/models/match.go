@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MatchStatus string
+
+const (
+	MatchStatusScheduled MatchStatus = "scheduled"
+	MatchStatusLive      MatchStatus = "live"
+	MatchStatusCompleted MatchStatus = "completed"
+	MatchStatusCancelled MatchStatus = "cancelled"
+)
+
+// matchTransitions lists the statuses each status may move to
+var matchTransitions = map[MatchStatus][]MatchStatus{
+	MatchStatusScheduled: {MatchStatusLive, MatchStatusCancelled},
+	MatchStatusLive:      {MatchStatusCompleted, MatchStatusCancelled},
+}
+
+// CanTransitionTo checks if a match may move from s to next
+func (s MatchStatus) CanTransitionTo(next MatchStatus) bool {
+	for _, allowed := range matchTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type Match struct {
+	ID                 uuid.UUID      `gorm:"type:char(36);primary_key" json:"id"`
+	TournamentID       *uuid.UUID     `gorm:"type:char(36);index" json:"tournament_id,omitempty"`
+	HomeTeamID         uuid.UUID      `gorm:"type:char(36);not null;index" json:"home_team_id"`
+	AwayTeamID         uuid.UUID      `gorm:"type:char(36);not null;index" json:"away_team_id"`
+	ScheduledAt        time.Time      `gorm:"not null;index" json:"scheduled_at"`
+	Venue              string         `gorm:"type:varchar(255);not null;index" json:"venue"`
+	Status             MatchStatus    `gorm:"type:varchar(20);not null;default:'scheduled';index" json:"status"`
+	HomeScore          int            `gorm:"not null;default:0" json:"home_score"`
+	AwayScore          int            `gorm:"not null;default:0" json:"away_score"`
+	EventSequence      int            `gorm:"not null;default:0" json:"event_sequence"` // Sequence of the latest live event
+	GameState          GameState      `gorm:"type:varchar(20);not null;default:'pre_game'" json:"game_state"`
+	Period             int            `gorm:"not null;default:0" json:"period"`
+	PeriodCount        int            `gorm:"not null;default:0" json:"period_count,omitempty"`    // Game rules, fixed when the first period starts
+	PeriodLength       int            `gorm:"not null;default:0" json:"period_length,omitempty"`   // Seconds
+	OvertimeLength     int            `gorm:"not null;default:0" json:"overtime_length,omitempty"` // Seconds
+	ClockRemainingMs   int64          `gorm:"not null;default:0" json:"-"`                         // Time left when the clock was last stopped or started
+	ClockStartedAt     *time.Time     `json:"-"`                                                   // Set while the clock runs
+	WinnerTeamID       *uuid.UUID     `gorm:"type:char(36)" json:"winner_team_id,omitempty"`
+	RefereeName        string         `gorm:"type:varchar(255)" json:"referee_name,omitempty"`
+	Notes              string         `gorm:"type:text" json:"notes,omitempty"`
+	IsPrivate          bool           `gorm:"not null;default:false" json:"is_private"` // Live feeds only for the teams' organizations, managers and scorekeepers
+	RescheduleCount    int            `gorm:"not null;default:0" json:"reschedule_count"`
+	RescheduleReason   string         `gorm:"type:varchar(500)" json:"reschedule_reason,omitempty"`
+	CancellationReason string         `gorm:"type:varchar(500)" json:"cancellation_reason,omitempty"`
+	CreatedBy          *uuid.UUID     `gorm:"type:char(36)" json:"created_by,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Tournament *Tournament `gorm:"foreignKey:TournamentID" json:"tournament,omitempty"`
+	HomeTeam   *Team       `gorm:"foreignKey:HomeTeamID" json:"home_team,omitempty"`
+	AwayTeam   *Team       `gorm:"foreignKey:AwayTeamID" json:"away_team,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (m *Match) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (Match) TableName() string {
+	return "matches"
+}
+
+// IsLive checks if the match is being played
+func (m *Match) IsLive() bool {
+	return m.Status == MatchStatusLive
+}
+
+// IsCompleted checks if the match has a final result
+func (m *Match) IsCompleted() bool {
+	return m.Status == MatchStatusCompleted
+}
+
+// HasTeam checks if the team plays in the match
+func (m *Match) HasTeam(teamID uuid.UUID) bool {
+	return m.HomeTeamID == teamID || m.AwayTeamID == teamID
+}
+
+// VenueLock is a row per venue that bookings at the venue lock, so two
+// matches can't be booked into the same slot at once
+type VenueLock struct {
+	Venue string `gorm:"type:varchar(255);primary_key"` // Lower case
+}
+
+// TableName specifies the table name
+func (VenueLock) TableName() string {
+	return "venue_locks"
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MatchEventType string
+
+const (
+	MatchEventPoint        MatchEventType = "point" // Made shot or free throw
+	MatchEventMiss         MatchEventType = "miss"  // Missed shot or free throw
+	MatchEventRebound      MatchEventType = "rebound"
+	MatchEventSteal        MatchEventType = "steal"
+	MatchEventBlock        MatchEventType = "block"
+	MatchEventTurnover     MatchEventType = "turnover"
+	MatchEventFoul         MatchEventType = "foul"
+	MatchEventTimeout      MatchEventType = "timeout"
+	MatchEventSubstitution MatchEventType = "substitution"
+	MatchEventQuarterEnd   MatchEventType = "quarter_end" // Ends any period, quarter or half
+	MatchEventVoid         MatchEventType = "void"        // Undoes an earlier event
+	MatchEventPeriodStart  MatchEventType = "period_start"
+	MatchEventClockStart   MatchEventType = "clock_start"
+	MatchEventClockStop    MatchEventType = "clock_stop"
+	MatchEventClockSet     MatchEventType = "clock_set"
+)
+
+// ScoringEventTypes are the events a scorekeeper records by hand and can undo
+var ScoringEventTypes = []MatchEventType{
+	MatchEventPoint,
+	MatchEventMiss,
+	MatchEventRebound,
+	MatchEventSteal,
+	MatchEventBlock,
+	MatchEventTurnover,
+	MatchEventFoul,
+	MatchEventTimeout,
+	MatchEventSubstitution,
+}
+
+// ShotZone is the area of the court a shot was taken from
+type ShotZone string
+
+const (
+	ShotZoneRestrictedArea ShotZone = "restricted_area" // Within the no-charge semi-circle under the basket
+	ShotZonePaint          ShotZone = "paint"           // Rest of the key
+	ShotZoneMidRange       ShotZone = "mid_range"       // Two-pointers outside the key
+	ShotZoneLeftCorner     ShotZone = "left_corner_three"
+	ShotZoneRightCorner    ShotZone = "right_corner_three"
+	ShotZoneAboveBreak     ShotZone = "above_break_three" // Three-pointers beyond the arc
+	ShotZoneFreeThrow      ShotZone = "free_throw"
+)
+
+// ShotZones lists the zones in the order shot charts report them
+var ShotZones = []ShotZone{
+	ShotZoneRestrictedArea,
+	ShotZonePaint,
+	ShotZoneMidRange,
+	ShotZoneLeftCorner,
+	ShotZoneRightCorner,
+	ShotZoneAboveBreak,
+	ShotZoneFreeThrow,
+}
+
+// Value is the points a made shot from the zone is worth
+func (z ShotZone) Value() int {
+	switch z {
+	case ShotZoneRestrictedArea, ShotZonePaint, ShotZoneMidRange:
+		return 2
+	case ShotZoneLeftCorner, ShotZoneRightCorner, ShotZoneAboveBreak:
+		return 3
+	case ShotZoneFreeThrow:
+		return 1
+	}
+	return 0
+}
+
+// MatchEvent is one entry of a match's live scoring log. Events are never
+// deleted: undoing one marks it voided and appends a void event, so every
+// change gets its own sequence number. Each event is stamped with the period
+// and game clock of the match when it was recorded.
+type MatchEvent struct {
+	ID                  uuid.UUID      `gorm:"type:char(36);primary_key" json:"id"`
+	MatchID             uuid.UUID      `gorm:"type:char(36);not null;uniqueIndex:idx_match_event_sequence" json:"match_id"`
+	Sequence            int            `gorm:"not null;uniqueIndex:idx_match_event_sequence" json:"sequence"`
+	Type                MatchEventType `gorm:"type:varchar(20);not null" json:"event_type"`
+	TeamID              *uuid.UUID     `gorm:"type:char(36);index" json:"team_id,omitempty"`
+	PlayerID            *uuid.UUID     `gorm:"type:char(36);index" json:"player_id,omitempty"`
+	SubstitutedPlayerID *uuid.UUID     `gorm:"type:char(36)" json:"substituted_player_id,omitempty"` // Player leaving the court
+	AssistPlayerID      *uuid.UUID     `gorm:"type:char(36)" json:"assist_player_id,omitempty"`
+	Points              int            `gorm:"not null;default:0" json:"points,omitempty"` // Points scored, or the value of a missed shot
+	ShotX               *float64       `json:"shot_x,omitempty"`                           // Shot location across the half court, 0 at the left sideline facing the basket, 1 at the right
+	ShotY               *float64       `json:"shot_y,omitempty"`                           // Shot location up the half court, 0 at the baseline, 1 at the half-court line
+	ShotZone            ShotZone       `gorm:"type:varchar(20)" json:"shot_zone,omitempty"`
+	Period              int            `gorm:"not null" json:"period"`
+	ClockRemainingMs    int64          `gorm:"not null;default:0" json:"clock_remaining_ms"` // Game clock when the event was recorded
+	TimeRemaining       string         `gorm:"type:varchar(10)" json:"time_remaining,omitempty"`
+	Description         string         `gorm:"type:varchar(500)" json:"description,omitempty"`
+	VoidsEventID        *uuid.UUID     `gorm:"type:char(36)" json:"voids_event_id,omitempty"`
+	Voided              bool           `gorm:"not null;default:false" json:"voided"`
+	RecordedBy          *uuid.UUID     `gorm:"type:char(36)" json:"recorded_by,omitempty"`
+	CreatedAt           time.Time      `json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (e *MatchEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (MatchEvent) TableName() string {
+	return "match_events"
+}
+
+// MatchScorekeeper allows a user to record the live events of a match
+type MatchScorekeeper struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primary_key" json:"id"`
+	MatchID    uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex:idx_match_scorekeeper" json:"match_id"`
+	UserID     uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex:idx_match_scorekeeper" json:"user_id"`
+	AssignedBy *uuid.UUID `gorm:"type:char(36)" json:"assigned_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (s *MatchScorekeeper) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (MatchScorekeeper) TableName() string {
+	return "match_scorekeepers"
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PlayerPosition string
+
+const (
+	PositionPointGuard    PlayerPosition = "PG"
+	PositionShootingGuard PlayerPosition = "SG"
+	PositionSmallForward  PlayerPosition = "SF"
+	PositionPowerForward  PlayerPosition = "PF"
+	PositionCenter        PlayerPosition = "C"
+)
+
+// IsValid checks if the position is one of the five basketball positions
+func (p PlayerPosition) IsValid() bool {
+	switch p {
+	case PositionPointGuard, PositionShootingGuard, PositionSmallForward, PositionPowerForward, PositionCenter:
+		return true
+	}
+	return false
+}
+
+type PlayerStatus string
+
+const (
+	PlayerStatusActive   PlayerStatus = "active"
+	PlayerStatusInactive PlayerStatus = "inactive"
+)
+
+type Player struct {
+	ID           uuid.UUID      `gorm:"type:char(36);primary_key" json:"id"`
+	TeamID       uuid.UUID      `gorm:"type:char(36);not null;index" json:"team_id"`
+	UserID       *uuid.UUID     `gorm:"type:char(36);uniqueIndex" json:"user_id,omitempty"` // Cleared when the player is deleted
+	JerseyNumber int            `gorm:"not null;uniqueIndex:idx_players_active_jersey,priority:2" json:"jersey_number"`
+	FullName     string         `gorm:"type:varchar(255);not null" json:"full_name"`
+	Position     PlayerPosition `gorm:"type:varchar(2);not null" json:"position"`
+	HeightCM     *int           `json:"height_cm,omitempty"`
+	WeightKG     *float64       `json:"weight_kg,omitempty"`
+	DateOfBirth  *time.Time     `gorm:"type:date" json:"date_of_birth,omitempty"`
+	PhotoURL     string         `gorm:"type:varchar(500)" json:"photo_url,omitempty"`
+	Status       PlayerStatus   `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// ActiveTeamID mirrors TeamID while the player is active and is NULL
+	// otherwise, so the unique index only applies to active jersey numbers
+	ActiveTeamID *uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_players_active_jersey,priority:1" json:"-"`
+
+	// Relationships
+	Team *Team `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	User *User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (p *Player) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeSave keeps ActiveTeamID in sync with the player's status
+func (p *Player) BeforeSave(tx *gorm.DB) error {
+	if p.IsActive() {
+		teamID := p.TeamID
+		p.ActiveTeamID = &teamID
+	} else {
+		p.ActiveTeamID = nil
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (Player) TableName() string {
+	return "players"
+}
+
+// IsActive checks if player is active
+func (p *Player) IsActive() bool {
+	return p.Status == PlayerStatusActive
+}
@@ -0,0 +1,189 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StatLine holds the counting stats of a box score line
+type StatLine struct {
+	Points                 int `gorm:"not null;default:0" json:"points"`
+	Rebounds               int `gorm:"not null;default:0" json:"rebounds"`
+	OffensiveRebounds      int `gorm:"not null;default:0" json:"offensive_rebounds"`
+	DefensiveRebounds      int `gorm:"not null;default:0" json:"defensive_rebounds"`
+	Assists                int `gorm:"not null;default:0" json:"assists"`
+	Steals                 int `gorm:"not null;default:0" json:"steals"`
+	Blocks                 int `gorm:"not null;default:0" json:"blocks"`
+	Turnovers              int `gorm:"not null;default:0" json:"turnovers"`
+	Fouls                  int `gorm:"not null;default:0" json:"fouls"`
+	SecondsPlayed          int `gorm:"not null;default:0" json:"seconds_played"`
+	PlusMinus              int `gorm:"not null;default:0" json:"plus_minus"` // Point differential while on court
+	FieldGoalsMade         int `gorm:"not null;default:0" json:"field_goals_made"`
+	FieldGoalsAttempted    int `gorm:"not null;default:0" json:"field_goals_attempted"`
+	ThreePointersMade      int `gorm:"not null;default:0" json:"three_pointers_made"`
+	ThreePointersAttempted int `gorm:"not null;default:0" json:"three_pointers_attempted"`
+	FreeThrowsMade         int `gorm:"not null;default:0" json:"free_throws_made"`
+	FreeThrowsAttempted    int `gorm:"not null;default:0" json:"free_throws_attempted"`
+}
+
+// Add adds another line's stats to this one
+func (l *StatLine) Add(other StatLine) {
+	l.Points += other.Points
+	l.Rebounds += other.Rebounds
+	l.OffensiveRebounds += other.OffensiveRebounds
+	l.DefensiveRebounds += other.DefensiveRebounds
+	l.Assists += other.Assists
+	l.Steals += other.Steals
+	l.Blocks += other.Blocks
+	l.Turnovers += other.Turnovers
+	l.Fouls += other.Fouls
+	l.SecondsPlayed += other.SecondsPlayed
+	l.PlusMinus += other.PlusMinus
+	l.FieldGoalsMade += other.FieldGoalsMade
+	l.FieldGoalsAttempted += other.FieldGoalsAttempted
+	l.ThreePointersMade += other.ThreePointersMade
+	l.ThreePointersAttempted += other.ThreePointersAttempted
+	l.FreeThrowsMade += other.FreeThrowsMade
+	l.FreeThrowsAttempted += other.FreeThrowsAttempted
+}
+
+// GameContext places a statistics row in its match so aggregates can be
+// scoped and split without joining matches
+type GameContext struct {
+	TournamentID   *uuid.UUID `gorm:"type:char(36);index" json:"tournament_id,omitempty"`
+	OpponentTeamID uuid.UUID  `gorm:"type:char(36);index" json:"opponent_team_id"`
+	Home           bool       `gorm:"not null;default:false" json:"home"`
+	Season         int        `gorm:"not null;default:0;index" json:"season"` // Calendar year of the match
+	PlayedAt       time.Time  `gorm:"index" json:"played_at"`
+	Final          bool       `gorm:"not null;default:false;index" json:"final"` // Only rows of completed matches count in aggregates
+	Won            bool       `gorm:"not null;default:false" json:"won"`
+}
+
+// PlayerStatistics is a player's box score line for one match, derived from
+// the match's event log
+type PlayerStatistics struct {
+	ID       uuid.UUID `gorm:"type:char(36);primary_key" json:"id"`
+	MatchID  uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_player_statistics_match" json:"match_id"`
+	PlayerID uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_player_statistics_match;index" json:"player_id"`
+	TeamID   uuid.UUID `gorm:"type:char(36);not null;index" json:"team_id"`
+	StatLine
+	GameContext
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (s *PlayerStatistics) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (PlayerStatistics) TableName() string {
+	return "player_statistics"
+}
+
+// TeamStatistics is a team's box score totals for one match, including the
+// team turnovers, fouls and rebounds credited to no player. Points and
+// OpponentPoints are the match's official score.
+type TeamStatistics struct {
+	ID      uuid.UUID `gorm:"type:char(36);primary_key" json:"id"`
+	MatchID uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_team_statistics_match" json:"match_id"`
+	TeamID  uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_team_statistics_match;index" json:"team_id"`
+	StatLine
+	OpponentPoints int `gorm:"not null;default:0" json:"opponent_points"`
+	GameContext
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (s *TeamStatistics) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (TeamStatistics) TableName() string {
+	return "team_statistics"
+}
+
+// BoxScoreState is the state a live match's box score is carried on from,
+// kept between events so a new event updates the stored lines without
+// replaying the log
+type BoxScoreState struct {
+	MatchID   uuid.UUID `gorm:"type:char(36);primary_key" json:"match_id"`
+	State     string    `gorm:"type:text;not null" json:"state"` // JSON encoded boxscore.State
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (BoxScoreState) TableName() string {
+	return "box_score_states"
+}
+
+// Statistics aggregate subjects and scopes
+const (
+	StatSubjectPlayer = "player"
+	StatSubjectTeam   = "team"
+
+	StatScopeTournament = "tournament"
+	StatScopeSeason     = "season"
+	StatScopeCareer     = "career"
+)
+
+// StatAggregate holds the totals of a player or team over the completed
+// matches of a tournament, a season or a whole career. Aggregates are
+// rebuilt from the final statistics rows whenever a match completes.
+type StatAggregate struct {
+	ID          uuid.UUID `gorm:"type:char(36);primary_key" json:"id"`
+	SubjectType string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_stat_aggregate" json:"subject_type"`
+	SubjectID   uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_stat_aggregate" json:"subject_id"`
+	Scope       string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_stat_aggregate" json:"scope"`
+	ScopeKey    string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_stat_aggregate" json:"scope_key"` // Tournament ID, season year, or empty for careers
+	Games       int       `gorm:"not null;default:0" json:"games"`
+	Wins        int       `gorm:"not null;default:0" json:"wins"`
+	StatLine
+	OpponentPoints int `gorm:"not null;default:0" json:"opponent_points"`
+
+	// Team totals over a player's games, the base of the player's usage rate
+	TeamFieldGoalsAttempted int `gorm:"not null;default:0" json:"team_field_goals_attempted,omitempty"`
+	TeamFreeThrowsAttempted int `gorm:"not null;default:0" json:"team_free_throws_attempted,omitempty"`
+	TeamTurnovers           int `gorm:"not null;default:0" json:"team_turnovers,omitempty"`
+	TeamSecondsPlayed       int `gorm:"not null;default:0" json:"team_seconds_played,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TeamLine is the part of the team's totals a player's usage rate is
+// measured against
+func (a *StatAggregate) TeamLine() StatLine {
+	return StatLine{
+		FieldGoalsAttempted: a.TeamFieldGoalsAttempted,
+		FreeThrowsAttempted: a.TeamFreeThrowsAttempted,
+		Turnovers:           a.TeamTurnovers,
+		SecondsPlayed:       a.TeamSecondsPlayed,
+	}
+}
+
+// BeforeCreate hook to generate UUID
+func (a *StatAggregate) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (StatAggregate) TableName() string {
+	return "stat_aggregates"
+}
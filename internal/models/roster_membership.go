@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RosterMembership is one stint of a player on a team. Stints cover the
+// half-open date range [StartDate, EndDate); the current stint has no EndDate.
+type RosterMembership struct {
+	ID           uuid.UUID  `gorm:"type:char(36);primary_key" json:"id"`
+	PlayerID     uuid.UUID  `gorm:"type:char(36);not null;index" json:"player_id"`
+	TeamID       uuid.UUID  `gorm:"type:char(36);not null;index:idx_roster_team_dates" json:"team_id"`
+	JerseyNumber int        `gorm:"not null" json:"jersey_number"`
+	StartDate    time.Time  `gorm:"type:date;not null;index:idx_roster_team_dates" json:"start_date"`
+	EndDate      *time.Time `gorm:"type:date;index:idx_roster_team_dates" json:"end_date,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+	Team   *Team   `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (m *RosterMembership) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (RosterMembership) TableName() string {
+	return "roster_memberships"
+}
+
+// IsCurrent checks if the stint is still open
+func (m *RosterMembership) IsCurrent() bool {
+	return m.EndDate == nil
+}
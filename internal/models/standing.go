@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Standing is a team's record and rank within its group of a tournament,
+// rebuilt from the tournament's completed matches whenever one completes
+type Standing struct {
+	ID            uuid.UUID `gorm:"type:char(36);primary_key" json:"id"`
+	TournamentID  uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_standing" json:"tournament_id"`
+	TeamID        uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_standing" json:"team_id"`
+	Group         string    `gorm:"column:group_name;type:varchar(50);not null;default:''" json:"group,omitempty"`
+	Rank          int       `gorm:"not null" json:"rank"` // Within the group
+	Played        int       `gorm:"not null;default:0" json:"played"`
+	Wins          int       `gorm:"not null;default:0" json:"wins"`
+	Losses        int       `gorm:"not null;default:0" json:"losses"`
+	PointsFor     int       `gorm:"not null;default:0" json:"points_for"`
+	PointsAgainst int       `gorm:"not null;default:0" json:"points_against"`
+	PointDiff     int       `gorm:"not null;default:0" json:"point_diff"`
+	WinPercentage float64   `gorm:"not null;default:0" json:"win_percentage"`
+	Tied          bool      `gorm:"not null;default:false" json:"tied"` // Level after every tiebreaker, ordered by seed
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relationships
+	Team *Team `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (s *Standing) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (Standing) TableName() string {
+	return "standings"
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TeamStatus string
+
+const (
+	TeamStatusActive   TeamStatus = "active"
+	TeamStatusInactive TeamStatus = "inactive"
+)
+
+type Team struct {
+	ID             uuid.UUID      `gorm:"type:char(36);primary_key" json:"id"`
+	OrganizationID uuid.UUID      `gorm:"type:char(36);not null;index" json:"organization_id"`
+	Name           string         `gorm:"type:varchar(255);not null" json:"name"`
+	LogoURL        string         `gorm:"type:varchar(500)" json:"logo_url,omitempty"`
+	CoachName      string         `gorm:"type:varchar(255)" json:"coach_name,omitempty"`
+	CoachPhone     string         `gorm:"type:varchar(20)" json:"coach_phone,omitempty"`
+	CoachEmail     string         `gorm:"type:varchar(255)" json:"coach_email,omitempty"`
+	Description    string         `gorm:"type:text" json:"description,omitempty"`
+	Status         TeamStatus     `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (t *Team) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (Team) TableName() string {
+	return "teams"
+}
+
+// IsActive checks if team is active
+func (t *Team) IsActive() bool {
+	return t.Status == TeamStatusActive
+}
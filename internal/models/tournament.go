@@ -0,0 +1,151 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TournamentStatus string
+
+const (
+	TournamentStatusUpcoming  TournamentStatus = "upcoming"
+	TournamentStatusOngoing   TournamentStatus = "ongoing"
+	TournamentStatusCompleted TournamentStatus = "completed"
+)
+
+// tournamentTransitions lists the statuses each status may move to
+var tournamentTransitions = map[TournamentStatus][]TournamentStatus{
+	TournamentStatusUpcoming: {TournamentStatusOngoing},
+	TournamentStatusOngoing:  {TournamentStatusCompleted},
+}
+
+// CanTransitionTo checks if a tournament may move from s to next
+func (s TournamentStatus) CanTransitionTo(next TournamentStatus) bool {
+	for _, allowed := range tournamentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Tiebreaker separates teams level on win percentage in the standings
+type Tiebreaker string
+
+const (
+	TiebreakHeadToHead          Tiebreaker = "head_to_head"            // Win percentage in games among the tied teams
+	TiebreakHeadToHeadPointDiff Tiebreaker = "head_to_head_point_diff" // Point differential in games among the tied teams
+	TiebreakPointDiff           Tiebreaker = "point_diff"              // Point differential in all games
+	TiebreakPointsFor           Tiebreaker = "points_for"              // Points scored in all games
+	TiebreakManual              Tiebreaker = "manual"                  // Coin toss or organizer ruling, from the entries' tiebreak_rank
+)
+
+// DefaultTiebreakers is the chain used by tournaments that set none
+var DefaultTiebreakers = []Tiebreaker{
+	TiebreakHeadToHead,
+	TiebreakHeadToHeadPointDiff,
+	TiebreakPointDiff,
+	TiebreakPointsFor,
+	TiebreakManual,
+}
+
+// IsValid checks if the tiebreaker is a known one
+func (t Tiebreaker) IsValid() bool {
+	for _, known := range DefaultTiebreakers {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+type Tournament struct {
+	ID              uuid.UUID        `gorm:"type:char(36);primary_key" json:"id"`
+	OrganizationID  *uuid.UUID       `gorm:"type:char(36);index" json:"organization_id,omitempty"` // Owning organization, nil for leagues run by super admins
+	Name            string           `gorm:"type:varchar(255);not null" json:"name"`
+	Description     string           `gorm:"type:text" json:"description,omitempty"`
+	StartDate       time.Time        `gorm:"type:date;not null" json:"start_date"`
+	EndDate         time.Time        `gorm:"type:date;not null" json:"end_date"`
+	Status          TournamentStatus `gorm:"type:varchar(20);not null;default:'upcoming';index" json:"status"`
+	PeriodCount     *int             `json:"period_count,omitempty"` // Game rules for its matches, nil for the server defaults
+	PeriodMinutes   *int             `json:"period_minutes,omitempty"`
+	OvertimeMinutes *int             `json:"overtime_minutes,omitempty"`
+	Tiebreakers     string           `gorm:"type:varchar(255)" json:"tiebreakers,omitempty"` // Comma-separated tiebreaker chain, empty for the default
+	CreatedBy       *uuid.UUID       `gorm:"type:char(36)" json:"created_by,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt   `gorm:"index" json:"-"`
+
+	// Relationships
+	Organization *Organization     `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Entries      []TournamentEntry `gorm:"foreignKey:TournamentID" json:"entries,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (t *Tournament) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (Tournament) TableName() string {
+	return "tournaments"
+}
+
+// TiebreakerChain is the order the tournament's tiebreakers are applied in
+func (t *Tournament) TiebreakerChain() []Tiebreaker {
+	if t.Tiebreakers == "" {
+		return DefaultTiebreakers
+	}
+	var chain []Tiebreaker
+	for _, name := range strings.Split(t.Tiebreakers, ",") {
+		chain = append(chain, Tiebreaker(name))
+	}
+	return chain
+}
+
+// IsUpcoming checks if the tournament has not started yet
+func (t *Tournament) IsUpcoming() bool {
+	return t.Status == TournamentStatusUpcoming
+}
+
+// CanBeManagedBy checks if a user may modify the tournament. Only super
+// admins manage tournaments without an owning organization.
+func (t *Tournament) CanBeManagedBy(user *User) bool {
+	if user.IsAdmin() {
+		return true
+	}
+	return t.OrganizationID != nil && user.CanManageOrganization(*t.OrganizationID)
+}
+
+// TournamentEntry registers a team in a tournament
+type TournamentEntry struct {
+	ID           uuid.UUID `gorm:"type:char(36);primary_key" json:"id"`
+	TournamentID uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_tournament_entry" json:"tournament_id"`
+	TeamID       uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_tournament_entry;index" json:"team_id"`
+	Seed         *int      `json:"seed,omitempty"`
+	Group        string    `gorm:"column:group_name;type:varchar(50);not null;default:''" json:"group,omitempty"` // Group or conference the team is ranked in
+	TiebreakRank *int      `json:"tiebreak_rank,omitempty"`                                                       // Coin toss or organizer ruling, lower first among teams still tied
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relationships
+	Team *Team `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (e *TournamentEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (TournamentEntry) TableName() string {
+	return "tournament_entries"
+}
@@ -0,0 +1,132 @@
+package narration
+
+type message int
+
+const (
+	msgMakes message = iota
+	msgMisses
+	msgAssist
+	msgFreeThrow
+	msgLayup
+	msgPaintShot
+	msgJumper
+	msgTwo
+	msgThree
+	msgOffensiveRebound
+	msgDefensiveRebound
+	msgTeamRebound
+	msgSteal
+	msgBlock
+	msgTurnover
+	msgTeamTurnover
+	msgFoul
+	msgTeamFoul
+	msgTimeout
+	msgSubstitution
+	msgPeriodStart
+	msgPeriodEnd
+	msgQuarter
+	msgHalf
+	msgOvertime
+	msgPeriod
+	msgVoid
+	msgUnknownPlayer
+	msgUnknownTeam
+)
+
+// catalogs holds the narration templates of each language
+var catalogs = map[Language]map[message]string{
+	English: {
+		msgMakes:            "%s makes %s",
+		msgMisses:           "%s misses %s",
+		msgAssist:           " (assist: %s)",
+		msgFreeThrow:        "free throw",
+		msgLayup:            "layup",
+		msgPaintShot:        "2-pt shot in the paint",
+		msgJumper:           "2-pt jumper",
+		msgTwo:              "2-pt shot",
+		msgThree:            "3-pt jumper",
+		msgOffensiveRebound: "%s offensive rebound",
+		msgDefensiveRebound: "%s defensive rebound",
+		msgTeamRebound:      "%s team rebound",
+		msgSteal:            "%s steal",
+		msgBlock:            "%s block",
+		msgTurnover:         "%s turnover",
+		msgTeamTurnover:     "%s team turnover",
+		msgFoul:             "Foul on %s",
+		msgTeamFoul:         "%s team foul",
+		msgTimeout:          "Timeout: %s",
+		msgSubstitution:     "%s enters the game for %s",
+		msgPeriodStart:      "Start of %s",
+		msgPeriodEnd:        "End of %s",
+		msgQuarter:          "quarter %d",
+		msgHalf:             "half %d",
+		msgOvertime:         "overtime %d",
+		msgPeriod:           "period %d",
+		msgVoid:             "Play removed: %s",
+		msgUnknownPlayer:    "Unknown player",
+		msgUnknownTeam:      "Unknown team",
+	},
+	Spanish: {
+		msgMakes:            "%s anota %s",
+		msgMisses:           "%s falla %s",
+		msgAssist:           " (asistencia: %s)",
+		msgFreeThrow:        "tiro libre",
+		msgLayup:            "bandeja",
+		msgPaintShot:        "tiro de 2 en la pintura",
+		msgJumper:           "tiro de media distancia",
+		msgTwo:              "tiro de 2",
+		msgThree:            "triple",
+		msgOffensiveRebound: "Rebote ofensivo de %s",
+		msgDefensiveRebound: "Rebote defensivo de %s",
+		msgTeamRebound:      "Rebote de equipo de %s",
+		msgSteal:            "Robo de %s",
+		msgBlock:            "Tapón de %s",
+		msgTurnover:         "Pérdida de %s",
+		msgTeamTurnover:     "Pérdida de equipo de %s",
+		msgFoul:             "Falta de %s",
+		msgTeamFoul:         "Falta de equipo de %s",
+		msgTimeout:          "Tiempo muerto: %s",
+		msgSubstitution:     "%s entra por %s",
+		msgPeriodStart:      "Comienza %s",
+		msgPeriodEnd:        "Termina %s",
+		msgQuarter:          "el cuarto %d",
+		msgHalf:             "la parte %d",
+		msgOvertime:         "la prórroga %d",
+		msgPeriod:           "el periodo %d",
+		msgVoid:             "Jugada anulada: %s",
+		msgUnknownPlayer:    "Jugador desconocido",
+		msgUnknownTeam:      "Equipo desconocido",
+	},
+	French: {
+		msgMakes:            "%s réussit %s",
+		msgMisses:           "%s manque %s",
+		msgAssist:           " (passe décisive : %s)",
+		msgFreeThrow:        "un lancer franc",
+		msgLayup:            "un lay-up",
+		msgPaintShot:        "un tir à 2 points dans la raquette",
+		msgJumper:           "un tir à mi-distance",
+		msgTwo:              "un tir à 2 points",
+		msgThree:            "un tir à 3 points",
+		msgOffensiveRebound: "Rebond offensif de %s",
+		msgDefensiveRebound: "Rebond défensif de %s",
+		msgTeamRebound:      "Rebond d'équipe pour %s",
+		msgSteal:            "Interception de %s",
+		msgBlock:            "Contre de %s",
+		msgTurnover:         "Perte de balle de %s",
+		msgTeamTurnover:     "Perte de balle collective pour %s",
+		msgFoul:             "Faute de %s",
+		msgTeamFoul:         "Faute d'équipe pour %s",
+		msgTimeout:          "Temps mort : %s",
+		msgSubstitution:     "%s remplace %s",
+		msgPeriodStart:      "%s : début",
+		msgPeriodEnd:        "%s : fin",
+		msgQuarter:          "Quart-temps %d",
+		msgHalf:             "Mi-temps %d",
+		msgOvertime:         "Prolongation %d",
+		msgPeriod:           "Période %d",
+		msgVoid:             "Action annulée : %s",
+		msgUnknownPlayer:    "Joueur inconnu",
+		msgUnknownTeam:      "Équipe inconnue",
+	},
+}
@@ -0,0 +1,217 @@
+// Package narration renders a match's events as play-by-play commentary,
+// such as "#23 Smith makes 3-pt jumper (assist: #5 Lee)", in the languages it
+// has a catalog for.
+package narration
+
+import (
+	"fmt"
+	"strings"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Language is a language narration is available in, as a two-letter code
+type Language string
+
+const (
+	English Language = "en"
+	Spanish Language = "es"
+	French  Language = "fr"
+)
+
+// Languages lists the supported languages, the default first
+var Languages = []Language{English, Spanish, French}
+
+// Negotiate picks the first supported language named by the values, each a
+// language code or an Accept-Language header listing them in order of
+// preference. Region subtags are ignored, and English is the fallback.
+func Negotiate(values ...string) Language {
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(strings.SplitN(tag, ";", 2)[0])
+			code := Language(strings.ToLower(strings.SplitN(tag, "-", 2)[0]))
+			if _, ok := catalogs[code]; ok {
+				return code
+			}
+		}
+	}
+	return English
+}
+
+// Play is a narrated event with the score once it is in
+type Play struct {
+	Event     *models.MatchEvent
+	Text      string
+	HomeScore int
+	AwayScore int
+}
+
+// Narrate renders the events of a match ordered by sequence, with the running
+// score after each. Voided events are still narrated but their points don't
+// count, and the void event that undid one says so. Game clock starts, stops
+// and corrections are left out. players holds the players the events name.
+func Narrate(match *models.Match, events []models.MatchEvent, players map[uuid.UUID]models.Player, lang Language) []Play {
+	n := &narrator{match: match, players: players, text: catalogs[lang]}
+	if n.text == nil {
+		n.text = catalogs[English]
+	}
+
+	var plays []Play
+	var home, away int
+	var lastMiss *uuid.UUID // Team of the latest missed shot not yet rebounded
+	texts := map[uuid.UUID]string{}
+	for i := range events {
+		e := &events[i]
+		var text string
+		switch e.Type {
+		case models.MatchEventClockStart, models.MatchEventClockStop, models.MatchEventClockSet:
+			continue
+		case models.MatchEventVoid:
+			if e.VoidsEventID != nil {
+				text = n.say(msgVoid, texts[*e.VoidsEventID])
+			}
+		case models.MatchEventPeriodStart:
+			text = n.say(msgPeriodStart, n.period(e.Period))
+		case models.MatchEventQuarterEnd:
+			text = n.say(msgPeriodEnd, n.period(e.Period))
+		case models.MatchEventRebound:
+			offensive := lastMiss != nil && e.TeamID != nil && *lastMiss == *e.TeamID
+			text = n.rebound(e, offensive)
+		default:
+			text = n.narrate(e)
+		}
+		texts[e.ID] = text
+
+		if !e.Voided && e.TeamID != nil {
+			switch e.Type {
+			case models.MatchEventPoint:
+				if *e.TeamID == match.HomeTeamID {
+					home += e.Points
+				} else {
+					away += e.Points
+				}
+				lastMiss = nil
+			case models.MatchEventMiss:
+				lastMiss = e.TeamID
+			case models.MatchEventRebound:
+				lastMiss = nil
+			}
+		}
+		plays = append(plays, Play{Event: e, Text: text, HomeScore: home, AwayScore: away})
+	}
+	return plays
+}
+
+type narrator struct {
+	match   *models.Match
+	players map[uuid.UUID]models.Player
+	text    map[message]string
+}
+
+func (n *narrator) narrate(e *models.MatchEvent) string {
+	switch e.Type {
+	case models.MatchEventPoint:
+		text := n.say(msgMakes, n.player(e.PlayerID), n.shot(e))
+		if e.AssistPlayerID != nil {
+			text += n.say(msgAssist, n.player(e.AssistPlayerID))
+		}
+		return text
+	case models.MatchEventMiss:
+		return n.say(msgMisses, n.player(e.PlayerID), n.shot(e))
+	case models.MatchEventSteal:
+		return n.say(msgSteal, n.player(e.PlayerID))
+	case models.MatchEventBlock:
+		return n.say(msgBlock, n.player(e.PlayerID))
+	case models.MatchEventTurnover:
+		if e.PlayerID == nil {
+			return n.say(msgTeamTurnover, n.team(e.TeamID))
+		}
+		return n.say(msgTurnover, n.player(e.PlayerID))
+	case models.MatchEventFoul:
+		if e.PlayerID == nil {
+			return n.say(msgTeamFoul, n.team(e.TeamID))
+		}
+		return n.say(msgFoul, n.player(e.PlayerID))
+	case models.MatchEventTimeout:
+		return n.say(msgTimeout, n.team(e.TeamID))
+	case models.MatchEventSubstitution:
+		return n.say(msgSubstitution, n.player(e.PlayerID), n.player(e.SubstitutedPlayerID))
+	}
+	return e.Description
+}
+
+func (n *narrator) rebound(e *models.MatchEvent, offensive bool) string {
+	switch {
+	case e.PlayerID == nil:
+		return n.say(msgTeamRebound, n.team(e.TeamID))
+	case offensive:
+		return n.say(msgOffensiveRebound, n.player(e.PlayerID))
+	}
+	return n.say(msgDefensiveRebound, n.player(e.PlayerID))
+}
+
+// period names a period of the match: a quarter or half of regulation, or an
+// overtime
+func (n *narrator) period(period int) string {
+	count := n.match.PeriodCount
+	switch {
+	case count > 0 && period > count:
+		return n.say(msgOvertime, period-count)
+	case count == 4:
+		return n.say(msgQuarter, period)
+	case count == 2:
+		return n.say(msgHalf, period)
+	}
+	return n.say(msgPeriod, period)
+}
+
+// shot names a shot from its value and, for field goals, its zone
+func (n *narrator) shot(e *models.MatchEvent) string {
+	switch {
+	case e.Points == 1:
+		return n.text[msgFreeThrow]
+	case e.Points == 3:
+		return n.text[msgThree]
+	case e.ShotZone == models.ShotZoneRestrictedArea:
+		return n.text[msgLayup]
+	case e.ShotZone == models.ShotZonePaint:
+		return n.text[msgPaintShot]
+	case e.ShotZone == models.ShotZoneMidRange:
+		return n.text[msgJumper]
+	}
+	return n.text[msgTwo]
+}
+
+// player names a player by jersey number and surname, the last word of the
+// full name
+func (n *narrator) player(id *uuid.UUID) string {
+	if id == nil {
+		return n.text[msgUnknownPlayer]
+	}
+	p, ok := n.players[*id]
+	if !ok {
+		return n.text[msgUnknownPlayer]
+	}
+	names := strings.Fields(p.FullName)
+	if len(names) == 0 {
+		return fmt.Sprintf("#%d", p.JerseyNumber)
+	}
+	return fmt.Sprintf("#%d %s", p.JerseyNumber, names[len(names)-1])
+}
+
+func (n *narrator) team(id *uuid.UUID) string {
+	switch {
+	case id == nil:
+	case *id == n.match.HomeTeamID && n.match.HomeTeam != nil:
+		return n.match.HomeTeam.Name
+	case *id == n.match.AwayTeamID && n.match.AwayTeam != nil:
+		return n.match.AwayTeam.Name
+	}
+	return n.text[msgUnknownTeam]
+}
+
+func (n *narrator) say(msg message, args ...interface{}) string {
+	return fmt.Sprintf(n.text[msg], args...)
+}
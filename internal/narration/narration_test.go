@@ -0,0 +1,241 @@
+package narration
+
+import (
+	"testing"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []string
+		want   Language
+	}{
+		{"nothing asked", nil, English},
+		{"code", []string{"es"}, Spanish},
+		{"upper case", []string{"FR"}, French},
+		{"region ignored", []string{"fr-CA"}, French},
+		{"header order", []string{"de-DE,de;q=0.9,es;q=0.8,en;q=0.7"}, Spanish},
+		{"quality and spaces", []string{" fr ; q=0.5 , en"}, French},
+		{"unsupported only", []string{"de,it"}, English},
+		{"empty query before the header", []string{"", "es-MX"}, Spanish},
+		{"query before the header", []string{"fr", "es"}, French},
+		{"unsupported query falls to the header", []string{"pt", "es"}, Spanish},
+	}
+	for _, c := range cases {
+		if got := Negotiate(c.values...); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCatalogs(t *testing.T) {
+	for _, lang := range Languages {
+		for msg := msgMakes; msg <= msgUnknownTeam; msg++ {
+			if catalogs[lang][msg] == "" {
+				t.Errorf("%s: message %d missing", lang, msg)
+			}
+		}
+	}
+}
+
+// game is a match between Home and Away with a player on each side
+type game struct {
+	match   *models.Match
+	players map[uuid.UUID]models.Player
+	home    uuid.UUID
+	away    uuid.UUID
+	smith   uuid.UUID // Home #23
+	lee     uuid.UUID // Away #5
+}
+
+func newGame(periods int) *game {
+	g := &game{home: uuid.New(), away: uuid.New(), smith: uuid.New(), lee: uuid.New()}
+	g.match = &models.Match{
+		HomeTeamID:  g.home,
+		AwayTeamID:  g.away,
+		HomeTeam:    &models.Team{Name: "Home"},
+		AwayTeam:    &models.Team{Name: "Away"},
+		PeriodCount: periods,
+	}
+	g.players = map[uuid.UUID]models.Player{
+		g.smith: {ID: g.smith, FullName: "Ana Smith", JerseyNumber: 23},
+		g.lee:   {ID: g.lee, FullName: "Jo Lee", JerseyNumber: 5},
+	}
+	return g
+}
+
+func TestNarrateLanguages(t *testing.T) {
+	g := newGame(4)
+	unknown := uuid.New()
+	events := []models.MatchEvent{
+		{Type: models.MatchEventPeriodStart, Period: 1},
+		{Type: models.MatchEventPoint, TeamID: &g.home, PlayerID: &g.smith, Points: 3, AssistPlayerID: &g.lee},
+		{Type: models.MatchEventPoint, TeamID: &g.home, PlayerID: &g.smith, Points: 2, ShotZone: models.ShotZoneRestrictedArea},
+		{Type: models.MatchEventMiss, TeamID: &g.away, PlayerID: &g.lee, Points: 2, ShotZone: models.ShotZoneMidRange},
+		{Type: models.MatchEventPoint, TeamID: &g.away, PlayerID: &g.lee, Points: 1},
+		{Type: models.MatchEventSteal, TeamID: &g.home, PlayerID: &g.smith},
+		{Type: models.MatchEventBlock, TeamID: &g.away, PlayerID: &unknown},
+		{Type: models.MatchEventTurnover, TeamID: &g.away},
+		{Type: models.MatchEventFoul, TeamID: &g.home, PlayerID: &g.smith},
+		{Type: models.MatchEventTimeout, TeamID: &g.away},
+		{Type: models.MatchEventSubstitution, TeamID: &g.away, PlayerID: &g.lee, SubstitutedPlayerID: &unknown},
+		{Type: models.MatchEventQuarterEnd, Period: 5},
+	}
+	want := map[Language][]string{
+		English: {
+			"Start of quarter 1",
+			"#23 Smith makes 3-pt jumper (assist: #5 Lee)",
+			"#23 Smith makes layup",
+			"#5 Lee misses 2-pt jumper",
+			"#5 Lee makes free throw",
+			"#23 Smith steal",
+			"Unknown player block",
+			"Away team turnover",
+			"Foul on #23 Smith",
+			"Timeout: Away",
+			"#5 Lee enters the game for Unknown player",
+			"End of overtime 1",
+		},
+		Spanish: {
+			"Comienza el cuarto 1",
+			"#23 Smith anota triple (asistencia: #5 Lee)",
+			"#23 Smith anota bandeja",
+			"#5 Lee falla tiro de media distancia",
+			"#5 Lee anota tiro libre",
+			"Robo de #23 Smith",
+			"Tapón de Jugador desconocido",
+			"Pérdida de equipo de Away",
+			"Falta de #23 Smith",
+			"Tiempo muerto: Away",
+			"#5 Lee entra por Jugador desconocido",
+			"Termina la prórroga 1",
+		},
+		French: {
+			"Quart-temps 1 : début",
+			"#23 Smith réussit un tir à 3 points (passe décisive : #5 Lee)",
+			"#23 Smith réussit un lay-up",
+			"#5 Lee manque un tir à mi-distance",
+			"#5 Lee réussit un lancer franc",
+			"Interception de #23 Smith",
+			"Contre de Joueur inconnu",
+			"Perte de balle collective pour Away",
+			"Faute de #23 Smith",
+			"Temps mort : Away",
+			"#5 Lee remplace Joueur inconnu",
+			"Prolongation 1 : fin",
+		},
+	}
+	for lang, texts := range want {
+		plays := Narrate(g.match, events, g.players, lang)
+		if len(plays) != len(texts) {
+			t.Fatalf("%s: got %d plays, want %d", lang, len(plays), len(texts))
+		}
+		for i, play := range plays {
+			if play.Text != texts[i] {
+				t.Errorf("%s play %d: got %q, want %q", lang, i, play.Text, texts[i])
+			}
+		}
+	}
+
+	// Unsupported languages fall back to English
+	if plays := Narrate(g.match, events[:1], g.players, "de"); plays[0].Text != "Start of quarter 1" {
+		t.Errorf("unsupported language: got %q", plays[0].Text)
+	}
+}
+
+func TestNarratePeriods(t *testing.T) {
+	cases := []struct {
+		periods int
+		period  int
+		want    string
+	}{
+		{4, 1, "Start of quarter 1"},
+		{4, 4, "Start of quarter 4"},
+		{4, 5, "Start of overtime 1"},
+		{4, 6, "Start of overtime 2"},
+		{2, 2, "Start of half 2"},
+		{2, 3, "Start of overtime 1"},
+		{0, 3, "Start of period 3"},
+	}
+	for _, c := range cases {
+		g := newGame(c.periods)
+		events := []models.MatchEvent{{Type: models.MatchEventPeriodStart, Period: c.period}}
+		if got := Narrate(g.match, events, g.players, English)[0].Text; got != c.want {
+			t.Errorf("%d periods, period %d: got %q, want %q", c.periods, c.period, got, c.want)
+		}
+	}
+}
+
+func TestNarrateRebounds(t *testing.T) {
+	g := newGame(4)
+	miss := func(team uuid.UUID) models.MatchEvent {
+		return models.MatchEvent{Type: models.MatchEventMiss, TeamID: &team, Points: 2}
+	}
+	rebound := func(team uuid.UUID, player *uuid.UUID) models.MatchEvent {
+		return models.MatchEvent{Type: models.MatchEventRebound, TeamID: &team, PlayerID: player}
+	}
+	voided := miss(g.home)
+	voided.Voided = true
+	cases := []struct {
+		name   string
+		events []models.MatchEvent
+		want   string
+	}{
+		{"own miss", []models.MatchEvent{miss(g.home), rebound(g.home, &g.smith)}, "#23 Smith offensive rebound"},
+		{"opponent's miss", []models.MatchEvent{miss(g.away), rebound(g.home, &g.smith)}, "#23 Smith defensive rebound"},
+		{"no miss before", []models.MatchEvent{rebound(g.home, &g.smith)}, "#23 Smith defensive rebound"},
+		{"miss already rebounded", []models.MatchEvent{miss(g.home), rebound(g.away, &g.lee), rebound(g.home, &g.smith)},
+			"#23 Smith defensive rebound"},
+		{"basket since the miss", []models.MatchEvent{miss(g.home),
+			{Type: models.MatchEventPoint, TeamID: &g.away, PlayerID: &g.lee, Points: 1}, rebound(g.home, &g.smith)},
+			"#23 Smith defensive rebound"},
+		{"voided miss", []models.MatchEvent{voided, rebound(g.home, &g.smith)}, "#23 Smith defensive rebound"},
+		{"team rebound", []models.MatchEvent{miss(g.home), rebound(g.home, nil)}, "Home team rebound"},
+	}
+	for _, c := range cases {
+		plays := Narrate(g.match, c.events, g.players, English)
+		if got := plays[len(plays)-1].Text; got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNarrateScoreAndVoids(t *testing.T) {
+	g := newGame(4)
+	three := models.MatchEvent{ID: uuid.New(), Type: models.MatchEventPoint, TeamID: &g.home, PlayerID: &g.smith,
+		Points: 3, Voided: true}
+	events := []models.MatchEvent{
+		{ID: uuid.New(), Type: models.MatchEventPoint, TeamID: &g.home, PlayerID: &g.smith, Points: 2},
+		{ID: uuid.New(), Type: models.MatchEventClockStop},
+		three,
+		{ID: uuid.New(), Type: models.MatchEventPoint, TeamID: &g.away, PlayerID: &g.lee, Points: 1},
+		{ID: uuid.New(), Type: models.MatchEventVoid, TeamID: &g.home, VoidsEventID: &three.ID},
+		{ID: uuid.New(), Type: models.MatchEventClockSet},
+		{ID: uuid.New(), Type: models.MatchEventPoint, TeamID: &g.away, PlayerID: &g.lee, Points: 3},
+	}
+	want := []struct {
+		text       string
+		home, away int
+	}{
+		{"#23 Smith makes 2-pt shot", 2, 0},
+		{"#23 Smith makes 3-pt jumper", 2, 0},
+		{"#5 Lee makes free throw", 2, 1},
+		{"Play removed: #23 Smith makes 3-pt jumper", 2, 1},
+		{"#5 Lee makes 3-pt jumper", 2, 4},
+	}
+	plays := Narrate(g.match, events, g.players, English)
+	if len(plays) != len(want) {
+		t.Fatalf("got %d plays, want %d", len(plays), len(want))
+	}
+	for i, play := range plays {
+		w := want[i]
+		if play.Text != w.text || play.HomeScore != w.home || play.AwayScore != w.away {
+			t.Errorf("play %d: got %q %d-%d, want %q %d-%d", i, play.Text, play.HomeScore, play.AwayScore,
+				w.text, w.home, w.away)
+		}
+	}
+}
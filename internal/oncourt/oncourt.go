@@ -0,0 +1,187 @@
+// Package oncourt reconstructs which players each team has on court, and for
+// how long, from a match's events. Starters are never recorded, so a player
+// whose first appearance is entering by substitution came off the bench, and
+// any other player started the game.
+package oncourt
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Size is the number of players a team has on court
+const Size = 5
+
+var (
+	ErrAlreadyOnCourt = errors.New("player entering is already on court")
+	ErrNotOnCourt     = errors.New("player is not on court")
+	ErrTooManyOnCourt = errors.New("event would leave the team with more than five players on court")
+)
+
+// Court is who each team has on court as a match's events are applied in
+// order. It marshals to JSON so it can be kept between events.
+type Court struct {
+	Teams   map[uuid.UUID]uuid.UUID     `json:"teams"`    // Team of each player seen
+	Started map[uuid.UUID]bool          `json:"started"`  // Players first seen anywhere but entering by substitution
+	OnSince map[uuid.UUID]time.Duration `json:"on_since"` // Players on court and the game time they came on
+	Played  map[uuid.UUID]time.Duration `json:"played"`   // Time on court in finished stints
+}
+
+func New() *Court {
+	return &Court{
+		Teams:   map[uuid.UUID]uuid.UUID{},
+		Started: map[uuid.UUID]bool{},
+		OnSince: map[uuid.UUID]time.Duration{},
+		Played:  map[uuid.UUID]time.Duration{},
+	}
+}
+
+// Replay builds the court left by a match's events ordered by sequence,
+// ignoring game time
+func Replay(events []models.MatchEvent) *Court {
+	c := New()
+	for i := range events {
+		if Moves(&events[i]) {
+			c.Apply(&events[i], 0)
+		}
+	}
+	return c
+}
+
+// Moves reports whether an event can move players: it is in effect, and
+// credited to a team
+func Moves(e *models.MatchEvent) bool {
+	return !e.Voided && e.Type != models.MatchEventVoid && e.TeamID != nil
+}
+
+// Apply moves the players an event names on or off court at game time now.
+// It returns the players it found to have started the game, who are on
+// court from its start. Fouls can be called on players on the bench, so they
+// tell nothing of who is on court.
+func (c *Court) Apply(e *models.MatchEvent, now time.Duration) []uuid.UUID {
+	if e.Type == models.MatchEventFoul {
+		return nil
+	}
+	var starters []uuid.UUID
+	appear := func(player uuid.UUID, starter bool) {
+		if c.Appear(*e.TeamID, player, starter) && starter {
+			starters = append(starters, player)
+		}
+	}
+
+	if e.Type == models.MatchEventSubstitution {
+		if e.SubstitutedPlayerID != nil {
+			appear(*e.SubstitutedPlayerID, true)
+			if since, ok := c.OnSince[*e.SubstitutedPlayerID]; ok {
+				c.Played[*e.SubstitutedPlayerID] += now - since
+				delete(c.OnSince, *e.SubstitutedPlayerID)
+			}
+		}
+		if e.PlayerID != nil {
+			appear(*e.PlayerID, false)
+			if _, ok := c.OnSince[*e.PlayerID]; !ok {
+				c.OnSince[*e.PlayerID] = now
+			}
+		}
+		return starters
+	}
+	for _, playerID := range []*uuid.UUID{e.PlayerID, e.AssistPlayerID} {
+		if playerID != nil {
+			appear(*playerID, true)
+		}
+	}
+	return starters
+}
+
+// Appear records a player of a team, a starter on court from the start of the
+// game or a substitute still on the bench, unless already seen. It reports
+// whether the player is new.
+func (c *Court) Appear(team, player uuid.UUID, starter bool) bool {
+	if c.Seen(player) {
+		return false
+	}
+	c.Teams[player] = team
+	c.Started[player] = starter
+	if starter {
+		c.OnSince[player] = 0
+	}
+	return true
+}
+
+// Seen reports whether a player has appeared
+func (c *Court) Seen(player uuid.UUID) bool {
+	_, ok := c.Teams[player]
+	return ok
+}
+
+// On reports whether a player is on court
+func (c *Court) On(player uuid.UUID) bool {
+	_, ok := c.OnSince[player]
+	return ok
+}
+
+// Lineup is the players a team has on court, sorted by ID
+func (c *Court) Lineup(team uuid.UUID) []uuid.UUID {
+	var players []uuid.UUID
+	for player := range c.OnSince {
+		if c.Teams[player] == team {
+			players = append(players, player)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].String() < players[j].String() })
+	return players
+}
+
+// TimePlayed is a player's time on court up to game time now
+func (c *Court) TimePlayed(player uuid.UUID, now time.Duration) time.Duration {
+	played := c.Played[player]
+	if since, ok := c.OnSince[player]; ok {
+		played += now - since
+	}
+	return played
+}
+
+// Check tells if a substitution of a team is possible on the court: the
+// player entering must be on the bench, the player leaving on court, and the
+// team must not end up with more than five players on court
+func (c *Court) Check(teamID, entering, leaving uuid.UUID) error {
+	switch {
+	case c.On(entering):
+		return ErrAlreadyOnCourt
+	case c.Seen(leaving) && !c.On(leaving):
+		return ErrNotOnCourt
+	}
+	count := len(c.Lineup(teamID))
+	if !c.Seen(leaving) {
+		count++ // First seen leaving, so a starter
+	}
+	if count > Size {
+		return ErrTooManyOnCourt
+	}
+	return nil
+}
+
+// CheckPlaying tells if players of a team can be credited with an event on
+// the court: each must be on court, or, first seen now and so a starter,
+// still fit on it with fewer than five teammates there
+func (c *Court) CheckPlaying(teamID uuid.UUID, players ...uuid.UUID) error {
+	count := len(c.Lineup(teamID))
+	for _, player := range players {
+		if c.Seen(player) {
+			if !c.On(player) {
+				return ErrNotOnCourt
+			}
+			continue
+		}
+		count++
+		if count > Size {
+			return ErrTooManyOnCourt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package oncourt
+
+import (
+	"errors"
+	"testing"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestCourtChecks(t *testing.T) {
+	team, other := uuid.New(), uuid.New()
+	p := make([]uuid.UUID, 8)
+	for i := range p {
+		p[i] = uuid.New()
+	}
+	point := func(player uuid.UUID) models.MatchEvent {
+		return models.MatchEvent{Type: models.MatchEventPoint, TeamID: &team, PlayerID: &player, Points: 2}
+	}
+	foul := func(player uuid.UUID) models.MatchEvent {
+		return models.MatchEvent{Type: models.MatchEventFoul, TeamID: &team, PlayerID: &player}
+	}
+	sub := func(entering, leaving uuid.UUID) models.MatchEvent {
+		return models.MatchEvent{Type: models.MatchEventSubstitution, TeamID: &team, PlayerID: &entering, SubstitutedPlayerID: &leaving}
+	}
+
+	// p0-p3 start, p5 comes on for p0, p4 hasn't been seen and p7 fouled
+	// from the bench: four on court
+	events := []models.MatchEvent{point(p[0]), point(p[1]), point(p[2]), point(p[3]), sub(p[5], p[0]), foul(p[7])}
+	voided := sub(p[6], p[1])
+	voided.Voided = true
+	events = append(events, voided)
+	court := Replay(events)
+	if court.Seen(p[7]) {
+		t.Errorf("a foul placed %v on court", p[7])
+	}
+
+	playing := []struct {
+		name    string
+		players []uuid.UUID
+		want    error
+	}{
+		{"starter on court", []uuid.UUID{p[1]}, nil},
+		{"substitute on court", []uuid.UUID{p[5]}, nil},
+		{"voided substitution keeps the starter on", []uuid.UUID{p[1]}, nil},
+		{"substituted out", []uuid.UUID{p[0]}, ErrNotOnCourt},
+		{"first seen as the fifth", []uuid.UUID{p[4]}, nil},
+		{"first seen with an assister already on court", []uuid.UUID{p[4], p[2]}, nil},
+		{"two first seen", []uuid.UUID{p[4], p[7]}, ErrTooManyOnCourt},
+		{"assister substituted out", []uuid.UUID{p[2], p[0]}, ErrNotOnCourt},
+	}
+	for _, c := range playing {
+		if err := court.CheckPlaying(team, c.players...); !errors.Is(err, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+	if err := court.CheckPlaying(other, p[6]); err != nil {
+		t.Errorf("first player of the other team: %v", err)
+	}
+
+	full := Replay(append(events, point(p[4])))
+	substitutions := []struct {
+		name              string
+		entering, leaving uuid.UUID
+		want              error
+	}{
+		{"bench for court", p[0], p[1], nil},
+		{"entering already on court", p[5], p[1], ErrAlreadyOnCourt},
+		{"leaving already substituted out", p[6], p[0], ErrNotOnCourt},
+		{"unseen starter leaving a full court", p[6], p[7], ErrTooManyOnCourt},
+	}
+	for _, c := range substitutions {
+		if err := full.Check(team, c.entering, c.leaving); !errors.Is(err, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+	if err := full.CheckPlaying(team, p[7]); !errors.Is(err, ErrTooManyOnCourt) {
+		t.Errorf("first seen on a full court: got %v, want %v", err, ErrTooManyOnCourt)
+	}
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *AuditRepository) WithTx(tx *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: tx}
+}
+
+// Create stores a new audit event
+func (r *AuditRepository) Create(event *models.AuditEvent) error {
+	return r.db.Create(event).Error
+}
+
+// List gets a list of audit events with pagination, newest first
+func (r *AuditRepository) List(offset, limit int, filters map[string]interface{}) ([]models.AuditEvent, int64, error) {
+	var events []models.AuditEvent
+	var total int64
+
+	query := r.db.Model(&models.AuditEvent{})
+
+	// Apply filters
+	if actorID, ok := filters["actor_id"]; ok {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if action, ok := filters["action"]; ok {
+		query = query.Where("action = ?", action)
+	}
+	if targetType, ok := filters["target_type"]; ok {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if targetID, ok := filters["target_id"]; ok {
+		query = query.Where("target_id = ?", targetID)
+	}
+	if from, ok := filters["from"]; ok {
+		query = query.Where("created_at >= ?", from)
+	}
+	if to, ok := filters["to"]; ok {
+		query = query.Where("created_at < ?", to)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get events
+	err := query.Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&events).Error
+
+	return events, total, err
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BracketRepository struct {
+	db *gorm.DB
+}
+
+func NewBracketRepository() *BracketRepository {
+	return &BracketRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *BracketRepository) WithTx(tx *gorm.DB) *BracketRepository {
+	return &BracketRepository{db: tx}
+}
+
+// CreateNodes creates the nodes of a bracket
+func (r *BracketRepository) CreateNodes(nodes []*models.BracketNode) error {
+	return r.db.Omit("HomeTeam", "AwayTeam", "Match").Create(nodes).Error
+}
+
+// GetNode gets a node of a tournament's bracket
+func (r *BracketRepository) GetNode(tournamentID, id uuid.UUID) (*models.BracketNode, error) {
+	var node models.BracketNode
+	err := r.db.Where("tournament_id = ? AND id = ?", tournamentID, id).First(&node).Error
+	if err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// GetNodeForUpdate gets a node of a tournament's bracket and locks its row
+// until the transaction ends
+func (r *BracketRepository) GetNodeForUpdate(tournamentID, id uuid.UUID) (*models.BracketNode, error) {
+	var node models.BracketNode
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tournament_id = ? AND id = ?", tournamentID, id).First(&node).Error
+	if err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// GetByMatch gets the node a match was scheduled for
+func (r *BracketRepository) GetByMatch(matchID uuid.UUID) (*models.BracketNode, error) {
+	var node models.BracketNode
+	err := r.db.Where("match_id = ?", matchID).First(&node).Error
+	if err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// Update updates a node
+func (r *BracketRepository) Update(node *models.BracketNode) error {
+	return r.db.Omit("HomeTeam", "AwayTeam", "Match").Save(node).Error
+}
+
+// ListByTournament gets every node of a tournament's bracket with its teams
+// and match
+func (r *BracketRepository) ListByTournament(tournamentID uuid.UUID) ([]models.BracketNode, error) {
+	var nodes []models.BracketNode
+	err := r.db.Preload("HomeTeam").
+		Preload("AwayTeam").
+		Preload("Match").
+		Where("tournament_id = ?", tournamentID).
+		Order("round ASC, position ASC").
+		Find(&nodes).Error
+	return nodes, err
+}
+
+// CountByTournament counts the nodes of a tournament's bracket
+func (r *BracketRepository) CountByTournament(tournamentID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.BracketNode{}).Where("tournament_id = ?", tournamentID).Count(&total).Error
+	return total, err
+}
+
+// CountScheduled counts the nodes of a tournament's bracket with a match
+func (r *BracketRepository) CountScheduled(tournamentID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.BracketNode{}).
+		Where("tournament_id = ? AND match_id IS NOT NULL", tournamentID).
+		Count(&total).Error
+	return total, err
+}
+
+// DeleteByTournament deletes a tournament's bracket
+func (r *BracketRepository) DeleteByTournament(tournamentID uuid.UUID) error {
+	return r.db.Where("tournament_id = ?", tournamentID).Delete(&models.BracketNode{}).Error
+}
+
+// Unlink detaches a deleted or cancelled match from its node
+func (r *BracketRepository) Unlink(matchID uuid.UUID) error {
+	return r.db.Model(&models.BracketNode{}).
+		Where("match_id = ?", matchID).
+		Update("match_id", nil).Error
+}
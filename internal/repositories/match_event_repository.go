@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MatchEventRepository struct {
+	db *gorm.DB
+}
+
+func NewMatchEventRepository() *MatchEventRepository {
+	return &MatchEventRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *MatchEventRepository) WithTx(tx *gorm.DB) *MatchEventRepository {
+	return &MatchEventRepository{db: tx}
+}
+
+// Create creates a match event
+func (r *MatchEventRepository) Create(event *models.MatchEvent) error {
+	return r.db.Create(event).Error
+}
+
+// Update updates a match event
+func (r *MatchEventRepository) Update(event *models.MatchEvent) error {
+	return r.db.Save(event).Error
+}
+
+// GetLastActive gets the latest scoring event of a match that has not been
+// voided
+func (r *MatchEventRepository) GetLastActive(matchID uuid.UUID) (*models.MatchEvent, error) {
+	var event models.MatchEvent
+	err := r.db.Where("match_id = ? AND voided = ? AND type IN ?", matchID, false, models.ScoringEventTypes).
+		Order("sequence DESC").
+		First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ListByMatch gets the events of a match after a sequence number, oldest first
+func (r *MatchEventRepository) ListByMatch(matchID uuid.UUID, after int) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	err := r.db.Where("match_id = ? AND sequence > ?", matchID, after).
+		Order("sequence ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// ListShotsByTournament gets the made and missed shots still in effect from
+// the completed matches of a tournament
+func (r *MatchEventRepository) ListShotsByTournament(tournamentID uuid.UUID) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	err := r.db.Joins("JOIN matches ON matches.id = match_events.match_id").
+		Where("matches.tournament_id = ? AND matches.status = ? AND matches.deleted_at IS NULL", tournamentID, models.MatchStatusCompleted).
+		Where("match_events.type IN ? AND match_events.voided = ?", []models.MatchEventType{models.MatchEventPoint, models.MatchEventMiss}, false).
+		Order("match_events.match_id ASC, match_events.sequence ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// ListByMatches gets the events of several matches, grouped by match and
+// oldest first within each
+func (r *MatchEventRepository) ListByMatches(matchIDs []uuid.UUID) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	if len(matchIDs) == 0 {
+		return events, nil
+	}
+	err := r.db.Where("match_id IN ?", matchIDs).
+		Order("match_id ASC, sequence ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MatchRepository struct {
+	db *gorm.DB
+}
+
+func NewMatchRepository() *MatchRepository {
+	return &MatchRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *MatchRepository) WithTx(tx *gorm.DB) *MatchRepository {
+	return &MatchRepository{db: tx}
+}
+
+// Create creates a new match
+func (r *MatchRepository) Create(match *models.Match) error {
+	return r.db.Omit("Tournament", "HomeTeam", "AwayTeam").Create(match).Error
+}
+
+// GetByID gets a match by ID with both teams
+func (r *MatchRepository) GetByID(id uuid.UUID) (*models.Match, error) {
+	var match models.Match
+	err := r.db.Preload("HomeTeam").Preload("AwayTeam").Where("id = ?", id).First(&match).Error
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// Update updates a match
+func (r *MatchRepository) Update(match *models.Match) error {
+	return r.db.Omit("Tournament", "HomeTeam", "AwayTeam").Save(match).Error
+}
+
+// Delete soft deletes a match
+func (r *MatchRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.Match{}).Error
+}
+
+// List gets a list of matches with pagination, in schedule order
+func (r *MatchRepository) List(offset, limit int, filters map[string]interface{}) ([]models.Match, int64, error) {
+	var matches []models.Match
+	var total int64
+
+	query := applyMatchFilters(r.db.Model(&models.Match{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get matches
+	err := query.Preload("HomeTeam").Preload("AwayTeam").
+		Offset(offset).
+		Limit(limit).
+		Order("scheduled_at ASC").
+		Find(&matches).Error
+
+	return matches, total, err
+}
+
+// ListAll gets every match matching the filters, in schedule order
+func (r *MatchRepository) ListAll(filters map[string]interface{}) ([]models.Match, error) {
+	var matches []models.Match
+	err := applyMatchFilters(r.db.Model(&models.Match{}), filters).
+		Order("scheduled_at ASC").
+		Find(&matches).Error
+	return matches, err
+}
+
+// Count counts matches matching the filters
+func (r *MatchRepository) Count(filters map[string]interface{}) (int64, error) {
+	var total int64
+	err := applyMatchFilters(r.db.Model(&models.Match{}), filters).Count(&total).Error
+	return total, err
+}
+
+// CountActiveByTournament counts the matches of a tournament that are not cancelled
+func (r *MatchRepository) CountActiveByTournament(tournamentID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Match{}).
+		Where("tournament_id = ? AND status <> ?", tournamentID, models.MatchStatusCancelled).
+		Count(&total).Error
+	return total, err
+}
+
+// ListCompletedByTournament gets every completed match of a tournament
+func (r *MatchRepository) ListCompletedByTournament(tournamentID uuid.UUID) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.db.Where("tournament_id = ? AND status = ?", tournamentID, models.MatchStatusCompleted).
+		Order("scheduled_at ASC").
+		Find(&matches).Error
+	return matches, err
+}
+
+// FindConflicts gets the scheduled or live matches other than excludeID that
+// start within window of at and either share the venue or involve one of the
+// teams
+func (r *MatchRepository) FindConflicts(at time.Time, window time.Duration, venue string, teamIDs []uuid.UUID, excludeID uuid.UUID) ([]models.Match, error) {
+	var matches []models.Match
+	sameSlot := r.db.Session(&gorm.Session{NewDB: true}).
+		Where("LOWER(venue) = LOWER(?)", venue).
+		Or("home_team_id IN ?", teamIDs).
+		Or("away_team_id IN ?", teamIDs)
+	err := r.db.
+		Where("id <> ?", excludeID).
+		Where("status IN ?", []models.MatchStatus{models.MatchStatusScheduled, models.MatchStatusLive}).
+		Where("scheduled_at > ? AND scheduled_at < ?", at.Add(-window), at.Add(window)).
+		Where(sameSlot).
+		Order("scheduled_at ASC").
+		Find(&matches).Error
+	return matches, err
+}
+
+// applyMatchFilters applies the listing filters shared by List and Count
+func applyMatchFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if tournamentID, ok := filters["tournament_id"]; ok {
+		query = query.Where("tournament_id = ?", tournamentID)
+	}
+	if teamID, ok := filters["team_id"]; ok {
+		query = query.Where("(home_team_id = ? OR away_team_id = ?)", teamID, teamID)
+	}
+	if status, ok := filters["status"]; ok {
+		query = query.Where("status = ?", status)
+	}
+	if venue, ok := filters["venue"]; ok {
+		query = query.Where("venue LIKE ? ESCAPE '!'", "%"+escapeLike(venue.(string))+"%")
+	}
+	if from, ok := filters["from"]; ok {
+		query = query.Where("scheduled_at >= ?", from)
+	}
+	if to, ok := filters["to"]; ok {
+		query = query.Where("scheduled_at < ?", to)
+	}
+	return query
+}
+
+// GetForUpdate gets a match and locks its row until the transaction ends
+func (r *MatchRepository) GetForUpdate(id uuid.UUID) (*models.Match, error) {
+	var match models.Match
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&match).Error
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// LockBooking locks the venue and the teams of a booking until the
+// transaction ends, so concurrent bookings sharing any of them are checked
+// for conflicts one after another. The venue is locked first and the teams
+// in ID order, the same for every booking, so bookings can't deadlock.
+func (r *MatchRepository) LockBooking(venue string, teamIDs []uuid.UUID) error {
+	lock := models.VenueLock{Venue: strings.ToLower(strings.TrimSpace(venue))}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error; err != nil {
+		return err
+	}
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("venue = ?", lock.Venue).First(&lock).Error; err != nil {
+		return err
+	}
+	var teams []models.Team
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", teamIDs).
+		Order("id ASC").
+		Find(&teams).Error
+}
+
+// AddScorekeeper allows a user to record the events of a match
+func (r *MatchRepository) AddScorekeeper(scorekeeper *models.MatchScorekeeper) error {
+	return r.db.Omit("User").Create(scorekeeper).Error
+}
+
+// GetScorekeeper gets the scorekeeper assignment of a user for a match
+func (r *MatchRepository) GetScorekeeper(matchID, userID uuid.UUID) (*models.MatchScorekeeper, error) {
+	var scorekeeper models.MatchScorekeeper
+	err := r.db.Where("match_id = ? AND user_id = ?", matchID, userID).First(&scorekeeper).Error
+	if err != nil {
+		return nil, err
+	}
+	return &scorekeeper, nil
+}
+
+// RemoveScorekeeper removes a scorekeeper assignment
+func (r *MatchRepository) RemoveScorekeeper(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.MatchScorekeeper{}).Error
+}
+
+// ListScorekeepers gets the scorekeepers of a match with their users
+func (r *MatchRepository) ListScorekeepers(matchID uuid.UUID) ([]models.MatchScorekeeper, error) {
+	var scorekeepers []models.MatchScorekeeper
+	err := r.db.Preload("User").
+		Where("match_id = ?", matchID).
+		Order("created_at ASC").
+		Find(&scorekeepers).Error
+	return scorekeepers, err
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository() *OrganizationRepository {
+	return &OrganizationRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *OrganizationRepository) WithTx(tx *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: tx}
+}
+
+// Create creates a new organization
+func (r *OrganizationRepository) Create(org *models.Organization) error {
+	return r.db.Create(org).Error
+}
+
+// GetByID gets an organization by ID
+func (r *OrganizationRepository) GetByID(id uuid.UUID) (*models.Organization, error) {
+	var org models.Organization
+	err := r.db.Preload("AdminUser").Where("id = ?", id).First(&org).Error
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// Update updates an organization
+func (r *OrganizationRepository) Update(org *models.Organization) error {
+	return r.db.Save(org).Error
+}
+
+// Delete soft deletes an organization
+func (r *OrganizationRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.Organization{}).Error
+}
+
+// List gets a list of organizations with pagination
+func (r *OrganizationRepository) List(offset, limit int, filters map[string]interface{}) ([]models.Organization, int64, error) {
+	var orgs []models.Organization
+	var total int64
+
+	query := applyOrganizationFilters(r.db.Model(&models.Organization{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get organizations
+	err := query.Preload("AdminUser").
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&orgs).Error
+
+	return orgs, total, err
+}
+
+// Count counts organizations matching the filters
+func (r *OrganizationRepository) Count(filters map[string]interface{}) (int64, error) {
+	var total int64
+	err := applyOrganizationFilters(r.db.Model(&models.Organization{}), filters).Count(&total).Error
+	return total, err
+}
+
+// Each streams organizations matching the filters in batches, calling fn for every organization
+func (r *OrganizationRepository) Each(batchSize int, filters map[string]interface{}, fn func(*models.Organization) error) error {
+	var orgs []models.Organization
+	result := applyOrganizationFilters(r.db.Model(&models.Organization{}), filters).
+		FindInBatches(&orgs, batchSize, func(tx *gorm.DB, batch int) error {
+			for i := range orgs {
+				if err := fn(&orgs[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	return result.Error
+}
+
+// applyOrganizationFilters applies the listing filters shared by List and Each
+func applyOrganizationFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if status, ok := filters["status"]; ok {
+		query = query.Where("status = ?", status)
+	}
+	return query
+}
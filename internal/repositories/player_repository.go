@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PlayerRepository struct {
+	db *gorm.DB
+}
+
+func NewPlayerRepository() *PlayerRepository {
+	return &PlayerRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *PlayerRepository) WithTx(tx *gorm.DB) *PlayerRepository {
+	return &PlayerRepository{db: tx}
+}
+
+// Create creates a new player
+func (r *PlayerRepository) Create(player *models.Player) error {
+	return r.db.Create(player).Error
+}
+
+// GetByID gets a player by ID
+func (r *PlayerRepository) GetByID(id uuid.UUID) (*models.Player, error) {
+	var player models.Player
+	err := r.db.Preload("Team").Where("id = ?", id).First(&player).Error
+	if err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+// ListByIDs gets the players with the given IDs, deleted ones included
+func (r *PlayerRepository) ListByIDs(ids []uuid.UUID) ([]models.Player, error) {
+	var players []models.Player
+	if len(ids) == 0 {
+		return players, nil
+	}
+	err := r.db.Unscoped().Where("id IN ?", ids).Find(&players).Error
+	return players, err
+}
+
+// GetByUserID gets the player linked to a user account
+func (r *PlayerRepository) GetByUserID(userID uuid.UUID) (*models.Player, error) {
+	var player models.Player
+	err := r.db.Preload("Team").Where("user_id = ?", userID).First(&player).Error
+	if err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+// JerseyTaken checks if an active player other than excludeID wears the number on the team
+func (r *PlayerRepository) JerseyTaken(teamID uuid.UUID, number int, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Player{}).
+		Where("team_id = ? AND jersey_number = ? AND status = ? AND id <> ?",
+			teamID, number, models.PlayerStatusActive, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Update updates a player
+func (r *PlayerRepository) Update(player *models.Player) error {
+	return r.db.Save(player).Error
+}
+
+// Delete soft deletes a player, releasing their jersey number and their
+// linked user
+func (r *PlayerRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Player{}).Where("id = ?", id).
+			UpdateColumns(map[string]interface{}{"active_team_id": nil, "user_id": nil}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.Player{}).Error
+	})
+}
+
+// List gets a list of players with pagination
+func (r *PlayerRepository) List(offset, limit int, filters map[string]interface{}) ([]models.Player, int64, error) {
+	var players []models.Player
+	var total int64
+
+	query := applyPlayerFilters(r.db.Model(&models.Player{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get players
+	err := query.Preload("Team").
+		Offset(offset).
+		Limit(limit).
+		Order("jersey_number ASC").
+		Find(&players).Error
+
+	return players, total, err
+}
+
+// Count counts players matching the filters
+func (r *PlayerRepository) Count(filters map[string]interface{}) (int64, error) {
+	var total int64
+	err := applyPlayerFilters(r.db.Model(&models.Player{}), filters).Count(&total).Error
+	return total, err
+}
+
+// applyPlayerFilters applies the listing filters shared by List and Count
+func applyPlayerFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if teamID, ok := filters["team_id"]; ok {
+		query = query.Where("team_id = ?", teamID)
+	}
+	if orgID, ok := filters["organization_id"]; ok {
+		query = query.Where("team_id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Model(&models.Team{}).Select("id").Where("organization_id = ?", orgID))
+	}
+	if position, ok := filters["position"]; ok {
+		query = query.Where("position = ?", position)
+	}
+	if status, ok := filters["status"]; ok {
+		query = query.Where("status = ?", status)
+	}
+	return query
+}
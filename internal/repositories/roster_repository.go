@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"time"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RosterRepository struct {
+	db *gorm.DB
+}
+
+func NewRosterRepository() *RosterRepository {
+	return &RosterRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *RosterRepository) WithTx(tx *gorm.DB) *RosterRepository {
+	return &RosterRepository{db: tx}
+}
+
+// Create creates a roster membership
+func (r *RosterRepository) Create(membership *models.RosterMembership) error {
+	return r.db.Create(membership).Error
+}
+
+// GetCurrent gets the open stint of a player
+func (r *RosterRepository) GetCurrent(playerID uuid.UUID) (*models.RosterMembership, error) {
+	var membership models.RosterMembership
+	err := r.db.Where("player_id = ? AND end_date IS NULL", playerID).
+		Order("start_date DESC").
+		First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// Update updates a roster membership
+func (r *RosterRepository) Update(membership *models.RosterMembership) error {
+	return r.db.Save(membership).Error
+}
+
+// Close ends a stint on the given date
+func (r *RosterRepository) Close(id uuid.UUID, endDate time.Time) error {
+	return r.db.Model(&models.RosterMembership{}).
+		Where("id = ?", id).
+		Update("end_date", endDate).Error
+}
+
+// ListByPlayer gets every stint of a player, most recent first
+func (r *RosterRepository) ListByPlayer(playerID uuid.UUID) ([]models.RosterMembership, error) {
+	var memberships []models.RosterMembership
+	err := r.db.Preload("Team", unscoped).
+		Where("player_id = ?", playerID).
+		Order("start_date DESC, created_at DESC").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// ListByTeamAsOf gets the stints of a team that cover the given date
+func (r *RosterRepository) ListByTeamAsOf(teamID uuid.UUID, date time.Time, offset, limit int) ([]models.RosterMembership, int64, error) {
+	var memberships []models.RosterMembership
+	var total int64
+
+	query := r.db.Model(&models.RosterMembership{}).
+		Where("team_id = ? AND start_date <= ? AND (end_date IS NULL OR end_date > ?)", teamID, date, date)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get memberships
+	err := query.Preload("Player", unscoped).
+		Offset(offset).
+		Limit(limit).
+		Order("jersey_number ASC").
+		Find(&memberships).Error
+
+	return memberships, total, err
+}
+
+// ListByPlayersAsOf gets the stints of the players that cover the given date
+func (r *RosterRepository) ListByPlayersAsOf(playerIDs []uuid.UUID, date time.Time) ([]models.RosterMembership, error) {
+	var memberships []models.RosterMembership
+	if len(playerIDs) == 0 {
+		return memberships, nil
+	}
+	err := r.db.Where("player_id IN ? AND start_date <= ? AND (end_date IS NULL OR end_date > ?)", playerIDs, date, date).
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// unscoped preloads soft deleted rows so history keeps pointing at former
+// players and teams
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StandingRepository struct {
+	db *gorm.DB
+}
+
+func NewStandingRepository() *StandingRepository {
+	return &StandingRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *StandingRepository) WithTx(tx *gorm.DB) *StandingRepository {
+	return &StandingRepository{db: tx}
+}
+
+// ReplaceTournament replaces the standings of a tournament
+func (r *StandingRepository) ReplaceTournament(tournamentID uuid.UUID, standings []models.Standing) error {
+	if err := r.db.Where("tournament_id = ?", tournamentID).Delete(&models.Standing{}).Error; err != nil {
+		return err
+	}
+	if len(standings) == 0 {
+		return nil
+	}
+	return r.db.Omit("Team").Create(&standings).Error
+}
+
+// ListByTournament gets the standings of a tournament with the teams, by
+// group then rank
+func (r *StandingRepository) ListByTournament(tournamentID uuid.UUID) ([]models.Standing, error) {
+	var standings []models.Standing
+	err := r.db.Preload("Team").
+		Where("tournament_id = ?", tournamentID).
+		Order("group_name ASC, `rank` ASC").
+		Find(&standings).Error
+	return standings, err
+}
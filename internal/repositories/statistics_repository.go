@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StatisticsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatisticsRepository() *StatisticsRepository {
+	return &StatisticsRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *StatisticsRepository) WithTx(tx *gorm.DB) *StatisticsRepository {
+	return &StatisticsRepository{db: tx}
+}
+
+// ReplaceMatch replaces the player and team statistics of a match
+func (r *StatisticsRepository) ReplaceMatch(matchID uuid.UUID, players []models.PlayerStatistics, teams []models.TeamStatistics) error {
+	if err := r.db.Where("match_id = ?", matchID).Delete(&models.PlayerStatistics{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("match_id = ?", matchID).Delete(&models.TeamStatistics{}).Error; err != nil {
+		return err
+	}
+	if len(players) > 0 {
+		if err := r.db.Omit("Player").Create(&players).Error; err != nil {
+			return err
+		}
+	}
+	if len(teams) > 0 {
+		if err := r.db.Create(&teams).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveLines creates or replaces player and team statistics rows of a match.
+// Lines are matched on their match and player or team rather than their ID,
+// which a line rebuilt from the box score state doesn't carry.
+func (r *StatisticsRepository) SaveLines(players []models.PlayerStatistics, teams []models.TeamStatistics) error {
+	if len(players) > 0 {
+		conflict := clause.OnConflict{Columns: []clause.Column{{Name: "match_id"}, {Name: "player_id"}}, UpdateAll: true}
+		if err := r.db.Omit("Player").Clauses(conflict).Create(&players).Error; err != nil {
+			return err
+		}
+	}
+	if len(teams) > 0 {
+		conflict := clause.OnConflict{Columns: []clause.Column{{Name: "match_id"}, {Name: "team_id"}}, UpdateAll: true}
+		if err := r.db.Clauses(conflict).Create(&teams).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBoxScoreState gets the stored box score state of a match
+func (r *StatisticsRepository) GetBoxScoreState(matchID uuid.UUID) (*models.BoxScoreState, error) {
+	var state models.BoxScoreState
+	if err := r.db.Where("match_id = ?", matchID).First(&state).Error; err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SaveBoxScoreState creates or replaces the box score state of a match
+func (r *StatisticsRepository) SaveBoxScoreState(state *models.BoxScoreState) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(state).Error
+}
+
+// ListPlayersByMatch gets the player statistics of a match with the players
+func (r *StatisticsRepository) ListPlayersByMatch(matchID uuid.UUID) ([]models.PlayerStatistics, error) {
+	var stats []models.PlayerStatistics
+	err := r.db.Preload("Player", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("match_id = ?", matchID).Find(&stats).Error
+	return stats, err
+}
+
+// ListPlayerLinesByMatch gets the player statistics of a match without the
+// players
+func (r *StatisticsRepository) ListPlayerLinesByMatch(matchID uuid.UUID) ([]models.PlayerStatistics, error) {
+	var stats []models.PlayerStatistics
+	err := r.db.Where("match_id = ?", matchID).Find(&stats).Error
+	return stats, err
+}
+
+// ListTeamsByMatch gets the team statistics of a match
+func (r *StatisticsRepository) ListTeamsByMatch(matchID uuid.UUID) ([]models.TeamStatistics, error) {
+	var stats []models.TeamStatistics
+	err := r.db.Where("match_id = ?", matchID).Find(&stats).Error
+	return stats, err
+}
+
+// GetPlayerMatch gets a player's statistics for one match
+func (r *StatisticsRepository) GetPlayerMatch(playerID, matchID uuid.UUID) (*models.PlayerStatistics, error) {
+	var stats models.PlayerStatistics
+	err := r.db.Where("player_id = ? AND match_id = ?", playerID, matchID).First(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetTeamMatch gets a team's statistics for one match
+func (r *StatisticsRepository) GetTeamMatch(teamID, matchID uuid.UUID) (*models.TeamStatistics, error) {
+	var stats models.TeamStatistics
+	err := r.db.Where("team_id = ? AND match_id = ?", teamID, matchID).First(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// PlayedForSince checks if a player has a line for a team in a match played
+// at or after the given time
+func (r *StatisticsRepository) PlayedForSince(playerID, teamID uuid.UUID, from time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.PlayerStatistics{}).
+		Where("player_id = ? AND team_id = ? AND played_at >= ?", playerID, teamID, from).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// statColumns are the counting stat columns summed into aggregates
+var statColumns = []string{
+	"points", "rebounds", "offensive_rebounds", "defensive_rebounds", "assists", "steals", "blocks",
+	"turnovers", "fouls", "seconds_played", "plus_minus", "field_goals_made", "field_goals_attempted",
+	"three_pointers_made", "three_pointers_attempted", "free_throws_made", "free_throws_attempted",
+}
+
+// teamUsageColumns are the team columns summed over a player's games for the
+// player's usage rate
+var teamUsageColumns = []string{"field_goals_attempted", "free_throws_attempted", "turnovers", "seconds_played"}
+
+// Sum totals the final statistics rows of a player or team matching the
+// filters. With lastN above zero only the latest lastN games count. A
+// player's sum also totals the player's team over the same games.
+func (r *StatisticsRepository) Sum(subjectType string, subjectID uuid.UUID, filters map[string]interface{}, lastN int) (*models.StatAggregate, error) {
+	table, column := "player_statistics", "player_id"
+	columns := statColumns[:len(statColumns):len(statColumns)]
+	if subjectType == models.StatSubjectTeam {
+		table, column = "team_statistics", "team_id"
+		columns = append(columns, "opponent_points")
+	}
+
+	games := r.db.Table(table)
+	if subjectType == models.StatSubjectPlayer {
+		selects := []string{"player_statistics.*"}
+		for _, c := range teamUsageColumns {
+			selects = append(selects, "team."+c+" AS team_"+c)
+			columns = append(columns, "team_"+c)
+		}
+		games = games.Select(strings.Join(selects, ", ")).
+			Joins("LEFT JOIN team_statistics team ON team.match_id = player_statistics.match_id AND team.team_id = player_statistics.team_id")
+	}
+	games = games.Where(table+"."+column+" = ? AND "+table+".final = ?", subjectID, true)
+	games = applyStatFilters(games, table, filters).Order(table + ".played_at DESC")
+	if lastN > 0 {
+		games = games.Limit(lastN)
+	}
+
+	sums := []string{"COUNT(*) AS games", "COALESCE(SUM(won), 0) AS wins"}
+	for _, c := range columns {
+		sums = append(sums, "COALESCE(SUM("+c+"), 0) AS "+c)
+	}
+	var agg models.StatAggregate
+	err := r.db.Table("(?) AS games", games).Select(strings.Join(sums, ", ")).Scan(&agg).Error
+	if err != nil {
+		return nil, err
+	}
+	// Scanning resets the fields the query doesn't select
+	agg.SubjectType, agg.SubjectID = subjectType, subjectID
+	return &agg, nil
+}
+
+// SumBySubject totals the final statistics rows matching the filters for
+// every player or team that has any, one aggregate per subject. An
+// organization_id filter selects the rows of the organization's teams.
+func (r *StatisticsRepository) SumBySubject(subjectType string, filters map[string]interface{}) ([]models.StatAggregate, error) {
+	table, column := "player_statistics", "player_id"
+	columns := statColumns
+	if subjectType == models.StatSubjectTeam {
+		table, column = "team_statistics", "team_id"
+		columns = append(columns[:len(columns):len(columns)], "opponent_points")
+	}
+
+	sums := []string{table + "." + column + " AS subject_id", "COUNT(*) AS games", "COALESCE(SUM(" + table + ".won), 0) AS wins"}
+	for _, c := range columns {
+		sums = append(sums, "COALESCE(SUM("+table+"."+c+"), 0) AS "+c)
+	}
+	query := r.db.Table(table).Select(strings.Join(sums, ", ")).Where(table+".final = ?", true)
+	if orgID, ok := filters["organization_id"]; ok {
+		query = query.Joins("JOIN teams ON teams.id = "+table+".team_id").Where("teams.organization_id = ?", orgID)
+	}
+	query = applyStatFilters(query, table, filters).Group(table + "." + column)
+
+	var aggs []models.StatAggregate
+	if err := query.Scan(&aggs).Error; err != nil {
+		return nil, err
+	}
+	for i := range aggs {
+		aggs[i].SubjectType = subjectType
+	}
+	return aggs, nil
+}
+
+// GetAggregate gets the stored aggregate of a player or team for a scope
+func (r *StatisticsRepository) GetAggregate(subjectType string, subjectID uuid.UUID, scope, key string) (*models.StatAggregate, error) {
+	var agg models.StatAggregate
+	err := r.db.Where("subject_type = ? AND subject_id = ? AND scope = ? AND scope_key = ?", subjectType, subjectID, scope, key).
+		First(&agg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &agg, nil
+}
+
+// SaveAggregate creates or replaces the aggregate of a player or team for a
+// scope, matched on the subject and scope as a rebuilt aggregate carries no
+// ID
+func (r *StatisticsRepository) SaveAggregate(agg *models.StatAggregate) error {
+	conflict := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subject_type"}, {Name: "subject_id"}, {Name: "scope"}, {Name: "scope_key"}},
+		UpdateAll: true,
+	}
+	return r.db.Clauses(conflict).Create(agg).Error
+}
+
+func applyStatFilters(query *gorm.DB, table string, filters map[string]interface{}) *gorm.DB {
+	for _, key := range []string{"tournament_id", "season", "home", "opponent_team_id"} {
+		if value, ok := filters[key]; ok {
+			query = query.Where(table+"."+key+" = ?", value)
+		}
+	}
+	return query
+}
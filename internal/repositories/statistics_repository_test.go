@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// statsRepo opens a test database with the statistics tables
+func statsRepo(t *testing.T) *StatisticsRepository {
+	t.Helper()
+	db := testDB(t)
+	if err := db.AutoMigrate(&models.Team{}, &models.Player{}, &models.PlayerStatistics{}, &models.TeamStatistics{}, &models.StatAggregate{}); err != nil {
+		t.Fatal(err)
+	}
+	return &StatisticsRepository{db: db}
+}
+
+func TestSaveLines(t *testing.T) {
+	repo := statsRepo(t)
+	match, team, player := uuid.New(), uuid.New(), uuid.New()
+	save := func(points int) {
+		t.Helper()
+		// Lines carried on from the box score state come without their IDs
+		players := []models.PlayerStatistics{{MatchID: match, PlayerID: player, TeamID: team,
+			StatLine: models.StatLine{Points: points}}}
+		teams := []models.TeamStatistics{{MatchID: match, TeamID: team, StatLine: models.StatLine{Points: points}}}
+		if err := repo.SaveLines(players, teams); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save(2)
+	save(5)
+
+	players, err := repo.ListPlayerLinesByMatch(match)
+	if err != nil {
+		t.Fatal(err)
+	}
+	teams, err := repo.ListTeamsByMatch(match)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 1 || players[0].Points != 5 {
+		t.Errorf("got player lines %+v, want one with 5 points", players)
+	}
+	if len(teams) != 1 || teams[0].Points != 5 {
+		t.Errorf("got team lines %+v, want one with 5 points", teams)
+	}
+}
+
+// statGame is one match of a player and the player's team
+type statGame struct {
+	daysAgo  int
+	opponent uuid.UUID
+	home     bool
+	final    bool
+	won      bool
+	points   int
+}
+
+func TestSum(t *testing.T) {
+	repo := statsRepo(t)
+	player, team, tournament := uuid.New(), uuid.New(), uuid.New()
+	rivals, others := uuid.New(), uuid.New()
+	now := time.Date(2026, time.March, 1, 19, 0, 0, 0, time.UTC)
+	games := []statGame{
+		{1, rivals, true, false, false, 40}, // Still live
+		{2, rivals, true, true, true, 10},
+		{3, others, false, true, false, 8},
+		{4, rivals, false, true, true, 6},
+		{400, others, true, true, true, 4}, // Last season
+	}
+	for _, g := range games {
+		match := uuid.New()
+		context := models.GameContext{OpponentTeamID: g.opponent, Home: g.home, Final: g.final, Won: g.won,
+			PlayedAt: now.AddDate(0, 0, -g.daysAgo), Season: now.AddDate(0, 0, -g.daysAgo).Year()}
+		if g.daysAgo < 100 {
+			context.TournamentID = &tournament
+		}
+		players := []models.PlayerStatistics{{MatchID: match, PlayerID: player, TeamID: team, GameContext: context,
+			StatLine: models.StatLine{Points: g.points, FieldGoalsAttempted: 3}}}
+		teams := []models.TeamStatistics{{MatchID: match, TeamID: team, GameContext: context,
+			StatLine: models.StatLine{Points: 2 * g.points, FieldGoalsAttempted: 10}, OpponentPoints: g.points}}
+		if err := repo.ReplaceMatch(match, players, teams); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name    string
+		subject string
+		filters map[string]interface{}
+		lastN   int
+		games   int
+		wins    int
+		points  int
+	}{
+		{"career, final games only", models.StatSubjectPlayer, nil, 0, 4, 3, 28},
+		{"season", models.StatSubjectPlayer, map[string]interface{}{"season": 2026}, 0, 3, 2, 24},
+		{"tournament", models.StatSubjectPlayer, map[string]interface{}{"tournament_id": tournament}, 0, 3, 2, 24},
+		{"home", models.StatSubjectPlayer, map[string]interface{}{"home": true}, 0, 2, 2, 14},
+		{"away", models.StatSubjectPlayer, map[string]interface{}{"home": false}, 0, 2, 1, 14},
+		{"opponent", models.StatSubjectPlayer, map[string]interface{}{"opponent_team_id": rivals}, 0, 2, 2, 16},
+		{"last two", models.StatSubjectPlayer, nil, 2, 2, 1, 18},
+		{"last two at home", models.StatSubjectPlayer, map[string]interface{}{"home": true}, 2, 2, 2, 14},
+		{"last ten", models.StatSubjectPlayer, nil, 10, 4, 3, 28},
+		{"team career", models.StatSubjectTeam, nil, 0, 4, 3, 56},
+		{"team last one", models.StatSubjectTeam, nil, 1, 1, 1, 20},
+	}
+	for _, c := range cases {
+		subject := player
+		if c.subject == models.StatSubjectTeam {
+			subject = team
+		}
+		agg, err := repo.Sum(c.subject, subject, c.filters, c.lastN)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if agg.SubjectType != c.subject || agg.SubjectID != subject {
+			t.Errorf("%s: got subject %s %s, want %s %s", c.name, agg.SubjectType, agg.SubjectID, c.subject, subject)
+		}
+		if agg.Games != c.games || agg.Wins != c.wins || agg.Points != c.points {
+			t.Errorf("%s: got %d games, %d wins, %d points, want %d, %d, %d",
+				c.name, agg.Games, agg.Wins, agg.Points, c.games, c.wins, c.points)
+		}
+		switch c.subject {
+		case models.StatSubjectPlayer:
+			// The player's team over the same games
+			if agg.TeamFieldGoalsAttempted != 10*c.games {
+				t.Errorf("%s: got %d team attempts, want %d", c.name, agg.TeamFieldGoalsAttempted, 10*c.games)
+			}
+		case models.StatSubjectTeam:
+			if agg.OpponentPoints != c.points/2 {
+				t.Errorf("%s: got %d opponent points, want %d", c.name, agg.OpponentPoints, c.points/2)
+			}
+		}
+	}
+}
+
+func TestSumBySubject(t *testing.T) {
+	repo := statsRepo(t)
+	org := uuid.New()
+	ours := models.Team{ID: uuid.New(), OrganizationID: org, Name: "Ours"}
+	theirs := models.Team{ID: uuid.New(), OrganizationID: uuid.New(), Name: "Theirs"}
+	if err := repo.db.Create([]*models.Team{&ours, &theirs}).Error; err != nil {
+		t.Fatal(err)
+	}
+	first, second, rival := uuid.New(), uuid.New(), uuid.New()
+	lines := []struct {
+		player uuid.UUID
+		team   uuid.UUID
+		final  bool
+		points int
+	}{
+		{first, ours.ID, true, 10},
+		{first, ours.ID, true, 5},
+		{first, ours.ID, false, 30},
+		{second, ours.ID, true, 7},
+		{rival, theirs.ID, true, 12},
+	}
+	for _, l := range lines {
+		match := uuid.New()
+		players := []models.PlayerStatistics{{MatchID: match, PlayerID: l.player, TeamID: l.team,
+			GameContext: models.GameContext{Final: l.final}, StatLine: models.StatLine{Points: l.points}}}
+		if err := repo.ReplaceMatch(match, players, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name    string
+		filters map[string]interface{}
+		want    map[uuid.UUID][2]int // Games and points
+	}{
+		{"everyone", map[string]interface{}{}, map[uuid.UUID][2]int{first: {2, 15}, second: {1, 7}, rival: {1, 12}}},
+		{"organization", map[string]interface{}{"organization_id": org}, map[uuid.UUID][2]int{first: {2, 15}, second: {1, 7}}},
+	}
+	for _, c := range cases {
+		aggs, err := repo.SumBySubject(models.StatSubjectPlayer, c.filters)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		got := map[uuid.UUID][2]int{}
+		for _, agg := range aggs {
+			got[agg.SubjectID] = [2]int{agg.Games, agg.Points}
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("%s: got %d players, want %d", c.name, len(got), len(c.want))
+		}
+		for id, want := range c.want {
+			if got[id] != want {
+				t.Errorf("%s: got games and points %v, want %v", c.name, got[id], want)
+			}
+		}
+	}
+}
+
+func TestRefinalizeAggregate(t *testing.T) {
+	repo := statsRepo(t)
+	match, player, team := uuid.New(), uuid.New(), uuid.New()
+	context := models.GameContext{Final: true, Won: true, Season: 2026}
+
+	// A corrected result finalizes the match again: its lines are replaced
+	// and the aggregate rebuilt over them
+	for _, points := range []int{12, 14} {
+		players := []models.PlayerStatistics{{MatchID: match, PlayerID: player, TeamID: team,
+			GameContext: context, StatLine: models.StatLine{Points: points}}}
+		if err := repo.ReplaceMatch(match, players, nil); err != nil {
+			t.Fatal(err)
+		}
+		agg, err := repo.Sum(models.StatSubjectPlayer, player, map[string]interface{}{"season": 2026}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		agg.Scope, agg.ScopeKey = models.StatScopeSeason, "2026"
+		if err := repo.SaveAggregate(agg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	agg, err := repo.GetAggregate(models.StatSubjectPlayer, player, models.StatScopeSeason, "2026")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agg.Games != 1 || agg.Wins != 1 || agg.Points != 14 {
+		t.Errorf("got %d games, %d wins, %d points, want 1, 1 and 14", agg.Games, agg.Wins, agg.Points)
+	}
+	var stored int64
+	if err := repo.db.Model(&models.StatAggregate{}).Count(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored != 1 {
+		t.Errorf("got %d stored aggregates, want 1", stored)
+	}
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TeamRepository struct {
+	db *gorm.DB
+}
+
+func NewTeamRepository() *TeamRepository {
+	return &TeamRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *TeamRepository) WithTx(tx *gorm.DB) *TeamRepository {
+	return &TeamRepository{db: tx}
+}
+
+// Create creates a new team
+func (r *TeamRepository) Create(team *models.Team) error {
+	return r.db.Create(team).Error
+}
+
+// GetByID gets a team by ID
+func (r *TeamRepository) GetByID(id uuid.UUID) (*models.Team, error) {
+	var team models.Team
+	err := r.db.Preload("Organization").Where("id = ?", id).First(&team).Error
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// ListByIDs gets the teams with the given IDs, deleted ones included
+func (r *TeamRepository) ListByIDs(ids []uuid.UUID) ([]models.Team, error) {
+	var teams []models.Team
+	if len(ids) == 0 {
+		return teams, nil
+	}
+	err := r.db.Unscoped().Where("id IN ?", ids).Find(&teams).Error
+	return teams, err
+}
+
+// Update updates a team
+func (r *TeamRepository) Update(team *models.Team) error {
+	return r.db.Save(team).Error
+}
+
+// Delete soft deletes a team
+func (r *TeamRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.Team{}).Error
+}
+
+// List gets a list of teams with pagination
+func (r *TeamRepository) List(offset, limit int, filters map[string]interface{}) ([]models.Team, int64, error) {
+	var teams []models.Team
+	var total int64
+
+	query := applyTeamFilters(r.db.Model(&models.Team{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get teams
+	err := query.Preload("Organization").
+		Offset(offset).
+		Limit(limit).
+		Order("name ASC").
+		Find(&teams).Error
+
+	return teams, total, err
+}
+
+// Count counts teams matching the filters
+func (r *TeamRepository) Count(filters map[string]interface{}) (int64, error) {
+	var total int64
+	err := applyTeamFilters(r.db.Model(&models.Team{}), filters).Count(&total).Error
+	return total, err
+}
+
+// Each streams teams matching the filters in batches, calling fn for every team
+func (r *TeamRepository) Each(batchSize int, filters map[string]interface{}, fn func(*models.Team) error) error {
+	var teams []models.Team
+	result := applyTeamFilters(r.db.Model(&models.Team{}), filters).
+		FindInBatches(&teams, batchSize, func(tx *gorm.DB, batch int) error {
+			for i := range teams {
+				if err := fn(&teams[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	return result.Error
+}
+
+// applyTeamFilters applies the listing filters shared by List, Count and Each
+func applyTeamFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if orgID, ok := filters["organization_id"]; ok {
+		query = query.Where("organization_id = ?", orgID)
+	}
+	if status, ok := filters["status"]; ok {
+		query = query.Where("status = ?", status)
+	}
+	if name, ok := filters["name"]; ok {
+		query = query.Where("name LIKE ? ESCAPE '!'", "%"+escapeLike(name.(string))+"%")
+	}
+	return query
+}
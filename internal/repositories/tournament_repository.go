@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TournamentRepository struct {
+	db *gorm.DB
+}
+
+func NewTournamentRepository() *TournamentRepository {
+	return &TournamentRepository{
+		db: database.DB,
+	}
+}
+
+// WithTx returns a repository bound to the given transaction
+func (r *TournamentRepository) WithTx(tx *gorm.DB) *TournamentRepository {
+	return &TournamentRepository{db: tx}
+}
+
+// Create creates a new tournament
+func (r *TournamentRepository) Create(tournament *models.Tournament) error {
+	return r.db.Create(tournament).Error
+}
+
+// GetByID gets a tournament by ID
+func (r *TournamentRepository) GetByID(id uuid.UUID) (*models.Tournament, error) {
+	var tournament models.Tournament
+	err := r.db.Preload("Organization").Where("id = ?", id).First(&tournament).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+// Update updates a tournament
+func (r *TournamentRepository) Update(tournament *models.Tournament) error {
+	return r.db.Omit("Entries").Save(tournament).Error
+}
+
+// Delete soft deletes a tournament
+func (r *TournamentRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.Tournament{}).Error
+}
+
+// List gets a list of tournaments with pagination
+func (r *TournamentRepository) List(offset, limit int, filters map[string]interface{}) ([]models.Tournament, int64, error) {
+	var tournaments []models.Tournament
+	var total int64
+
+	query := applyTournamentFilters(r.db.Model(&models.Tournament{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get tournaments
+	err := query.Preload("Organization").
+		Offset(offset).
+		Limit(limit).
+		Order("start_date DESC").
+		Find(&tournaments).Error
+
+	return tournaments, total, err
+}
+
+// Count counts tournaments matching the filters
+func (r *TournamentRepository) Count(filters map[string]interface{}) (int64, error) {
+	var total int64
+	err := applyTournamentFilters(r.db.Model(&models.Tournament{}), filters).Count(&total).Error
+	return total, err
+}
+
+// applyTournamentFilters applies the listing filters shared by List and Count
+func applyTournamentFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if orgID, ok := filters["organization_id"]; ok {
+		query = query.Where("organization_id = ?", orgID)
+	}
+	if status, ok := filters["status"]; ok {
+		query = query.Where("status = ?", status)
+	}
+	if name, ok := filters["name"]; ok {
+		query = query.Where("name LIKE ? ESCAPE '!'", "%"+escapeLike(name.(string))+"%")
+	}
+	return query
+}
+
+// AddEntry registers a team in a tournament
+func (r *TournamentRepository) AddEntry(entry *models.TournamentEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// GetEntry gets the entry of a team in a tournament
+func (r *TournamentRepository) GetEntry(tournamentID, teamID uuid.UUID) (*models.TournamentEntry, error) {
+	var entry models.TournamentEntry
+	err := r.db.Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateEntry updates a team's entry in a tournament
+func (r *TournamentRepository) UpdateEntry(entry *models.TournamentEntry) error {
+	return r.db.Omit("Team").Save(entry).Error
+}
+
+// RemoveEntry withdraws a team from a tournament
+func (r *TournamentRepository) RemoveEntry(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.TournamentEntry{}).Error
+}
+
+// ListEntries gets the teams registered in a tournament, by seed then registration order
+func (r *TournamentRepository) ListEntries(tournamentID uuid.UUID) ([]models.TournamentEntry, error) {
+	var entries []models.TournamentEntry
+	err := r.db.Preload("Team").
+		Where("tournament_id = ?", tournamentID).
+		Order("seed IS NULL, seed ASC, created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// CountEntries counts the teams registered in a tournament
+func (r *TournamentRepository) CountEntries(tournamentID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.TournamentEntry{}).Where("tournament_id = ?", tournamentID).Count(&total).Error
+	return total, err
+}
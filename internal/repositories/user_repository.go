@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"strings"
 	"time"
 
 	"echo-golang/internal/database"
@@ -14,12 +15,23 @@ type UserRepository struct {
 	db *gorm.DB
 }
 
+// UserSearchHit is a user matched by Search together with its relevance score
+type UserSearchHit struct {
+	User  models.User
+	Score float64
+}
+
 func NewUserRepository() *UserRepository {
 	return &UserRepository{
 		db: database.DB,
 	}
 }
 
+// WithTx returns a repository bound to the given transaction
+func (r *UserRepository) WithTx(tx *gorm.DB) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
 // Create creates a new user
 func (r *UserRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
@@ -61,18 +73,7 @@ func (r *UserRepository) List(offset, limit int, filters map[string]interface{})
 	var users []models.User
 	var total int64
 
-	query := r.db.Model(&models.User{})
-
-	// Apply filters
-	if role, ok := filters["role"]; ok {
-		query = query.Where("role = ?", role)
-	}
-	if status, ok := filters["status"]; ok {
-		query = query.Where("status = ?", status)
-	}
-	if orgID, ok := filters["organization_id"]; ok {
-		query = query.Where("organization_id = ?", orgID)
-	}
+	query := applyUserFilters(r.db.Model(&models.User{}), filters)
 
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
@@ -89,8 +90,181 @@ func (r *UserRepository) List(offset, limit int, filters map[string]interface{})
 	return users, total, err
 }
 
+// Count counts users matching the filters
+func (r *UserRepository) Count(filters map[string]interface{}) (int64, error) {
+	var total int64
+	err := applyUserFilters(r.db.Model(&models.User{}), filters).Count(&total).Error
+	return total, err
+}
+
+// Each streams users matching the filters in batches, calling fn for every user
+func (r *UserRepository) Each(batchSize int, filters map[string]interface{}, fn func(*models.User) error) error {
+	var users []models.User
+	result := applyUserFilters(r.db.Model(&models.User{}), filters).
+		FindInBatches(&users, batchSize, func(tx *gorm.DB, batch int) error {
+			for i := range users {
+				if err := fn(&users[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	return result.Error
+}
+
+// Search finds users whose name, email or phone match term, most relevant first.
+// On MySQL the FULLTEXT index on (full_name, email) contributes to matching and
+// scoring; other databases use the portable LIKE based scoring only.
+func (r *UserRepository) Search(term string, offset, limit int, filters map[string]interface{}) ([]UserSearchHit, int64, error) {
+	var total int64
+
+	where, whereArgs, score, scoreArgs := userSearchClauses(term, userSearchMode(r.db))
+	query := applyUserFilters(r.db.Model(&models.User{}), filters).Where(where, whereArgs...)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Rank matching IDs, then load the users themselves
+	var ranked []struct {
+		ID          uuid.UUID
+		SearchScore float64
+	}
+	err := query.Select("id, ("+score+") AS search_score", scoreArgs...).
+		Order("search_score DESC").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(ranked) == 0 {
+		return []UserSearchHit{}, total, nil
+	}
+
+	ids := make([]uuid.UUID, len(ranked))
+	for i, hit := range ranked {
+		ids[i] = hit.ID
+	}
+	var users []models.User
+	if err := r.db.Preload("Organization").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uuid.UUID]models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	hits := make([]UserSearchHit, 0, len(ranked))
+	for _, hit := range ranked {
+		if user, ok := byID[hit.ID]; ok {
+			hits = append(hits, UserSearchHit{User: user, Score: hit.SearchScore})
+		}
+	}
+	return hits, total, nil
+}
+
 // Delete soft deletes a user
 func (r *UserRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.User{}, id).Error
+	return r.db.Where("id = ?", id).Delete(&models.User{}).Error
+}
+
+// applyUserFilters applies the listing filters shared by List and Each
+func applyUserFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if role, ok := filters["role"]; ok {
+		query = query.Where("role = ?", role)
+	}
+	if status, ok := filters["status"]; ok {
+		query = query.Where("status = ?", status)
+	}
+	if orgID, ok := filters["organization_id"]; ok {
+		query = query.Where("organization_id = ?", orgID)
+	}
+	return query
 }
 
+// searchMode is how Search matches users on a database
+type searchMode int
+
+const (
+	searchLike     searchMode = iota // LIKE based matching and scoring only
+	searchFulltext                   // LIKE based, plus the MySQL FULLTEXT index
+)
+
+// userSearchMode picks the search mode of a database: MySQL has the FULLTEXT
+// index, and any other database, such as the SQLite the tests run on, gets
+// the portable LIKE based mode
+func userSearchMode(db *gorm.DB) searchMode {
+	if db.Dialector.Name() == "mysql" {
+		return searchFulltext
+	}
+	return searchLike
+}
+
+// userSearchClauses builds the WHERE condition and relevance score expression for Search
+func userSearchClauses(term string, mode searchMode) (string, []interface{}, string, []interface{}) {
+	lower := escapeLike(strings.ToLower(strings.TrimSpace(term)))
+	contains := "%" + lower + "%"
+	prefix := lower + "%"
+	wordPrefix := "% " + lower + "%"
+
+	conditions := []string{
+		"LOWER(full_name) LIKE ? ESCAPE '!'",
+		"LOWER(email) LIKE ? ESCAPE '!'",
+		"phone LIKE ? ESCAPE '!'",
+	}
+	whereArgs := []interface{}{contains, contains, contains}
+
+	// Multi-word queries also match names containing every word in any order
+	words := strings.Fields(lower)
+	if len(words) > 1 {
+		all := make([]string, len(words))
+		for i, word := range words {
+			all[i] = "LOWER(full_name) LIKE ? ESCAPE '!'"
+			whereArgs = append(whereArgs, "%"+word+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(all, " AND ")+")")
+	}
+
+	score := "CASE WHEN LOWER(email) = ? THEN 100 ELSE 0 END" +
+		" + CASE WHEN LOWER(email) LIKE ? ESCAPE '!' THEN 40 ELSE 0 END" +
+		" + CASE WHEN LOWER(full_name) LIKE ? ESCAPE '!' THEN 40 ELSE 0 END" +
+		" + CASE WHEN LOWER(full_name) LIKE ? ESCAPE '!' THEN 25 ELSE 0 END" +
+		" + CASE WHEN phone LIKE ? ESCAPE '!' THEN 30 ELSE 0 END" +
+		" + CASE WHEN LOWER(full_name) LIKE ? ESCAPE '!' THEN 10 ELSE 0 END" +
+		" + CASE WHEN LOWER(email) LIKE ? ESCAPE '!' THEN 10 ELSE 0 END" +
+		" + CASE WHEN phone LIKE ? ESCAPE '!' THEN 10 ELSE 0 END"
+	scoreArgs := []interface{}{
+		strings.ToLower(strings.TrimSpace(term)), prefix, prefix, wordPrefix, prefix,
+		contains, contains, contains,
+	}
+
+	if booleanQuery := fulltextBooleanQuery(term); mode == searchFulltext && booleanQuery != "" {
+		conditions = append(conditions, "MATCH(full_name, email) AGAINST (? IN BOOLEAN MODE)")
+		whereArgs = append(whereArgs, booleanQuery)
+		score += " + MATCH(full_name, email) AGAINST (? IN BOOLEAN MODE) * 5"
+		scoreArgs = append(scoreArgs, booleanQuery)
+	}
+
+	return strings.Join(conditions, " OR "), whereArgs, score, scoreArgs
+}
+
+// escapeLike escapes LIKE wildcards using '!' as the escape character
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+// fulltextBooleanQuery turns a search term into a MySQL boolean mode query
+// requiring every word as a prefix, e.g. "john smi" becomes "+john* +smi*"
+func fulltextBooleanQuery(term string) string {
+	strip := strings.NewReplacer("+", " ", "-", " ", "<", " ", ">", " ", "(", " ", ")", " ",
+		"~", " ", "*", " ", "\"", " ", "@", " ", ".", " ")
+
+	var words []string
+	for _, word := range strings.Fields(strip.Replace(term)) {
+		words = append(words, "+"+word+"*")
+	}
+	return strings.Join(words, " ")
+}
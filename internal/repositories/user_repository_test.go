@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDB opens an empty in-memory SQLite database with the user tables
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // Every connection would get its own database
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.Organization{}, &models.User{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestUserSearch(t *testing.T) {
+	repo := &UserRepository{db: testDB(t)}
+	org := uuid.New()
+	users := []models.User{
+		{FullName: "John Smith", Email: "john@example.com", Phone: "+1 555 0100"},
+		{FullName: "Johnny Walker", Email: "walker@example.com", Phone: "+1 555 0101", OrganizationID: &org},
+		{FullName: "Anna Johnson", Email: "anna@example.com", Phone: "+44 20 7946"},
+		{FullName: "Smith Jones", Email: "sj@example.com", Phone: "555"},
+		{FullName: "Rate 100% Kim", Email: "kim_lee@example.com", Phone: "+82 2"},
+		{FullName: "Rate 1000 Park", Email: "kimxlee@example.com", Phone: "+82 3"},
+		{FullName: "Bang! Bang", Email: "bang@example.com", Phone: "+1 555 0102", Status: models.UserStatusInactive},
+	}
+	created := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := range users {
+		users[i].Password = "hash"
+		users[i].CreatedAt = created.Add(time.Duration(i) * time.Hour)
+		if users[i].Status == "" {
+			users[i].Status = models.UserStatusActive
+		}
+		if err := repo.Create(&users[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name    string
+		term    string
+		filters map[string]interface{}
+		want    []string // Full names, most relevant first
+	}{
+		{"exact email first", "john@example.com", nil, []string{"John Smith"}},
+		{"name prefix before word prefix", "john", nil, []string{"John Smith", "Johnny Walker", "Anna Johnson"}},
+		{"words in any order", "smith john", nil, []string{"John Smith"}},
+		{"equal scores newest first", "555 01", nil, []string{"Bang! Bang", "Johnny Walker", "John Smith"}},
+		{"percent matched literally", "100%", nil, []string{"Rate 100% Kim"}},
+		{"underscore matched literally", "kim_", nil, []string{"Rate 100% Kim"}},
+		{"escape character matched literally", "bang!", nil, []string{"Bang! Bang"}},
+		{"case insensitive", "SMITH", nil, []string{"Smith Jones", "John Smith"}},
+		{"filtered", "john", map[string]interface{}{"organization_id": org}, []string{"Johnny Walker"}},
+		{"filtered by status", "555", map[string]interface{}{"status": models.UserStatusInactive}, []string{"Bang! Bang"}},
+		{"no match", "zebra", nil, nil},
+	}
+	for _, c := range cases {
+		hits, total, err := repo.Search(c.term, 0, 10, c.filters)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		var got []string
+		for _, hit := range hits {
+			got = append(got, hit.User.FullName)
+		}
+		if strings.Join(got, ", ") != strings.Join(c.want, ", ") || total != int64(len(c.want)) {
+			t.Errorf("%s: got %q (%d in total), want %q", c.name, got, total, c.want)
+		}
+	}
+
+	// Pages keep the total of every match
+	hits, total, err := repo.Search("john", 1, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].User.FullName != "Johnny Walker" || total != 3 {
+		t.Errorf("second page: got %d hits, %d in total", len(hits), total)
+	}
+	if hits[0].User.Organization != nil {
+		t.Errorf("got organization %v for a user of a missing organization", hits[0].User.Organization)
+	}
+}
+
+func TestUserSearchScore(t *testing.T) {
+	repo := &UserRepository{db: testDB(t)}
+	user := models.User{FullName: "Mary Ann Lee", Email: "mary@example.com", Phone: "0612345678", Password: "hash"}
+	if err := repo.Create(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		term  string
+		score float64
+	}{
+		{"mary@example.com", 100 + 40 + 10}, // Exact email, email prefix and substring
+		{"mary", 40 + 40 + 10 + 10},         // Email and name prefix, name and email substring
+		{"ann", 25 + 10},                    // Word prefix and substring of the name
+		{"0612", 30 + 10},                   // Phone prefix and substring
+		{"example", 10},                     // Email substring
+		{"lee mary", 0},                     // Every word of the name, in another order
+	}
+	for _, c := range cases {
+		hits, _, err := repo.Search(c.term, 0, 10, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", c.term, err)
+		}
+		if len(hits) != 1 || hits[0].Score != c.score {
+			t.Errorf("%s: got %+v, want one hit scoring %v", c.term, hits, c.score)
+		}
+	}
+}
+
+func TestUserSearchClauses(t *testing.T) {
+	where, whereArgs, score, scoreArgs := userSearchClauses("Jo_hn 50%", searchFulltext)
+	if !strings.Contains(where, "MATCH(full_name, email) AGAINST") || !strings.Contains(score, "MATCH(full_name, email) AGAINST") {
+		t.Errorf("fulltext search left out: %s / %s", where, score)
+	}
+	if got := whereArgs[0]; got != "%jo!_hn 50!%%" {
+		t.Errorf("got pattern %q, want the wildcards escaped", got)
+	}
+	if got := whereArgs[len(whereArgs)-1]; got != "+Jo_hn* +50%*" {
+		t.Errorf("got boolean query %q", got)
+	}
+	if got := scoreArgs[len(scoreArgs)-1]; got != "+Jo_hn* +50%*" {
+		t.Errorf("got score boolean query %q", got)
+	}
+
+	where, _, score, _ = userSearchClauses("john", searchLike)
+	if strings.Contains(where+score, "MATCH") {
+		t.Errorf("LIKE search uses fulltext: %s / %s", where, score)
+	}
+}
+
+func TestFulltextBooleanQuery(t *testing.T) {
+	cases := map[string]string{
+		"john smi":           "+john* +smi*",
+		"  john  ":           "+john*",
+		"-john +smith":       "+john* +smith*",
+		"john@example.com":   "+john* +example* +com*",
+		`"quoted" (grouped)`: "+quoted* +grouped*",
+		"*~<>":               "",
+	}
+	for term, want := range cases {
+		if got := fulltextBooleanQuery(term); got != want {
+			t.Errorf("%q: got %q, want %q", term, got, want)
+		}
+	}
+}
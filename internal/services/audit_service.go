@@ -0,0 +1,153 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditService struct {
+	auditRepo *repositories.AuditRepository
+}
+
+func NewAuditService() *AuditService {
+	return &AuditService{
+		auditRepo: repositories.NewAuditRepository(),
+	}
+}
+
+// AuditContext describes who performed an action and from where
+type AuditContext struct {
+	ActorID    *uuid.UUID
+	ActorEmail string
+	IPAddress  string
+	RequestID  string
+}
+
+// NewAuditContext builds an audit context for the given actor, which may be nil
+func NewAuditContext(actor *models.User, ip, requestID string) AuditContext {
+	actx := AuditContext{
+		IPAddress: ip,
+		RequestID: requestID,
+	}
+	if actor != nil {
+		id := actor.ID
+		actx.ActorID = &id
+		actx.ActorEmail = actor.Email
+	}
+	return actx
+}
+
+// AuditEntry describes a single audited change
+type AuditEntry struct {
+	Action     models.AuditAction
+	TargetType string
+	TargetID   *uuid.UUID
+	Before     interface{}
+	After      interface{}
+}
+
+// fieldChange is the before/after pair stored for each changed field
+type fieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Record writes an audit event using tx, so it commits or rolls back together
+// with the change it describes. Pass nil to write outside a transaction.
+func (s *AuditService) Record(tx *gorm.DB, actx AuditContext, entry AuditEntry) error {
+	changes, err := diffChanges(entry.Before, entry.After)
+	if err != nil {
+		return err
+	}
+
+	event := &models.AuditEvent{
+		ActorID:    actx.ActorID,
+		ActorEmail: actx.ActorEmail,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Changes:    changes,
+		IPAddress:  actx.IPAddress,
+		RequestID:  actx.RequestID,
+	}
+
+	repo := s.auditRepo
+	if tx != nil {
+		repo = repo.WithTx(tx)
+	}
+	return repo.Create(event)
+}
+
+// List gets audit events with pagination
+func (s *AuditService) List(offset, limit int, filters map[string]interface{}) ([]models.AuditEvent, int64, error) {
+	return s.auditRepo.List(offset, limit, filters)
+}
+
+// diffChanges compares the JSON representation of before and after and returns
+// the changed top-level fields. Sensitive fields are excluded because they are
+// not part of the JSON representation, and nested relations are skipped.
+func diffChanges(before, after interface{}) (json.RawMessage, error) {
+	if before == nil && after == nil {
+		return nil, nil
+	}
+
+	beforeFields, err := toFieldMap(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := toFieldMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]fieldChange{}
+	for key, value := range afterFields {
+		if key == "updated_at" || isNested(value) {
+			continue
+		}
+		if old, ok := beforeFields[key]; !ok || !reflect.DeepEqual(old, value) {
+			changes[key] = fieldChange{Before: beforeFields[key], After: value}
+		}
+	}
+	for key, old := range beforeFields {
+		if _, ok := afterFields[key]; ok || key == "updated_at" || isNested(old) {
+			continue
+		}
+		changes[key] = fieldChange{Before: old}
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+func toFieldMap(v interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func isNested(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
@@ -2,22 +2,25 @@ package services
 
 import (
 	"errors"
-	"time"
 
+	"echo-golang/internal/database"
 	"echo-golang/internal/models"
 	"echo-golang/internal/repositories"
 	"echo-golang/internal/utils"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type AuthService struct {
-	userRepo *repositories.UserRepository
+	userRepo     *repositories.UserRepository
+	auditService *AuditService
 }
 
 func NewAuthService() *AuthService {
 	return &AuthService{
-		userRepo: repositories.NewUserRepository(),
+		userRepo:     repositories.NewUserRepository(),
+		auditService: NewAuditService(),
 	}
 }
 
@@ -42,22 +45,15 @@ type LoginResponse struct {
 	ExpiresIn    int64        `json:"expires_in"`
 }
 
+// ErrNotConsoleAdmin is returned when a user who is not a super admin logs
+// in to the admin console
+var ErrNotConsoleAdmin = errors.New("only super admins can use the admin console")
+
 // Login authenticates a user and returns JWT tokens
-func (s *AuthService) Login(req LoginRequest) (*LoginResponse, error) {
-	// Get user by email
-	user, err := s.userRepo.GetByEmail(req.Email)
+func (s *AuthService) Login(req LoginRequest, actx AuditContext) (*LoginResponse, error) {
+	user, actx, err := s.authenticate(req, actx, models.AuditActionLoginFailure)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
-	}
-
-	// Check if user is active
-	if !user.IsActive() {
-		return nil, errors.New("account is inactive")
-	}
-
-	// Verify password
-	if !utils.CheckPasswordHash(req.Password, user.Password) {
-		return nil, errors.New("invalid email or password")
+		return nil, err
 	}
 
 	// Generate tokens
@@ -71,8 +67,9 @@ func (s *AuthService) Login(req LoginRequest) (*LoginResponse, error) {
 		return nil, errors.New("failed to generate refresh token")
 	}
 
-	// Update last login
-	_ = s.userRepo.UpdateLastLogin(user.ID)
+	if err := s.recordLogin(actx, user, models.AuditActionLoginSuccess); err != nil {
+		return nil, err
+	}
 
 	// Calculate expires in (seconds)
 	expiresIn := int64(15 * 60) // 15 minutes
@@ -85,8 +82,83 @@ func (s *AuthService) Login(req LoginRequest) (*LoginResponse, error) {
 	}, nil
 }
 
+// ConsoleLogin authenticates a super admin for the admin console. It issues
+// no tokens, and any other user fails as a console login before anything is
+// recorded as a login.
+func (s *AuthService) ConsoleLogin(req LoginRequest, actx AuditContext) (*models.User, error) {
+	user, actx, err := s.authenticate(req, actx, models.AuditActionConsoleLoginFailure)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin() {
+		s.recordLoginFailure(actx, &user.ID, models.AuditActionConsoleLoginFailure)
+		return nil, ErrNotConsoleAdmin
+	}
+
+	if err := s.recordLogin(actx, user, models.AuditActionConsoleLoginSuccess); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// authenticate checks a user's credentials without side effects but
+// recording a failure under the given action. It returns the user with the
+// audit context of the user.
+func (s *AuthService) authenticate(req LoginRequest, actx AuditContext, failure models.AuditAction) (*models.User, AuditContext, error) {
+	// Get user by email
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil {
+		actx.ActorEmail = req.Email
+		s.recordLoginFailure(actx, nil, failure)
+		return nil, actx, errors.New("invalid email or password")
+	}
+	actx = NewAuditContext(user, actx.IPAddress, actx.RequestID)
+
+	// Check if user is active
+	if !user.IsActive() {
+		s.recordLoginFailure(actx, &user.ID, failure)
+		return nil, actx, errors.New("account is inactive")
+	}
+
+	// Verify password
+	if !utils.CheckPasswordHash(req.Password, user.Password) {
+		s.recordLoginFailure(actx, &user.ID, failure)
+		return nil, actx, errors.New("invalid email or password")
+	}
+	return user, actx, nil
+}
+
+// recordLogin updates the last login of a user and records the successful
+// login together
+func (s *AuthService) recordLogin(actx AuditContext, user *models.User, action models.AuditAction) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).UpdateLastLogin(user.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     action,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &user.ID,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to record login")
+	}
+	return nil
+}
+
+// recordLoginFailure records a failed login attempt. Failures are best effort
+// and never change the response returned to the client.
+func (s *AuthService) recordLoginFailure(actx AuditContext, userID *uuid.UUID, action models.AuditAction) {
+	_ = s.auditService.Record(nil, actx, AuditEntry{
+		Action:     action,
+		TargetType: models.AuditTargetUser,
+		TargetID:   userID,
+	})
+}
+
 // Register creates a new user
-func (s *AuthService) Register(req RegisterRequest) (*models.User, error) {
+func (s *AuthService) Register(req RegisterRequest, actx AuditContext) (*models.User, error) {
 	// Check if email already exists
 	existingUser, _ := s.userRepo.GetByEmail(req.Email)
 	if existingUser != nil {
@@ -110,7 +182,18 @@ func (s *AuthService) Register(req RegisterRequest) (*models.User, error) {
 		Status:         models.UserStatusActive,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).Create(user); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, NewAuditContext(user, actx.IPAddress, actx.RequestID), AuditEntry{
+			Action:     models.AuditActionRegister,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &user.ID,
+			After:      user,
+		})
+	})
+	if err != nil {
 		return nil, errors.New("failed to create user")
 	}
 
@@ -123,6 +206,45 @@ func (s *AuthService) Register(req RegisterRequest) (*models.User, error) {
 	return createdUser, nil
 }
 
+// RefreshToken validates a refresh token and issues a new token pair
+func (s *AuthService) RefreshToken(refreshToken string, actx AuditContext) (*LoginResponse, error) {
+	claims, err := utils.ValidateToken(refreshToken)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	accessToken, err := utils.GenerateToken(user)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	newRefreshToken, err := utils.GenerateRefreshToken(user)
+	if err != nil {
+		return nil, errors.New("failed to generate refresh token")
+	}
+
+	err = s.auditService.Record(nil, NewAuditContext(user, actx.IPAddress, actx.RequestID), AuditEntry{
+		Action:     models.AuditActionTokenRefresh,
+		TargetType: models.AuditTargetUser,
+		TargetID:   &user.ID,
+	})
+	if err != nil {
+		return nil, errors.New("failed to record token refresh")
+	}
+
+	return &LoginResponse{
+		User:         user,
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresIn:    int64(15 * 60), // 15 minutes
+	}, nil
+}
+
 // GetCurrentUser gets the current authenticated user
 func (s *AuthService) GetCurrentUser(userID uuid.UUID) (*models.User, error) {
 	return s.userRepo.GetByID(userID)
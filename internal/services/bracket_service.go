@@ -0,0 +1,555 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"echo-golang/internal/bracket"
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBracketNotFound      = errors.New("tournament has no bracket")
+	ErrBracketExists        = errors.New("tournament already has a bracket")
+	ErrBracketStarted       = errors.New("bracket games have already been scheduled")
+	ErrBracketLocked        = errors.New("the next bracket game has already been scheduled")
+	ErrBracketNodeNotFound  = errors.New("bracket game not found")
+	ErrBracketNodeNotReady  = errors.New("bracket game is still waiting on its teams or is a bye")
+	ErrBracketNodeScheduled = errors.New("bracket game already has a match")
+	ErrInvalidBracketSize   = errors.New("teams must not exceed the number of registered teams")
+	ErrTooFewForDoubleElim  = bracket.ErrTooFewForDoubleElim
+)
+
+type BracketService struct {
+	tournamentRepo    *repositories.TournamentRepository
+	bracketRepo       *repositories.BracketRepository
+	tournamentService *TournamentService
+	matchService      *MatchService
+	auditService      *AuditService
+}
+
+func NewBracketService() *BracketService {
+	return &BracketService{
+		tournamentRepo:    repositories.NewTournamentRepository(),
+		bracketRepo:       repositories.NewBracketRepository(),
+		tournamentService: NewTournamentService(),
+		matchService:      NewMatchService(),
+		auditService:      NewAuditService(),
+	}
+}
+
+// GenerateBracketRequest describes a knockout bracket to generate. Seeds come
+// from the standings, or from the registration seeds when seeding is entries.
+type GenerateBracketRequest struct {
+	Format  string `json:"format,omitempty" binding:"omitempty,oneof=single double"`      // Defaults to single
+	Seeding string `json:"seeding,omitempty" binding:"omitempty,oneof=standings entries"` // Defaults to standings
+	Teams   int    `json:"teams,omitempty" binding:"omitempty,min=2"`                     // Only the top teams qualify, defaults to all
+}
+
+// ScheduleBracketMatchRequest creates the match of a bracket game whose teams
+// are known
+type ScheduleBracketMatchRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"` // RFC3339
+	Venue       string    `json:"venue" binding:"required"`
+	RefereeName string    `json:"referee_name,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+}
+
+// BracketView is a bracket laid out round by round for rendering
+type BracketView struct {
+	TournamentID   uuid.UUID      `json:"tournament_id"`
+	Format         string         `json:"format"`
+	ChampionTeamID *uuid.UUID     `json:"champion_team_id,omitempty"`
+	Rounds         []BracketRound `json:"rounds"`
+}
+
+type BracketRound struct {
+	Bracket string        `json:"bracket"`
+	Round   int           `json:"round"`
+	Name    string        `json:"name"`
+	Games   []BracketGame `json:"games"`
+}
+
+// BracketGame is a node with the games feeding its sides
+type BracketGame struct {
+	models.BracketNode
+	HomeFrom *BracketFeeder `json:"home_from,omitempty"`
+	AwayFrom *BracketFeeder `json:"away_from,omitempty"`
+}
+
+// BracketFeeder names the game whose winner or loser fills a side
+type BracketFeeder struct {
+	NodeID uuid.UUID `json:"node_id"`
+	Key    string    `json:"key"`
+	Result string    `json:"result"` // winner or loser
+}
+
+// GetBracket gets a tournament's bracket
+func (s *BracketService) GetBracket(id uuid.UUID) (*BracketView, error) {
+	if _, err := s.tournamentRepo.GetByID(id); err != nil {
+		return nil, ErrTournamentNotFound
+	}
+	nodes, err := s.bracketRepo.ListByTournament(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, ErrBracketNotFound
+	}
+	return bracketView(id, nodes), nil
+}
+
+// GenerateBracket seeds the registered teams into a knockout bracket. Byes go
+// to the top seeds and are decided straight away.
+func (s *BracketService) GenerateBracket(actor *models.User, id uuid.UUID, req GenerateBracketRequest, actx AuditContext) (*BracketView, error) {
+	tournament, err := s.tournamentService.managedTournament(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if tournament.Status == models.TournamentStatusCompleted {
+		return nil, ErrTournamentCompleted
+	}
+	existing, err := s.bracketRepo.CountByTournament(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrBracketExists
+	}
+
+	seeding := req.Seeding
+	if seeding == "" {
+		seeding = "standings"
+	}
+	seeds, err := s.seeds(tournament.ID, seeding)
+	if err != nil {
+		return nil, err
+	}
+	if req.Teams > len(seeds) {
+		return nil, ErrInvalidBracketSize
+	}
+	if req.Teams > 0 {
+		seeds = seeds[:req.Teams]
+	}
+	if len(seeds) < 2 {
+		return nil, ErrNotEnoughEntries
+	}
+
+	format := "single"
+	if req.Format == "double" {
+		format = "double"
+	}
+	plan, err := bracket.New(len(seeds), format == "double")
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := bracketNodes(tournament.ID, plan, seeds)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.bracketRepo.WithTx(tx).CreateNodes(nodes); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentGenerateBracket,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+			After:      map[string]interface{}{"format": format, "seeding": seeding, "teams": seeds},
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to generate bracket")
+	}
+
+	return s.GetBracket(tournament.ID)
+}
+
+// DeleteBracket removes a bracket none of whose games have been scheduled
+func (s *BracketService) DeleteBracket(actor *models.User, id uuid.UUID, actx AuditContext) error {
+	tournament, err := s.tournamentService.managedTournament(actor, id)
+	if err != nil {
+		return err
+	}
+	nodes, err := s.bracketRepo.CountByTournament(tournament.ID)
+	if err != nil {
+		return err
+	}
+	if nodes == 0 {
+		return ErrBracketNotFound
+	}
+	scheduled, err := s.bracketRepo.CountScheduled(tournament.ID)
+	if err != nil {
+		return err
+	}
+	if scheduled > 0 {
+		return ErrBracketStarted
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.bracketRepo.WithTx(tx).DeleteByTournament(tournament.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentDeleteBracket,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to delete bracket")
+	}
+	return nil
+}
+
+// ScheduleMatch creates the match of a bracket game once both of its teams
+// are known. A game whose match was cancelled can be scheduled again.
+func (s *BracketService) ScheduleMatch(actor *models.User, id, nodeID uuid.UUID, req ScheduleBracketMatchRequest, actx AuditContext) (*models.Match, error) {
+	tournament, err := s.tournamentService.managedTournament(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	node, err := s.bracketRepo.GetNode(tournament.ID, nodeID)
+	if err != nil {
+		return nil, ErrBracketNodeNotFound
+	}
+	if err := s.checkSchedulable(s.matchService.matchRepo, node); err != nil {
+		return nil, err
+	}
+
+	home, err := s.matchService.activeTeam(*node.HomeTeamID)
+	if err != nil {
+		return nil, err
+	}
+	away, err := s.matchService.activeTeam(*node.AwayTeamID)
+	if err != nil {
+		return nil, err
+	}
+	match := &models.Match{
+		TournamentID: &tournament.ID,
+		HomeTeamID:   home.ID,
+		AwayTeamID:   away.ID,
+		ScheduledAt:  req.ScheduledAt.UTC(),
+		Venue:        strings.TrimSpace(req.Venue),
+		Status:       models.MatchStatusScheduled,
+		RefereeName:  req.RefereeName,
+		Notes:        req.Notes,
+		CreatedBy:    &actor.ID,
+	}
+	if err := s.matchService.checkTournament(match); err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// The node may have been scheduled or decided since it was read
+		bracketRepo := s.bracketRepo.WithTx(tx)
+		matchRepo := s.matchService.matchRepo.WithTx(tx)
+		node, err := bracketRepo.GetNodeForUpdate(tournament.ID, nodeID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkSchedulable(matchRepo, node); err != nil {
+			return err
+		}
+		if *node.HomeTeamID != match.HomeTeamID || *node.AwayTeamID != match.AwayTeamID {
+			return ErrBracketNodeNotReady
+		}
+		if err := s.matchService.checkBooking(matchRepo, match); err != nil {
+			return err
+		}
+		if err := matchRepo.Create(match); err != nil {
+			return err
+		}
+		node.MatchID = &match.ID
+		if err := bracketRepo.Update(node); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionMatchCreate,
+			TargetType: models.AuditTargetMatch,
+			TargetID:   &match.ID,
+			After:      match,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrScheduleConflict) || errors.Is(err, ErrBracketNodeNotReady) || errors.Is(err, ErrBracketNodeScheduled) {
+			return nil, err
+		}
+		return nil, errors.New("failed to schedule bracket match")
+	}
+
+	return s.matchService.GetMatch(match.ID)
+}
+
+// checkSchedulable checks that a match can be scheduled for a node: both of
+// its teams are known, it isn't decided and its current match, if any, was
+// cancelled
+func (s *BracketService) checkSchedulable(matchRepo *repositories.MatchRepository, node *models.BracketNode) error {
+	if node.Decided || !node.IsReady() || node.HasBye() {
+		return ErrBracketNodeNotReady
+	}
+	if node.MatchID != nil {
+		current, err := matchRepo.GetByID(*node.MatchID)
+		if err == nil && current.Status != models.MatchStatusCancelled {
+			return ErrBracketNodeScheduled
+		}
+	}
+	return nil
+}
+
+// bracketNodes turns the plan of a bracket into its nodes with the seeded
+// teams placed, deciding the games of the top seeds' byes
+func bracketNodes(tournamentID uuid.UUID, plan *bracket.Plan, seeds []uuid.UUID) ([]*models.BracketNode, error) {
+	// Assign IDs up front so the links between nodes can be stored
+	ids := make(map[string]uuid.UUID, len(plan.Nodes))
+	for _, n := range plan.Nodes {
+		ids[n.Key] = uuid.New()
+	}
+	nodes := make([]*models.BracketNode, len(plan.Nodes))
+	for i, n := range plan.Nodes {
+		node := &models.BracketNode{
+			ID:           ids[n.Key],
+			TournamentID: tournamentID,
+			Key:          n.Key,
+			Bracket:      n.Bracket,
+			Round:        n.Round,
+			Position:     n.Position,
+		}
+		if n.WinnerTo != nil {
+			next := ids[n.WinnerTo.Key]
+			node.WinnerNodeID, node.WinnerSide = &next, n.WinnerTo.Side
+		}
+		if n.LoserTo != nil {
+			next := ids[n.LoserTo.Key]
+			node.LoserNodeID, node.LoserSide = &next, n.LoserTo.Side
+		}
+		if n.HomeSeed > 0 {
+			node.HomeSeed, node.AwaySeed = &n.HomeSeed, &n.AwaySeed
+			node.Place(bracket.Home, seedTeam(seeds, n.HomeSeed))
+			node.Place(bracket.Away, seedTeam(seeds, n.AwaySeed))
+		}
+		nodes[i] = node
+	}
+
+	state := newBracketState(nodes)
+	for _, node := range nodes {
+		if node.HomeSeed != nil && node.HasBye() {
+			if err := state.decide(node, byeWinner(node), nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// seeds lists the registered teams from the first seed down
+func (s *BracketService) seeds(tournamentID uuid.UUID, seeding string) ([]uuid.UUID, error) {
+	var teams []uuid.UUID
+	if seeding == "entries" {
+		entries, err := s.tournamentRepo.ListEntries(tournamentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			teams = append(teams, entry.TeamID)
+		}
+		return teams, nil
+	}
+
+	standings, err := s.tournamentService.GetStandings(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	// Group winners first, then the runners-up and so on
+	sort.SliceStable(standings, func(i, j int) bool { return standings[i].Rank < standings[j].Rank })
+	for _, standing := range standings {
+		teams = append(teams, standing.TeamID)
+	}
+	return teams, nil
+}
+
+// advanceBracket moves the winner and loser of a completed bracket match on to
+// their next games. It runs in the transaction that completes the match, so a
+// corrected result that would change an already scheduled game is rejected.
+// Matches outside a bracket are left alone.
+func advanceBracket(bracketRepo *repositories.BracketRepository, match *models.Match) error {
+	if !match.IsCompleted() || match.WinnerTeamID == nil {
+		return nil
+	}
+	node, err := bracketRepo.GetByMatch(match.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if node.Decided && node.WinnerTeamID != nil && *node.WinnerTeamID == *match.WinnerTeamID {
+		return nil
+	}
+
+	list, err := bracketRepo.ListByTournament(node.TournamentID)
+	if err != nil {
+		return err
+	}
+	nodes := make([]*models.BracketNode, len(list))
+	for i := range list {
+		nodes[i] = &list[i]
+	}
+	state := newBracketState(nodes)
+
+	loser := match.HomeTeamID
+	if *match.WinnerTeamID == match.HomeTeamID {
+		loser = match.AwayTeamID
+	}
+	if err := state.decide(state.nodes[node.ID], match.WinnerTeamID, &loser); err != nil {
+		return err
+	}
+	for _, changed := range state.changed {
+		if err := bracketRepo.Update(changed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bracketState moves teams through the nodes of one bracket in memory,
+// remembering the nodes it changed
+type bracketState struct {
+	nodes   map[uuid.UUID]*models.BracketNode
+	changed map[uuid.UUID]*models.BracketNode
+}
+
+func newBracketState(nodes []*models.BracketNode) *bracketState {
+	state := &bracketState{
+		nodes:   make(map[uuid.UUID]*models.BracketNode, len(nodes)),
+		changed: map[uuid.UUID]*models.BracketNode{},
+	}
+	for _, node := range nodes {
+		state.nodes[node.ID] = node
+	}
+	return state
+}
+
+// decide records the winner of a node and sends the winner and loser on. A
+// nil team is a bye.
+func (b *bracketState) decide(node *models.BracketNode, winner, loser *uuid.UUID) error {
+	node.Decided = true
+	node.WinnerTeamID = winner
+	b.changed[node.ID] = node
+
+	if node.WinnerNodeID != nil {
+		if err := b.place(*node.WinnerNodeID, node.WinnerSide, winner); err != nil {
+			return err
+		}
+	}
+	if node.LoserNodeID != nil {
+		if err := b.place(*node.LoserNodeID, node.LoserSide, loser); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// place fills a side of a node, deciding it at once when the other side is a
+// bye. Replacing the team of a node that already has a match is refused.
+func (b *bracketState) place(id uuid.UUID, side string, teamID *uuid.UUID) error {
+	node := b.nodes[id]
+	if node.MatchID != nil {
+		return ErrBracketLocked
+	}
+	node.Place(side, teamID)
+	node.Decided, node.WinnerTeamID = false, nil
+	b.changed[node.ID] = node
+
+	if node.IsReady() && node.HasBye() {
+		return b.decide(node, byeWinner(node), nil)
+	}
+	return nil
+}
+
+// byeWinner is the team facing a bye, or nil when both sides are byes
+func byeWinner(node *models.BracketNode) *uuid.UUID {
+	if node.HomeBye {
+		return node.AwayTeamID
+	}
+	return node.HomeTeamID
+}
+
+// seedTeam gets the team with a seed, nil for a bye
+func seedTeam(seeds []uuid.UUID, seed int) *uuid.UUID {
+	if seed > len(seeds) {
+		return nil
+	}
+	return &seeds[seed-1]
+}
+
+// bracketView groups nodes into rounds: the winners bracket, then the losers
+// bracket, then the grand final
+func bracketView(tournamentID uuid.UUID, nodes []models.BracketNode) *BracketView {
+	order := map[string]int{bracket.Winners: 0, bracket.Losers: 1, bracket.Final: 2}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.Bracket != b.Bracket {
+			return order[a.Bracket] < order[b.Bracket]
+		}
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.Position < b.Position
+	})
+
+	rounds := map[string]int{}
+	feeders := map[uuid.UUID]map[string]*BracketFeeder{}
+	feed := func(to *uuid.UUID, side string, from models.BracketNode, result string) {
+		if to == nil {
+			return
+		}
+		if feeders[*to] == nil {
+			feeders[*to] = map[string]*BracketFeeder{}
+		}
+		feeders[*to][side] = &BracketFeeder{NodeID: from.ID, Key: from.Key, Result: result}
+	}
+	for _, node := range nodes {
+		if node.Round > rounds[node.Bracket] {
+			rounds[node.Bracket] = node.Round
+		}
+		feed(node.WinnerNodeID, node.WinnerSide, node, "winner")
+		feed(node.LoserNodeID, node.LoserSide, node, "loser")
+	}
+
+	view := &BracketView{TournamentID: tournamentID, Format: "single"}
+	if rounds[bracket.Losers] > 0 {
+		view.Format = "double"
+	}
+	for _, node := range nodes {
+		last := len(view.Rounds) - 1
+		if last < 0 || view.Rounds[last].Bracket != node.Bracket || view.Rounds[last].Round != node.Round {
+			view.Rounds = append(view.Rounds, BracketRound{
+				Bracket: node.Bracket,
+				Round:   node.Round,
+				Name:    bracket.RoundName(node.Bracket, node.Round, rounds[node.Bracket]),
+			})
+			last++
+		}
+		game := BracketGame{BracketNode: node}
+		if from := feeders[node.ID]; from != nil {
+			game.HomeFrom, game.AwayFrom = from[bracket.Home], from[bracket.Away]
+		}
+		view.Rounds[last].Games = append(view.Rounds[last].Games, game)
+
+		// The last game of the bracket crowns the champion
+		if node.WinnerNodeID == nil && node.Decided {
+			view.ChampionTeamID = node.WinnerTeamID
+		}
+	}
+	return view
+}
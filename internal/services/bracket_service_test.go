@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"echo-golang/internal/bracket"
+	"echo-golang/internal/config"
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+func TestAdvanceBracket(t *testing.T) {
+	withTestDB(t, &models.Team{}, &models.Match{}, &models.BracketNode{})
+	repo := repositories.NewBracketRepository()
+	tournament := uuid.New()
+	seeds := make([]uuid.UUID, 5)
+	names := map[uuid.UUID]string{}
+	for i := range seeds {
+		seeds[i] = uuid.New()
+		names[seeds[i]] = strconv.Itoa(i + 1)
+	}
+
+	// describe shows each node's sides by seed, "bye", or "-" while waiting,
+	// then the winner once decided
+	describe := func() map[string]string {
+		nodes, err := repo.ListByTournament(tournament)
+		if err != nil {
+			t.Fatal(err)
+		}
+		side := func(team *uuid.UUID, bye bool) string {
+			switch {
+			case bye:
+				return "bye"
+			case team == nil:
+				return "-"
+			}
+			return names[*team]
+		}
+		got := map[string]string{}
+		for _, n := range nodes {
+			s := side(n.HomeTeamID, n.HomeBye) + " v " + side(n.AwayTeamID, n.AwayBye)
+			if n.Decided {
+				s += " > " + side(n.WinnerTeamID, n.WinnerTeamID == nil)
+			}
+			got[n.Key] = s
+		}
+		return got
+	}
+	check := func(step string, want map[string]string) {
+		t.Helper()
+		got := describe()
+		for key, w := range want {
+			if got[key] != w {
+				t.Errorf("%s: %s is %q, want %q", step, key, got[key], w)
+			}
+		}
+	}
+
+	// Five entrants in double elimination: seeds 1 to 3 get byes, which
+	// sends byes into both sides of L1-2
+	plan, err := bracket.New(5, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := bracketNodes(tournament, plan, seeds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateNodes(nodes); err != nil {
+		t.Fatal(err)
+	}
+	check("generated", map[string]string{
+		"W1-1": "1 v bye > 1",
+		"W1-2": "4 v 5",
+		"W1-3": "2 v bye > 2",
+		"W1-4": "3 v bye > 3",
+		"W2-1": "1 v -",
+		"W2-2": "2 v 3",
+		"L1-1": "bye v -",
+		"L1-2": "bye v bye > bye",
+		"L2-1": "- v -",
+		"L2-2": "bye v -",
+	})
+
+	ids := map[string]uuid.UUID{}
+	for _, n := range nodes {
+		ids[n.Key] = n.ID
+	}
+	schedule := func(key string) *models.BracketNode {
+		node, err := repo.GetNode(tournament, ids[key])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.MatchID == nil {
+			id := uuid.New()
+			node.MatchID = &id
+			if err := repo.Update(node); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return node
+	}
+	play := func(key string, home, away, winner int) error {
+		node := schedule(key)
+		match := &models.Match{ID: *node.MatchID, HomeTeamID: seeds[home-1], AwayTeamID: seeds[away-1],
+			Status: models.MatchStatusCompleted, WinnerTeamID: &seeds[winner-1]}
+		return advanceBracket(repo, match)
+	}
+
+	// The loser of W1-2 gets past the bye in L1-1 at once
+	if err := play("W1-2", 4, 5, 4); err != nil {
+		t.Fatal(err)
+	}
+	check("W1-2 decided", map[string]string{
+		"W1-2": "4 v 5 > 4",
+		"W2-1": "1 v 4",
+		"L1-1": "bye v 5 > 5",
+		"L2-1": "5 v -",
+	})
+
+	// The loser of W2-1 drops into L2-2, the reversed side, and passes the
+	// bye that came out of L1-2
+	if err := play("W2-1", 1, 4, 1); err != nil {
+		t.Fatal(err)
+	}
+	check("W2-1 decided", map[string]string{
+		"W2-1": "1 v 4 > 1",
+		"W3-1": "1 v -",
+		"L2-2": "bye v 4 > 4",
+		"L3-1": "- v 4",
+	})
+
+	// A corrected result swaps the teams in the games that follow
+	if err := play("W2-1", 1, 4, 4); err != nil {
+		t.Fatal(err)
+	}
+	check("W2-1 corrected", map[string]string{
+		"W2-1": "1 v 4 > 4",
+		"W3-1": "4 v -",
+		"L2-2": "bye v 1 > 1",
+		"L3-1": "- v 1",
+	})
+
+	// Once a later game has its match, the result can't change its teams
+	schedule("L3-1")
+	if err := play("W2-1", 1, 4, 1); !errors.Is(err, ErrBracketLocked) {
+		t.Errorf("correcting a result feeding a scheduled game: got %v, want %v", err, ErrBracketLocked)
+	}
+	check("correction refused", map[string]string{
+		"W2-1": "1 v 4 > 4",
+		"W3-1": "4 v -",
+	})
+}
+
+func TestScheduleBracketMatchConcurrently(t *testing.T) {
+	withTestDB(t, &models.Organization{}, &models.Team{}, &models.Tournament{}, &models.TournamentEntry{},
+		&models.Match{}, &models.VenueLock{}, &models.BracketNode{}, &models.AuditEvent{})
+	saved := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = saved })
+	config.AppConfig = &config.Config{MatchDuration: 2 * time.Hour, MatchSchedulingBuffer: 30 * time.Minute}
+	s := NewBracketService()
+	admin := &models.User{ID: uuid.New(), Role: models.RoleSuperAdmin}
+
+	start := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	tournament := models.Tournament{ID: uuid.New(), Name: "Cup", StartDate: start, EndDate: start.Add(7 * 24 * time.Hour)}
+	if err := database.DB.Create(&tournament).Error; err != nil {
+		t.Fatal(err)
+	}
+	seeds := []uuid.UUID{uuid.New(), uuid.New()}
+	for i, id := range seeds {
+		team := models.Team{ID: id, OrganizationID: uuid.New(), Name: strconv.Itoa(i + 1), Status: models.TeamStatusActive}
+		if err := database.DB.Create(&team).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := database.DB.Create(&models.TournamentEntry{TournamentID: tournament.ID, TeamID: id}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	plan, err := bracket.New(2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := bracketNodes(tournament.ID, plan, seeds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repositories.NewBracketRepository().CreateNodes(nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	// The requests are far enough apart not to conflict, so only the node
+	// can keep all but one of them from being scheduled
+	reqs := make([]ScheduleBracketMatchRequest, 8)
+	for i := range reqs {
+		reqs[i] = ScheduleBracketMatchRequest{ScheduledAt: start.Add(time.Duration(12*i+6) * time.Hour), Venue: "Gym " + strconv.Itoa(i)}
+	}
+	done := holdReads(t, "bracket_nodes", len(reqs))
+	errs := make(chan error, len(reqs))
+	var wg sync.WaitGroup
+	for _, req := range reqs {
+		wg.Add(1)
+		go func(req ScheduleBracketMatchRequest) {
+			defer wg.Done()
+			defer done()
+			_, err := s.ScheduleMatch(admin, tournament.ID, nodes[0].ID, req, AuditContext{})
+			errs <- err
+		}(req)
+	}
+	wg.Wait()
+	close(errs)
+
+	scheduled := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			scheduled++
+		case !errors.Is(err, ErrBracketNodeScheduled):
+			t.Errorf("got %v, want %v", err, ErrBracketNodeScheduled)
+		}
+	}
+	var count int64
+	if err := database.DB.Model(&models.Match{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if scheduled != 1 || count != 1 {
+		t.Errorf("%d scheduled and %d matches created, want 1 of each", scheduled, count)
+	}
+}
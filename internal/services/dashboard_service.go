@@ -0,0 +1,62 @@
+package services
+
+import (
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+)
+
+type DashboardService struct {
+	userRepo  *repositories.UserRepository
+	orgRepo   *repositories.OrganizationRepository
+	teamRepo  *repositories.TeamRepository
+	matchRepo *repositories.MatchRepository
+}
+
+func NewDashboardService() *DashboardService {
+	return &DashboardService{
+		userRepo:  repositories.NewUserRepository(),
+		orgRepo:   repositories.NewOrganizationRepository(),
+		teamRepo:  repositories.NewTeamRepository(),
+		matchRepo: repositories.NewMatchRepository(),
+	}
+}
+
+type DashboardStats struct {
+	TotalUsers          int64 `json:"total_users"`
+	ActiveUsers         int64 `json:"active_users"`
+	TotalOrganizations  int64 `json:"total_organizations"`
+	ActiveOrganizations int64 `json:"active_organizations"`
+	TotalTeams          int64 `json:"total_teams"`
+	TotalMatches        int64 `json:"total_matches"`
+	ActiveMatches       int64 `json:"active_matches"`
+}
+
+// GetStats collects the counts shown on the admin dashboard
+func (s *DashboardService) GetStats() (*DashboardStats, error) {
+	var stats DashboardStats
+	var err error
+
+	if stats.TotalUsers, err = s.userRepo.Count(nil); err != nil {
+		return nil, err
+	}
+	if stats.ActiveUsers, err = s.userRepo.Count(map[string]interface{}{"status": models.UserStatusActive}); err != nil {
+		return nil, err
+	}
+	if stats.TotalOrganizations, err = s.orgRepo.Count(nil); err != nil {
+		return nil, err
+	}
+	if stats.ActiveOrganizations, err = s.orgRepo.Count(map[string]interface{}{"status": models.OrgStatusActive}); err != nil {
+		return nil, err
+	}
+	if stats.TotalTeams, err = s.teamRepo.Count(nil); err != nil {
+		return nil, err
+	}
+	if stats.TotalMatches, err = s.matchRepo.Count(nil); err != nil {
+		return nil, err
+	}
+	if stats.ActiveMatches, err = s.matchRepo.Count(map[string]interface{}{"status": models.MatchStatusLive}); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
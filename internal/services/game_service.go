@@ -0,0 +1,345 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-golang/internal/config"
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidGameTransition = errors.New("invalid game state transition")
+	ErrClockRunning          = errors.New("the clock must be stopped first")
+	ErrClockNotRunning       = errors.New("the clock is not running")
+	ErrClockExpired          = errors.New("there is no time left in the period")
+	ErrPeriodNotOver         = errors.New("the period clock has not run out")
+	ErrInvalidClock          = errors.New("remaining must be a time within the period, such as 7:32 or 45.2")
+)
+
+// GameService runs the server-authoritative game state of live matches:
+// periods, breaks and the game clock. Every change is appended to the match's
+// event log.
+type GameService struct {
+	tournamentRepo *repositories.TournamentRepository
+	bracketRepo    *repositories.BracketRepository
+	standingRepo   *repositories.StandingRepository
+	eventService   *MatchEventService
+	auditService   *AuditService
+}
+
+func NewGameService() *GameService {
+	return &GameService{
+		tournamentRepo: repositories.NewTournamentRepository(),
+		bracketRepo:    repositories.NewBracketRepository(),
+		standingRepo:   repositories.NewStandingRepository(),
+		eventService:   NewMatchEventService(),
+		auditService:   NewAuditService(),
+	}
+}
+
+// SetClockRequest corrects the time left in the period
+type SetClockRequest struct {
+	Remaining string `json:"remaining" binding:"required"` // M:SS or seconds with tenths, e.g. 7:32 or 45.2
+}
+
+// GameClock is the game state of a match at ServerTime. While the clock runs,
+// clients count down from ClockRemainingMs themselves.
+type GameClock struct {
+	MatchID          uuid.UUID          `json:"match_id"`
+	Status           models.MatchStatus `json:"status"`
+	State            models.GameState   `json:"state"`
+	Period           int                `json:"period"`
+	PeriodLabel      string             `json:"period_label,omitempty"`
+	PeriodCount      int                `json:"period_count,omitempty"`
+	PeriodLength     int                `json:"period_length,omitempty"`   // Seconds
+	OvertimeLength   int                `json:"overtime_length,omitempty"` // Seconds
+	ClockRunning     bool               `json:"clock_running"`
+	ClockRemainingMs int64              `json:"clock_remaining_ms"`
+	Clock            string             `json:"clock"`
+	HomeScore        int                `json:"home_score"`
+	AwayScore        int                `json:"away_score"`
+	Sequence         int                `json:"sequence"`
+	ServerTime       time.Time          `json:"server_time"`
+}
+
+// GetGame gets the game state of a match
+func (s *GameService) GetGame(viewer *models.User, matchID uuid.UUID) (*GameClock, error) {
+	match, err := s.eventService.matchService.viewableMatch(viewer, matchID)
+	if err != nil {
+		return nil, err
+	}
+	return gameClock(match, time.Now()), nil
+}
+
+// StartPeriod starts the next period with a full, stopped clock. The first
+// period fixes the match's rules from its tournament or the server defaults.
+func (s *GameService) StartPeriod(actor *models.User, matchID uuid.UUID) (*GameClock, error) {
+	return s.change(actor, matchID, models.MatchEventPeriodStart, func(m *models.Match, now time.Time) error {
+		switch m.GameState {
+		case models.GameStatePreGame:
+			if err := s.applyRules(m); err != nil {
+				return err
+			}
+		case models.GameStateBreak, models.GameStateHalftime:
+		default:
+			return ErrInvalidGameTransition
+		}
+		m.Period++
+		m.GameState = models.GameStatePeriod
+		m.ClockRemainingMs = m.CurrentPeriodLength().Milliseconds()
+		m.ClockStartedAt = nil
+		return nil
+	})
+}
+
+// EndPeriod ends a period whose clock has run out. After the last regular
+// period or an overtime the game is final unless it is tied, in which case
+// overtime follows; a final game completes the match.
+func (s *GameService) EndPeriod(actor *models.User, matchID uuid.UUID, actx AuditContext) (*GameClock, error) {
+	var before models.Match
+	match, err := s.eventService.scoringMatch(actor, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ended *models.Match
+	event := &models.MatchEvent{Type: models.MatchEventQuarterEnd, RecordedBy: &actor.ID}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		m, err := s.eventService.appendEvent(tx, match.ID, event, func(m *models.Match) error {
+			if m.GameState != models.GameStatePeriod {
+				return ErrInvalidGameTransition
+			}
+			now := time.Now()
+			if m.ClockRemaining(now) > 0 {
+				return ErrPeriodNotOver
+			}
+			before = *m
+			m.StopClock(now)
+
+			switch {
+			case m.Period < m.PeriodCount && m.PeriodCount%2 == 0 && m.Period == m.PeriodCount/2:
+				m.GameState = models.GameStateHalftime
+			case m.Period < m.PeriodCount || m.HomeScore == m.AwayScore:
+				m.GameState = models.GameStateBreak
+			default:
+				finishMatch(m)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		ended = m
+		if m.GameState != models.GameStateFinal {
+			return nil
+		}
+
+		if err := advanceBracket(s.bracketRepo.WithTx(tx), m); err != nil {
+			return err
+		}
+		if err := finalizeStatistics(s.eventService.eventRepo.WithTx(tx), s.eventService.statsRepo.WithTx(tx), m); err != nil {
+			return err
+		}
+		if m.TournamentID != nil {
+			err := rebuildStandings(s.tournamentRepo.WithTx(tx), s.eventService.matchRepo.WithTx(tx), s.standingRepo.WithTx(tx), *m.TournamentID)
+			if err != nil {
+				return err
+			}
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionMatchUpdate,
+			TargetType: models.AuditTargetMatch,
+			TargetID:   &m.ID,
+			Before:     &before,
+			After:      m,
+		})
+	})
+	if err != nil {
+		return nil, gameError(err, "failed to end period")
+	}
+	if ended.IsCompleted() {
+		leaderboards.invalidate(ended)
+	}
+	publishEvent(ended, event)
+	return gameClock(ended, time.Now()), nil
+}
+
+// StartClock runs the game clock of the current period
+func (s *GameService) StartClock(actor *models.User, matchID uuid.UUID) (*GameClock, error) {
+	return s.change(actor, matchID, models.MatchEventClockStart, func(m *models.Match, now time.Time) error {
+		switch {
+		case m.GameState != models.GameStatePeriod:
+			return ErrInvalidGameTransition
+		case m.ClockRunning():
+			return ErrClockRunning
+		case m.ClockRemaining(now) == 0:
+			return ErrClockExpired
+		}
+		m.StartClock(now)
+		return nil
+	})
+}
+
+// StopClock stops the game clock at the time left
+func (s *GameService) StopClock(actor *models.User, matchID uuid.UUID) (*GameClock, error) {
+	return s.change(actor, matchID, models.MatchEventClockStop, func(m *models.Match, now time.Time) error {
+		if !m.ClockRunning() {
+			return ErrClockNotRunning
+		}
+		m.StopClock(now)
+		return nil
+	})
+}
+
+// SetClock corrects the time left on a stopped clock
+func (s *GameService) SetClock(actor *models.User, matchID uuid.UUID, req SetClockRequest) (*GameClock, error) {
+	remaining, err := parseClock(req.Remaining)
+	if err != nil {
+		return nil, err
+	}
+	return s.change(actor, matchID, models.MatchEventClockSet, func(m *models.Match, now time.Time) error {
+		switch {
+		case m.GameState != models.GameStatePeriod:
+			return ErrInvalidGameTransition
+		case m.ClockRunning():
+			return ErrClockRunning
+		case remaining > m.CurrentPeriodLength():
+			return ErrInvalidClock
+		}
+		m.ClockRemainingMs = remaining.Milliseconds()
+		return nil
+	})
+}
+
+// change applies a game state change to a live match the actor may score and
+// logs it as an event of the given type
+func (s *GameService) change(actor *models.User, matchID uuid.UUID, eventType models.MatchEventType, apply func(*models.Match, time.Time) error) (*GameClock, error) {
+	match, err := s.eventService.scoringMatch(actor, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed *models.Match
+	event := &models.MatchEvent{Type: eventType, RecordedBy: &actor.ID}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		m, err := s.eventService.appendEvent(tx, match.ID, event, func(m *models.Match) error {
+			return apply(m, time.Now())
+		})
+		changed = m
+		return err
+	})
+	if err != nil {
+		return nil, gameError(err, "failed to update game state")
+	}
+	publishEvent(changed, event)
+	return gameClock(changed, time.Now()), nil
+}
+
+// applyRules fixes the period count and lengths of a match from its
+// tournament, falling back to the server defaults
+func (s *GameService) applyRules(m *models.Match) error {
+	count := config.AppConfig.GamePeriodCount
+	length := config.AppConfig.GamePeriodLength
+	overtime := config.AppConfig.GameOvertimeLength
+
+	if m.TournamentID != nil {
+		tournament, err := s.tournamentRepo.GetByID(*m.TournamentID)
+		if err != nil {
+			return ErrTournamentNotFound
+		}
+		if tournament.PeriodCount != nil {
+			count = *tournament.PeriodCount
+		}
+		if tournament.PeriodMinutes != nil {
+			length = time.Duration(*tournament.PeriodMinutes) * time.Minute
+		}
+		if tournament.OvertimeMinutes != nil {
+			overtime = time.Duration(*tournament.OvertimeMinutes) * time.Minute
+		}
+	}
+
+	m.PeriodCount = count
+	m.PeriodLength = int(length / time.Second)
+	m.OvertimeLength = int(overtime / time.Second)
+	return nil
+}
+
+// finishMatch ends the game and completes the match with its winner
+func finishMatch(m *models.Match) {
+	m.GameState = models.GameStateFinal
+	m.ClockStartedAt = nil
+	m.Status = models.MatchStatusCompleted
+	winner := m.HomeTeamID
+	if m.AwayScore > m.HomeScore {
+		winner = m.AwayTeamID
+	}
+	m.WinnerTeamID = &winner
+}
+
+// gameClock reads the game state of a match at now
+func gameClock(m *models.Match, now time.Time) *GameClock {
+	remaining := m.ClockRemaining(now)
+	return &GameClock{
+		MatchID:          m.ID,
+		Status:           m.Status,
+		State:            m.GameState,
+		Period:           m.Period,
+		PeriodLabel:      m.PeriodLabel(),
+		PeriodCount:      m.PeriodCount,
+		PeriodLength:     m.PeriodLength,
+		OvertimeLength:   m.OvertimeLength,
+		ClockRunning:     m.ClockRunning(),
+		ClockRemainingMs: remaining.Milliseconds(),
+		Clock:            models.FormatClock(remaining),
+		HomeScore:        m.HomeScore,
+		AwayScore:        m.AwayScore,
+		Sequence:         m.EventSequence,
+		ServerTime:       now.UTC(),
+	}
+}
+
+// parseClock parses M:SS, M:SS.t or seconds with tenths
+func parseClock(value string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > 2 {
+		return 0, ErrInvalidClock
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	// ParseFloat takes "NaN" and "Inf" too, which no duration can hold
+	if err != nil || seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, ErrInvalidClock
+	}
+	if len(parts) == 2 {
+		minutes, err := strconv.Atoi(parts[0])
+		if err != nil || minutes < 0 || seconds >= 60 {
+			return 0, ErrInvalidClock
+		}
+		seconds += float64(minutes) * 60
+	}
+	if seconds >= float64(math.MaxInt64/time.Second) {
+		return 0, ErrInvalidClock
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(100 * time.Millisecond), nil
+}
+
+// gameError keeps the game state errors callers can act on and hides the rest
+func gameError(err error, message string) error {
+	for _, known := range []error{
+		ErrMatchNotLive, ErrInvalidGameTransition, ErrClockRunning, ErrClockNotRunning,
+		ErrClockExpired, ErrPeriodNotOver, ErrInvalidClock, ErrTournamentNotFound, ErrBracketLocked,
+	} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	cases := []struct {
+		value string
+		want  time.Duration
+		err   error
+	}{
+		{"10:00", 10 * time.Minute, nil},
+		{"4:32.5", 4*time.Minute + 32500*time.Millisecond, nil},
+		{" 0:07 ", 7 * time.Second, nil},
+		{"45.25", 45300 * time.Millisecond, nil},
+		{"0", 0, nil},
+		{"12:60", 0, ErrInvalidClock},
+		{"-1", 0, ErrInvalidClock},
+		{"-1:30", 0, ErrInvalidClock},
+		{"1:2:3", 0, ErrInvalidClock},
+		{"ten", 0, ErrInvalidClock},
+		{"", 0, ErrInvalidClock},
+		{"NaN", 0, ErrInvalidClock},
+		{"1:NaN", 0, ErrInvalidClock},
+		{"Inf", 0, ErrInvalidClock},
+		{"+Inf", 0, ErrInvalidClock},
+		{"1e300", 0, ErrInvalidClock},
+		{"9223372036854775807:00", 0, ErrInvalidClock},
+	}
+	for _, c := range cases {
+		got, err := parseClock(c.value)
+		if got != c.want || !errors.Is(err, c.err) {
+			t.Errorf("%q: got %v, %v, want %v, %v", c.value, got, err, c.want, c.err)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"echo-golang/internal/analytics"
+	"echo-golang/internal/config"
+	"echo-golang/internal/live"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownLeaderStat = errors.New("unknown leaderboard stat")
+)
+
+// Leaderboard modes
+const (
+	LeaderModePerGame = "per_game"
+	LeaderModeTotal   = "total"
+)
+
+// leaderStat is a stat players and teams can be ranked by. Percentage stats
+// have a shots function giving the makes and attempts they are measured on;
+// they are always computed over the totals.
+type leaderStat struct {
+	total func(l models.StatLine) float64
+	shots func(l models.StatLine) (made, attempted int)
+	pct   func(l models.StatLine) *float64
+}
+
+func counting(total func(l models.StatLine) int) leaderStat {
+	return leaderStat{total: func(l models.StatLine) float64 { return float64(total(l)) }}
+}
+
+var leaderStats = map[string]leaderStat{
+	"points":                   counting(func(l models.StatLine) int { return l.Points }),
+	"rebounds":                 counting(func(l models.StatLine) int { return l.Rebounds }),
+	"offensive_rebounds":       counting(func(l models.StatLine) int { return l.OffensiveRebounds }),
+	"defensive_rebounds":       counting(func(l models.StatLine) int { return l.DefensiveRebounds }),
+	"assists":                  counting(func(l models.StatLine) int { return l.Assists }),
+	"steals":                   counting(func(l models.StatLine) int { return l.Steals }),
+	"blocks":                   counting(func(l models.StatLine) int { return l.Blocks }),
+	"turnovers":                counting(func(l models.StatLine) int { return l.Turnovers }),
+	"fouls":                    counting(func(l models.StatLine) int { return l.Fouls }),
+	"plus_minus":               counting(func(l models.StatLine) int { return l.PlusMinus }),
+	"efficiency":               counting(analytics.Efficiency),
+	"field_goals_made":         counting(func(l models.StatLine) int { return l.FieldGoalsMade }),
+	"field_goals_attempted":    counting(func(l models.StatLine) int { return l.FieldGoalsAttempted }),
+	"three_pointers_made":      counting(func(l models.StatLine) int { return l.ThreePointersMade }),
+	"three_pointers_attempted": counting(func(l models.StatLine) int { return l.ThreePointersAttempted }),
+	"free_throws_made":         counting(func(l models.StatLine) int { return l.FreeThrowsMade }),
+	"free_throws_attempted":    counting(func(l models.StatLine) int { return l.FreeThrowsAttempted }),
+	"minutes":                  {total: func(l models.StatLine) float64 { return float64(l.SecondsPlayed) / 60 }},
+	"field_goal_pct": {
+		shots: func(l models.StatLine) (int, int) { return l.FieldGoalsMade, l.FieldGoalsAttempted },
+		pct:   func(l models.StatLine) *float64 { return percentage(l.FieldGoalsMade, l.FieldGoalsAttempted) },
+	},
+	"three_point_pct": {
+		shots: func(l models.StatLine) (int, int) { return l.ThreePointersMade, l.ThreePointersAttempted },
+		pct:   func(l models.StatLine) *float64 { return percentage(l.ThreePointersMade, l.ThreePointersAttempted) },
+	},
+	"free_throw_pct": {
+		shots: func(l models.StatLine) (int, int) { return l.FreeThrowsMade, l.FreeThrowsAttempted },
+		pct:   func(l models.StatLine) *float64 { return percentage(l.FreeThrowsMade, l.FreeThrowsAttempted) },
+	},
+	"effective_field_goal_pct": {
+		shots: func(l models.StatLine) (int, int) { return l.FieldGoalsMade, l.FieldGoalsAttempted },
+		pct:   analytics.EffectiveFieldGoalPct,
+	},
+	"true_shooting_pct": {
+		shots: func(l models.StatLine) (int, int) { return l.FieldGoalsMade, l.FieldGoalsAttempted },
+		pct:   analytics.TrueShootingPct,
+	},
+}
+
+// IsLeaderStat reports whether players and teams can be ranked by a stat
+func IsLeaderStat(stat string) bool {
+	_, ok := leaderStats[stat]
+	return ok
+}
+
+// LeaderboardQuery selects a leaderboard: the stat, whether players or teams
+// are ranked, the games that count and who qualifies
+type LeaderboardQuery struct {
+	Stat           string
+	Mode           string // per_game or total; percentages ignore it
+	SubjectType    string // player or team
+	TournamentID   *uuid.UUID
+	OrganizationID *uuid.UUID
+	Season         int
+	MinGames       int
+	MinAttempts    int // Percentage stats only
+}
+
+// key identifies the query's leaderboard in the cache
+func (q LeaderboardQuery) key() string {
+	id := func(u *uuid.UUID) string {
+		if u == nil {
+			return ""
+		}
+		return u.String()
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%d|%d|%d",
+		q.Stat, q.Mode, q.SubjectType, id(q.TournamentID), id(q.OrganizationID), q.Season, q.MinGames, q.MinAttempts)
+}
+
+// covers reports whether a match's statistics count towards the query's
+// leaderboard. The match's teams give its organizations; without them every
+// organization is assumed.
+func (q LeaderboardQuery) covers(match *models.Match) bool {
+	if q.TournamentID != nil && (match.TournamentID == nil || *match.TournamentID != *q.TournamentID) {
+		return false
+	}
+	if q.Season != 0 && match.ScheduledAt.UTC().Year() != q.Season {
+		return false
+	}
+	if q.OrganizationID != nil && match.HomeTeam != nil && match.AwayTeam != nil {
+		return match.HomeTeam.OrganizationID == *q.OrganizationID || match.AwayTeam.OrganizationID == *q.OrganizationID
+	}
+	return true
+}
+
+// Leader is one ranked player or team. Players that share a value share a
+// rank, and the next rank skips as many places.
+type Leader struct {
+	Rank         int        `json:"rank"`
+	Tied         bool       `json:"tied"`
+	SubjectID    uuid.UUID  `json:"subject_id"`
+	Name         string     `json:"name"`
+	JerseyNumber *int       `json:"jersey_number,omitempty"` // Players only
+	TeamID       *uuid.UUID `json:"team_id,omitempty"`       // Players only, their current team
+	Games        int        `json:"games"`
+	Value        float64    `json:"value"`
+	Made         *int       `json:"made,omitempty"`      // Percentage stats only
+	Attempted    *int       `json:"attempted,omitempty"` // Percentage stats only
+}
+
+// Leaderboard is a page of a leaderboard
+type Leaderboard struct {
+	Stat           string     `json:"stat"`
+	Mode           string     `json:"mode"`
+	SubjectType    string     `json:"subject_type"`
+	TournamentID   *uuid.UUID `json:"tournament_id,omitempty"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	Season         int        `json:"season,omitempty"`
+	MinGames       int        `json:"min_games"`
+	MinAttempts    int        `json:"min_attempts,omitempty"`
+	Leaders        []Leader   `json:"leaders"`
+	Total          int        `json:"total"`
+	Page           int        `json:"page"`
+	Limit          int        `json:"limit"`
+}
+
+type LeaderboardService struct {
+	statsRepo  *repositories.StatisticsRepository
+	playerRepo *repositories.PlayerRepository
+	teamRepo   *repositories.TeamRepository
+}
+
+func NewLeaderboardService() *LeaderboardService {
+	return &LeaderboardService{
+		statsRepo:  repositories.NewStatisticsRepository(),
+		playerRepo: repositories.NewPlayerRepository(),
+		teamRepo:   repositories.NewTeamRepository(),
+	}
+}
+
+// GetLeaderboard gets a page of a leaderboard over completed matches
+func (s *LeaderboardService) GetLeaderboard(q LeaderboardQuery, page, limit, offset int) (*Leaderboard, error) {
+	stat, ok := leaderStats[q.Stat]
+	if !ok {
+		return nil, ErrUnknownLeaderStat
+	}
+	if stat.pct != nil {
+		q.Mode = LeaderModeTotal
+	} else {
+		q.MinAttempts = 0
+	}
+	if q.MinGames < 1 {
+		q.MinGames = 1
+	}
+
+	leaders, ok := leaderboards.get(q)
+	if !ok {
+		// Taken before ranking, so a board that was invalidated meanwhile
+		// isn't cached
+		generation := leaderboards.current()
+		var err error
+		if leaders, err = s.rank(q, stat); err != nil {
+			return nil, err
+		}
+		leaderboards.put(q, leaders, generation)
+	}
+
+	board := &Leaderboard{
+		Stat:           q.Stat,
+		Mode:           q.Mode,
+		SubjectType:    q.SubjectType,
+		TournamentID:   q.TournamentID,
+		OrganizationID: q.OrganizationID,
+		Season:         q.Season,
+		MinGames:       q.MinGames,
+		MinAttempts:    q.MinAttempts,
+		Leaders:        []Leader{},
+		Total:          len(leaders),
+		Page:           page,
+		Limit:          limit,
+	}
+	if offset < len(leaders) {
+		board.Leaders = leaders[offset:min(offset+limit, len(leaders))]
+	}
+	return board, nil
+}
+
+// rank computes the whole leaderboard of a query: the qualifying players or
+// teams sorted by value, ties by name
+func (s *LeaderboardService) rank(q LeaderboardQuery, stat leaderStat) ([]Leader, error) {
+	filters := map[string]interface{}{}
+	if q.TournamentID != nil {
+		filters["tournament_id"] = *q.TournamentID
+	}
+	if q.OrganizationID != nil {
+		filters["organization_id"] = *q.OrganizationID
+	}
+	if q.Season != 0 {
+		filters["season"] = q.Season
+	}
+	aggs, err := s.statsRepo.SumBySubject(q.SubjectType, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var leaders []Leader
+	for _, agg := range aggs {
+		if agg.Games < q.MinGames {
+			continue
+		}
+		leader := Leader{SubjectID: agg.SubjectID, Games: agg.Games}
+		if stat.pct != nil {
+			made, attempted := stat.shots(agg.StatLine)
+			value := stat.pct(agg.StatLine)
+			if value == nil || attempted < q.MinAttempts {
+				continue
+			}
+			leader.Value, leader.Made, leader.Attempted = *value, &made, &attempted
+		} else {
+			total := stat.total(agg.StatLine)
+			if q.Mode == LeaderModePerGame {
+				total /= float64(agg.Games)
+			}
+			leader.Value = math.Round(total*10) / 10
+		}
+		leaders = append(leaders, leader)
+	}
+	if err := s.name(q.SubjectType, leaders); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(leaders, func(i, j int) bool {
+		if leaders[i].Value != leaders[j].Value {
+			return leaders[i].Value > leaders[j].Value
+		}
+		return leaders[i].Name < leaders[j].Name
+	})
+	for i := range leaders {
+		leaders[i].Rank = i + 1
+		if i > 0 && leaders[i].Value == leaders[i-1].Value {
+			leaders[i].Rank = leaders[i-1].Rank
+			leaders[i].Tied, leaders[i-1].Tied = true, true
+		}
+	}
+	return leaders, nil
+}
+
+// name fills in the names of ranked players or teams, and the jersey number
+// and current team of players
+func (s *LeaderboardService) name(subjectType string, leaders []Leader) error {
+	ids := make([]uuid.UUID, len(leaders))
+	for i := range leaders {
+		ids[i] = leaders[i].SubjectID
+	}
+
+	if subjectType == models.StatSubjectTeam {
+		teams, err := s.teamRepo.ListByIDs(ids)
+		if err != nil {
+			return err
+		}
+		names := map[uuid.UUID]string{}
+		for _, t := range teams {
+			names[t.ID] = t.Name
+		}
+		for i := range leaders {
+			leaders[i].Name = names[leaders[i].SubjectID]
+		}
+		return nil
+	}
+
+	players, err := s.playerRepo.ListByIDs(ids)
+	if err != nil {
+		return err
+	}
+	byID := map[uuid.UUID]*models.Player{}
+	for i := range players {
+		byID[players[i].ID] = &players[i]
+	}
+	for i := range leaders {
+		if p, ok := byID[leaders[i].SubjectID]; ok {
+			jersey, team := p.JerseyNumber, p.TeamID
+			leaders[i].Name, leaders[i].JerseyNumber, leaders[i].TeamID = p.FullName, &jersey, &team
+		}
+	}
+	return nil
+}
+
+// leaderboards caches whole leaderboards by query. Entries expire after the
+// configured TTL and are dropped as soon as a match they cover completes on
+// any instance.
+var leaderboards = &leaderboardCache{entries: map[string]cachedLeaderboard{}}
+
+// leaderboardsTopic carries the leaderboard invalidations of every instance
+const leaderboardsTopic = "leaderboards"
+
+type cachedLeaderboard struct {
+	query   LeaderboardQuery
+	leaders []Leader
+	expires time.Time
+}
+
+type leaderboardCache struct {
+	mu         sync.Mutex
+	entries    map[string]cachedLeaderboard
+	generation uint64 // Bumped by every invalidation
+}
+
+func (c *leaderboardCache) get(q LeaderboardQuery) ([]Leader, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[q.key()]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.leaders, true
+}
+
+// current is the generation to put a leaderboard ranked from now on with
+func (c *leaderboardCache) current() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// put caches a leaderboard ranked at a generation, unless an invalidation
+// came since. Expired entries are evicted, and the one expiring first when
+// the cache is full.
+func (c *leaderboardCache) put(q LeaderboardQuery, leaders []Leader, generation uint64) {
+	ttl, size := config.AppConfig.LeaderboardCacheTTL, config.AppConfig.LeaderboardCacheSize
+	if ttl <= 0 || size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+
+	now := time.Now()
+	key := q.key()
+	oldest := ""
+	for k, entry := range c.entries {
+		switch {
+		case now.After(entry.expires):
+			delete(c.entries, k)
+		case k != key && (oldest == "" || entry.expires.Before(c.entries[oldest].expires)):
+			oldest = k
+		}
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= size {
+		delete(c.entries, oldest)
+	}
+	c.entries[key] = cachedLeaderboard{query: q, leaders: leaders, expires: now.Add(ttl)}
+}
+
+// invalidate drops the leaderboards a match's statistics count towards, here
+// and, through the live broker, on every other instance
+func (c *leaderboardCache) invalidate(match *models.Match) {
+	c.drop(match)
+	if live.DefaultBroker == nil {
+		return
+	}
+	scope := leaderboardScope{TournamentID: match.TournamentID, ScheduledAt: match.ScheduledAt}
+	if match.HomeTeam != nil && match.AwayTeam != nil {
+		scope.OrganizationIDs = []uuid.UUID{match.HomeTeam.OrganizationID, match.AwayTeam.OrganizationID}
+	}
+	payload, err := json.Marshal(scope)
+	if err == nil {
+		err = live.DefaultBroker.Publish(live.Message{Topic: leaderboardsTopic, Payload: payload})
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to publish leaderboard invalidation for match %s: %v", match.ID, err)
+	}
+}
+
+// drop removes the leaderboards a match covers, and expired ones
+func (c *leaderboardCache) drop(match *models.Match) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	now := time.Now()
+	for key, entry := range c.entries {
+		if entry.query.covers(match) || now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// clear removes every leaderboard
+func (c *leaderboardCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = map[string]cachedLeaderboard{}
+}
+
+// leaderboardScope is what an invalidation carries of the completed match:
+// enough for LeaderboardQuery.covers
+type leaderboardScope struct {
+	TournamentID    *uuid.UUID  `json:"tournament_id,omitempty"`
+	ScheduledAt     time.Time   `json:"scheduled_at"`
+	OrganizationIDs []uuid.UUID `json:"organization_ids,omitempty"` // Of the home and away teams, when known
+}
+
+func (s leaderboardScope) match() *models.Match {
+	match := &models.Match{TournamentID: s.TournamentID, ScheduledAt: s.ScheduledAt}
+	if len(s.OrganizationIDs) == 2 {
+		match.HomeTeam = &models.Team{OrganizationID: s.OrganizationIDs[0]}
+		match.AwayTeam = &models.Team{OrganizationID: s.OrganizationIDs[1]}
+	}
+	return match
+}
+
+// WatchLeaderboards drops the cached leaderboards invalidated on any instance
+// as the broker relays them, until the broker closes. Run it once the broker
+// is set up.
+func WatchLeaderboards(broker live.Broker) {
+	for {
+		sub := broker.Subscribe(leaderboardsTopic)
+		for msg := range sub.C {
+			var scope leaderboardScope
+			if err := json.Unmarshal(msg.Payload, &scope); err != nil {
+				log.Printf("Warning: Dropped malformed leaderboard invalidation: %v", err)
+				continue
+			}
+			leaderboards.drop(scope.match())
+		}
+		if !sub.Dropped() {
+			return
+		}
+		// Invalidations may have been missed while falling behind
+		leaderboards.clear()
+	}
+}
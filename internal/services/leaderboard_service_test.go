@@ -0,0 +1,130 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"echo-golang/internal/config"
+	"echo-golang/internal/live"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// withLeaderboardCache gives a test an empty cache of the given size
+func withLeaderboardCache(t *testing.T, size int) {
+	t.Helper()
+	saved, savedCache := config.AppConfig, leaderboards
+	config.AppConfig = &config.Config{LeaderboardCacheTTL: time.Minute, LeaderboardCacheSize: size}
+	leaderboards = &leaderboardCache{entries: map[string]cachedLeaderboard{}}
+	t.Cleanup(func() { config.AppConfig, leaderboards = saved, savedCache })
+}
+
+func TestLeaderboardCacheGeneration(t *testing.T) {
+	withLeaderboardCache(t, 10)
+	tournament := uuid.New()
+	q := LeaderboardQuery{Stat: "points", Mode: LeaderModePerGame, SubjectType: "player", TournamentID: &tournament}
+
+	// A board ranked before an invalidation isn't cached
+	generation := leaderboards.current()
+	leaderboards.drop(&models.Match{TournamentID: &tournament})
+	leaderboards.put(q, []Leader{{Rank: 1}}, generation)
+	if _, ok := leaderboards.get(q); ok {
+		t.Error("stale leaderboard cached after an invalidation")
+	}
+
+	leaderboards.put(q, []Leader{{Rank: 1}}, leaderboards.current())
+	if _, ok := leaderboards.get(q); !ok {
+		t.Fatal("leaderboard not cached")
+	}
+	other := uuid.New()
+	leaderboards.drop(&models.Match{TournamentID: &other})
+	if _, ok := leaderboards.get(q); !ok {
+		t.Error("leaderboard dropped by a match of another tournament")
+	}
+	leaderboards.drop(&models.Match{TournamentID: &tournament})
+	if _, ok := leaderboards.get(q); ok {
+		t.Error("leaderboard kept after a match it covers completed")
+	}
+}
+
+func TestLeaderboardCacheEviction(t *testing.T) {
+	withLeaderboardCache(t, 3)
+	queries := make([]LeaderboardQuery, 4)
+	for i := range queries {
+		queries[i] = LeaderboardQuery{Stat: "points", Mode: LeaderModeTotal, SubjectType: "team", Season: 2020 + i}
+	}
+
+	expired := LeaderboardQuery{Stat: "assists", Mode: LeaderModeTotal, SubjectType: "team"}
+	leaderboards.entries[expired.key()] = cachedLeaderboard{query: expired, expires: time.Now().Add(-time.Second)}
+	for _, q := range queries {
+		leaderboards.put(q, nil, leaderboards.current())
+	}
+
+	if n := len(leaderboards.entries); n != 3 {
+		t.Errorf("got %d entries, want 3", n)
+	}
+	if _, ok := leaderboards.entries[expired.key()]; ok {
+		t.Error("expired entry kept")
+	}
+	if _, ok := leaderboards.get(queries[0]); ok {
+		t.Error("entry expiring first kept in a full cache")
+	}
+	for _, q := range queries[1:] {
+		if _, ok := leaderboards.get(q); !ok {
+			t.Errorf("season %d evicted", q.Season)
+		}
+	}
+	// Replacing an entry of a full cache evicts nothing
+	leaderboards.put(queries[3], nil, leaderboards.current())
+	if _, ok := leaderboards.get(queries[1]); !ok {
+		t.Error("entry evicted by a replacement")
+	}
+}
+
+func TestWatchLeaderboards(t *testing.T) {
+	withLeaderboardCache(t, 10)
+	saved := live.DefaultBroker
+	hub := live.NewHub(4)
+	live.DefaultBroker = hub
+	t.Cleanup(func() { live.DefaultBroker = saved })
+
+	done := make(chan struct{})
+	go func() {
+		WatchLeaderboards(hub)
+		close(done)
+	}()
+	for hub.Subscribers(leaderboardsTopic) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	org, otherOrg := uuid.New(), uuid.New()
+	q := LeaderboardQuery{Stat: "points", Mode: LeaderModePerGame, SubjectType: "player", OrganizationID: &org}
+	leaderboards.put(q, nil, leaderboards.current())
+
+	// Another instance's invalidation arrives through the broker
+	match := &models.Match{
+		ScheduledAt: time.Date(2026, time.March, 7, 18, 0, 0, 0, time.UTC),
+		HomeTeam:    &models.Team{OrganizationID: otherOrg},
+		AwayTeam:    &models.Team{OrganizationID: org},
+	}
+	generation := leaderboards.current()
+	leaderboards.invalidate(match)
+	deadline := time.Now().Add(time.Second)
+	for leaderboards.current() < generation+2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := leaderboards.current(); got != generation+2 {
+		t.Errorf("generation %d, want the local and relayed drops to bump it to %d", got, generation+2)
+	}
+	if _, ok := leaderboards.get(q); ok {
+		t.Error("leaderboard kept after an invalidation")
+	}
+
+	hub.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("watcher still running after the broker closed")
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"echo-golang/internal/analytics"
+	"echo-golang/internal/lineups"
+	"echo-golang/internal/models"
+	"echo-golang/internal/oncourt"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidLineupSize = errors.New("size must be 2, 3 or 5")
+)
+
+type LineupService struct {
+	matchRepo    *repositories.MatchRepository
+	eventRepo    *repositories.MatchEventRepository
+	playerRepo   *repositories.PlayerRepository
+	teamRepo     *repositories.TeamRepository
+	matchService *MatchService
+}
+
+func NewLineupService() *LineupService {
+	return &LineupService{
+		matchRepo:    repositories.NewMatchRepository(),
+		eventRepo:    repositories.NewMatchEventRepository(),
+		playerRepo:   repositories.NewPlayerRepository(),
+		teamRepo:     repositories.NewTeamRepository(),
+		matchService: NewMatchService(),
+	}
+}
+
+// LineupQuery selects the combinations reported and, for a team, the matches
+// they are totalled over
+type LineupQuery struct {
+	Size         int // Players per combination: 5 for full lineups, 2 or 3
+	TeamID       *uuid.UUID
+	TournamentID *uuid.UUID
+	Season       int
+	MinMinutes   int
+}
+
+// Validate checks the combination size, defaulting to full lineups
+func (q *LineupQuery) Validate() error {
+	if q.Size == 0 {
+		q.Size = oncourt.Size
+	}
+	if q.Size != 2 && q.Size != 3 && q.Size != oncourt.Size {
+		return ErrInvalidLineupSize
+	}
+	return nil
+}
+
+type LineupPlayer struct {
+	PlayerID     uuid.UUID `json:"player_id"`
+	FullName     string    `json:"full_name"`
+	JerseyNumber int       `json:"jersey_number"`
+}
+
+// LineupStats is how a combination of players did while on court together
+type LineupStats struct {
+	Players         []LineupPlayer `json:"players"`
+	Matches         int            `json:"matches"`
+	Stints          int            `json:"stints"`
+	Seconds         int            `json:"seconds"`
+	Minutes         string         `json:"minutes"`
+	PointsFor       int            `json:"points_for"`
+	PointsAgainst   int            `json:"points_against"`
+	PlusMinus       int            `json:"plus_minus"`
+	Possessions     float64        `json:"possessions"`
+	OffensiveRating *float64       `json:"offensive_rating,omitempty"` // Points per 100 possessions
+	DefensiveRating *float64       `json:"defensive_rating,omitempty"`
+	NetRating       *float64       `json:"net_rating,omitempty"`
+}
+
+// LineupStint is a stretch of a match a team played with the same players
+type LineupStint struct {
+	PlayerIDs     []uuid.UUID `json:"player_ids"`
+	Complete      bool        `json:"complete"` // Exactly five players could be placed on court
+	StartSeconds  int         `json:"start_seconds"`
+	EndSeconds    int         `json:"end_seconds"`
+	PointsFor     int         `json:"points_for"`
+	PointsAgainst int         `json:"points_against"`
+}
+
+// TeamLineups is a team's lineups or smaller combinations, most minutes
+// first
+type TeamLineups struct {
+	TeamID            uuid.UUID     `json:"team_id"`
+	Name              string        `json:"name"`
+	Size              int           `json:"size"`
+	Matches           int           `json:"matches"`
+	UnresolvedSeconds int           `json:"unresolved_seconds"` // Time the events don't show exactly five players on court
+	Lineups           []LineupStats `json:"lineups"`
+	Stints            []LineupStint `json:"stints,omitempty"` // Single matches only
+}
+
+// MatchLineups is the lineups of both teams of a match, home first
+type MatchLineups struct {
+	MatchID uuid.UUID     `json:"match_id"`
+	Size    int           `json:"size"`
+	Teams   []TeamLineups `json:"teams"`
+}
+
+// GetMatchLineups reconstructs the stints of a match so far and totals the
+// combinations of each team, or only the query's team
+func (s *LineupService) GetMatchLineups(viewer *models.User, matchID uuid.UUID, q LineupQuery) (*MatchLineups, error) {
+	match, err := s.matchService.viewableMatch(viewer, matchID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.ListByMatch(match.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	stints := lineups.Stints(match, events)
+
+	result := &MatchLineups{MatchID: match.ID, Size: q.Size, Teams: []TeamLineups{}}
+	for _, teamID := range []uuid.UUID{match.HomeTeamID, match.AwayTeamID} {
+		if q.TeamID != nil && *q.TeamID != teamID {
+			continue
+		}
+		var own []lineups.Stint
+		for _, stint := range stints {
+			if stint.TeamID == teamID {
+				own = append(own, stint)
+			}
+		}
+		team, err := s.teamLineups(teamID, own, 1, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, stint := range own {
+			team.Stints = append(team.Stints, LineupStint{
+				PlayerIDs:     stint.Players,
+				Complete:      stint.Complete(),
+				StartSeconds:  int(stint.Start / time.Second),
+				EndSeconds:    int(stint.End / time.Second),
+				PointsFor:     stint.Line.Points,
+				PointsAgainst: stint.PointsAgainst,
+			})
+		}
+		result.Teams = append(result.Teams, *team)
+	}
+	return result, nil
+}
+
+// GetTeamLineups totals a team's combinations over its completed matches,
+// optionally of a tournament or season
+func (s *LineupService) GetTeamLineups(teamID uuid.UUID, q LineupQuery) (*TeamLineups, error) {
+	if _, err := s.teamRepo.GetByID(teamID); err != nil {
+		return nil, ErrTeamNotFound
+	}
+	filters := map[string]interface{}{"team_id": teamID, "status": models.MatchStatusCompleted}
+	if q.TournamentID != nil {
+		filters["tournament_id"] = *q.TournamentID
+	}
+	if q.Season > 0 {
+		filters["from"] = time.Date(q.Season, time.January, 1, 0, 0, 0, 0, time.UTC)
+		filters["to"] = time.Date(q.Season+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	matches, err := s.matchRepo.ListAll(filters)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(matches))
+	for i := range matches {
+		ids[i] = matches[i].ID
+	}
+	events, err := s.eventRepo.ListByMatches(ids)
+	if err != nil {
+		return nil, err
+	}
+	byMatch := map[uuid.UUID][]models.MatchEvent{}
+	for _, event := range events {
+		byMatch[event.MatchID] = append(byMatch[event.MatchID], event)
+	}
+
+	var own []lineups.Stint
+	for i := range matches {
+		for _, stint := range lineups.Stints(&matches[i], byMatch[matches[i].ID]) {
+			if stint.TeamID == teamID {
+				own = append(own, stint)
+			}
+		}
+	}
+	return s.teamLineups(teamID, own, len(matches), q)
+}
+
+// teamLineups totals the combinations of a team's stints and names their
+// players
+func (s *LineupService) teamLineups(teamID uuid.UUID, stints []lineups.Stint, matches int, q LineupQuery) (*TeamLineups, error) {
+	result := &TeamLineups{TeamID: teamID, Size: q.Size, Matches: matches, Lineups: []LineupStats{}}
+	if team, err := s.teamRepo.GetByID(teamID); err == nil {
+		result.Name = team.Name
+	}
+	for _, stint := range stints {
+		if !stint.Complete() {
+			result.UnresolvedSeconds += int((stint.End - stint.Start) / time.Second)
+		}
+	}
+
+	combos := lineups.Combine(stints, q.Size)
+	seen := map[uuid.UUID]bool{}
+	var playerIDs []uuid.UUID
+	for _, combo := range combos {
+		for _, id := range combo.Players {
+			if !seen[id] {
+				seen[id] = true
+				playerIDs = append(playerIDs, id)
+			}
+		}
+	}
+	players, err := s.playerRepo.ListByIDs(playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[uuid.UUID]*models.Player{}
+	for i := range players {
+		byID[players[i].ID] = &players[i]
+	}
+
+	for _, combo := range combos {
+		seconds := int(combo.Time / time.Second)
+		if seconds < q.MinMinutes*60 {
+			continue
+		}
+		stats := LineupStats{
+			Matches:       combo.Matches,
+			Stints:        combo.Stints,
+			Seconds:       seconds,
+			Minutes:       fmt.Sprintf("%d:%02d", seconds/60, seconds%60),
+			PointsFor:     combo.Line.Points,
+			PointsAgainst: combo.PointsAgainst,
+			PlusMinus:     combo.Line.Points - combo.PointsAgainst,
+		}
+		possessions := analytics.Possessions(combo.Line)
+		stats.Possessions = math.Round(possessions*10) / 10
+		if possessions > 0 {
+			stats.OffensiveRating = analytics.Rating(combo.Line.Points, possessions)
+			stats.DefensiveRating = analytics.Rating(combo.PointsAgainst, possessions)
+			net := math.Round((*stats.OffensiveRating-*stats.DefensiveRating)*10) / 10
+			stats.NetRating = &net
+		}
+		for _, id := range combo.Players {
+			player := LineupPlayer{PlayerID: id}
+			if p, ok := byID[id]; ok {
+				player.FullName, player.JerseyNumber = p.FullName, p.JerseyNumber
+			}
+			stats.Players = append(stats.Players, player)
+		}
+		sort.SliceStable(stats.Players, func(i, j int) bool {
+			return stats.Players[i].JerseyNumber < stats.Players[j].JerseyNumber
+		})
+		result.Lineups = append(result.Lineups, stats)
+	}
+	return result, nil
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"echo-golang/internal/live"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+	"echo-golang/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Live update types
+const (
+	LiveUpdateSnapshot = "snapshot" // Current game state when a client connects
+	LiveUpdateEvent    = "event"    // An event appended to the match's log
+	LiveUpdateMatch    = "match"    // A match change outside the event log, such as a status or score correction
+)
+
+// LiveUpdate is one message of a match's live feed. Game is the game state
+// after the update; it is left out of the events replayed on resume.
+type LiveUpdate struct {
+	Type     string             `json:"type"`
+	MatchID  uuid.UUID          `json:"match_id"`
+	Sequence int                `json:"sequence"`
+	Event    *models.MatchEvent `json:"event,omitempty"`
+	Game     *GameClock         `json:"game,omitempty"`
+}
+
+// LiveFeed is a client's view of a match's live updates: the events it
+// missed, a snapshot of the current game, then the subscription for what
+// follows. Close it when the client goes away.
+type LiveFeed struct {
+	Backlog      []live.Message
+	Snapshot     live.Message
+	Subscription *live.Subscription
+	Sequence     int // Event sequence delivered up to, with none missing
+
+	// Publishes race each other and Redis may reorder them, so an event can
+	// come before the one it follows; the events in between are read from
+	// the log and delivered first, and remembered here until Sequence
+	// reaches them
+	ahead  map[int]bool
+	missed func(after, before int) ([]live.Message, error)
+}
+
+// Deliver returns the messages to send for a message from the subscription:
+// none for an event the backlog, the snapshot or a gap fill already covered,
+// and for an event past a gap, the events missing before it first
+func (f *LiveFeed) Deliver(msg live.Message) []live.Message {
+	if msg.Sequence == 0 {
+		return []live.Message{msg}
+	}
+	if msg.Sequence <= f.Sequence || f.ahead[msg.Sequence] {
+		return nil
+	}
+
+	var out []live.Message
+	if msg.Sequence > f.Sequence+1 && f.missed != nil {
+		// A failed read leaves the gap to the missing events themselves,
+		// which are delivered late when they arrive
+		missing, err := f.missed(f.Sequence, msg.Sequence)
+		if err != nil {
+			log.Printf("Warning: Failed to read events missing from a live feed: %v", err)
+		}
+		for _, m := range missing {
+			if m.Sequence > f.Sequence && !f.ahead[m.Sequence] {
+				out = append(out, m)
+				f.delivered(m.Sequence)
+			}
+		}
+	}
+	f.delivered(msg.Sequence)
+	return append(out, msg)
+}
+
+func (f *LiveFeed) delivered(sequence int) {
+	if sequence != f.Sequence+1 {
+		if f.ahead == nil {
+			f.ahead = map[int]bool{}
+		}
+		f.ahead[sequence] = true
+		return
+	}
+	f.Sequence = sequence
+	for f.ahead[f.Sequence+1] {
+		delete(f.ahead, f.Sequence+1)
+		f.Sequence++
+	}
+}
+
+// Close stops the feed's subscription
+func (f *LiveFeed) Close() {
+	f.Subscription.Close()
+}
+
+type LiveService struct {
+	eventRepo    *repositories.MatchEventRepository
+	matchService *MatchService
+}
+
+func NewLiveService() *LiveService {
+	return &LiveService{
+		eventRepo:    repositories.NewMatchEventRepository(),
+		matchService: NewMatchService(),
+	}
+}
+
+// Follow opens the live feed of a match for a viewer. Clients resuming after
+// a disconnect pass the last sequence they saw to get the events they missed;
+// a negative after skips the backlog.
+func (s *LiveService) Follow(viewer *models.User, matchID uuid.UUID, after int) (*LiveFeed, error) {
+	if _, err := s.matchService.viewableMatch(viewer, matchID); err != nil {
+		return nil, err
+	}
+
+	// Subscribe before reading the snapshot so no update falls in between;
+	// updates the snapshot already covers are skipped by Deliver
+	sub := live.DefaultBroker.Subscribe(live.MatchTopic(matchID))
+	feed, err := s.open(matchID, after)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	feed.Subscription = sub
+	feed.missed = func(after, before int) ([]live.Message, error) {
+		return s.eventMessages(matchID, after, before)
+	}
+	return feed, nil
+}
+
+// StreamTicket is a ticket for a user to open the live feed of a match
+// through a URL, which browsers need for WebSockets and EventSource
+type StreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IssueStreamTicket issues a stream ticket for the live feed of a match the
+// user can view
+func (s *LiveService) IssueStreamTicket(user *models.User, matchID uuid.UUID) (*StreamTicket, error) {
+	if _, err := s.matchService.viewableMatch(user, matchID); err != nil {
+		return nil, err
+	}
+	ticket, expiresAt, err := utils.GenerateStreamTicket(user, matchID)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamTicket{Ticket: ticket, ExpiresAt: expiresAt}, nil
+}
+
+func (s *LiveService) open(matchID uuid.UUID, after int) (*LiveFeed, error) {
+	match, err := s.matchService.GetMatch(matchID)
+	if err != nil {
+		return nil, err
+	}
+	feed := &LiveFeed{Sequence: match.EventSequence}
+
+	if after >= 0 && after < match.EventSequence {
+		feed.Backlog, err = s.eventMessages(match.ID, after, match.EventSequence+1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	feed.Snapshot, err = liveMessage(LiveUpdate{
+		Type:     LiveUpdateSnapshot,
+		MatchID:  match.ID,
+		Sequence: match.EventSequence,
+		Game:     gameClock(match, time.Now()),
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// eventMessages reads the events of a match's log between two sequences,
+// exclusive, as live messages without the game state
+func (s *LiveService) eventMessages(matchID uuid.UUID, after, before int) ([]live.Message, error) {
+	events, err := s.eventRepo.ListByMatch(matchID, after)
+	if err != nil {
+		return nil, err
+	}
+	var messages []live.Message
+	for i := range events {
+		if events[i].Sequence >= before {
+			break
+		}
+		msg, err := liveMessage(LiveUpdate{
+			Type:     LiveUpdateEvent,
+			MatchID:  matchID,
+			Sequence: events[i].Sequence,
+			Event:    &events[i],
+		}, events[i].Sequence)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// publishEvent sends an event appended to a match's log, with the game state
+// it left, to the match's live feed. Call it once the event is committed.
+func publishEvent(match *models.Match, event *models.MatchEvent) {
+	publishLive(LiveUpdate{
+		Type:     LiveUpdateEvent,
+		MatchID:  match.ID,
+		Sequence: event.Sequence,
+		Event:    event,
+		Game:     gameClock(match, time.Now()),
+	}, event.Sequence)
+}
+
+// publishMatch sends the game state of a match changed outside its event log
+// to the match's live feed. Call it once the change is committed.
+func publishMatch(match *models.Match) {
+	publishLive(LiveUpdate{
+		Type:     LiveUpdateMatch,
+		MatchID:  match.ID,
+		Sequence: match.EventSequence,
+		Game:     gameClock(match, time.Now()),
+	}, 0)
+}
+
+// publishLive is best effort: the change is already committed, and clients
+// that miss the update catch up by event sequence
+func publishLive(update LiveUpdate, sequence int) {
+	if live.DefaultBroker == nil {
+		return
+	}
+	msg, err := liveMessage(update, sequence)
+	if err == nil {
+		err = live.DefaultBroker.Publish(msg)
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to publish live update for match %s: %v", update.MatchID, err)
+	}
+}
+
+func liveMessage(update LiveUpdate, sequence int) (live.Message, error) {
+	payload, err := json.Marshal(update)
+	if err != nil {
+		return live.Message{}, err
+	}
+	return live.Message{
+		Topic:    live.MatchTopic(update.MatchID),
+		Sequence: sequence,
+		Payload:  payload,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"echo-golang/internal/live"
+)
+
+func TestLiveFeedDeliver(t *testing.T) {
+	// The backlog and snapshot delivered events up to sequence 5, and the
+	// subscription, opened before them, replays some of those
+	failing := false
+	feed := &LiveFeed{Sequence: 5, missed: func(after, before int) ([]live.Message, error) {
+		if failing {
+			return nil, errors.New("connection lost")
+		}
+		var log []live.Message
+		for sequence := after + 1; sequence < before; sequence++ {
+			log = append(log, live.Message{Sequence: sequence})
+		}
+		return log, nil
+	}}
+	cases := []struct {
+		name     string
+		sequence int
+		failing  bool
+		want     []int
+	}{
+		{"covered by the backlog", 3, false, nil},
+		{"covered by the snapshot", 5, false, nil},
+		{"match update outside the log", 0, false, []int{0}},
+		{"next event", 6, false, []int{6}},
+		{"duplicate", 6, false, nil},
+		{"ahead of the previous event", 8, false, []int{7, 8}},
+		{"late event filled from the log", 7, false, nil},
+		{"another match update", 0, false, []int{0}},
+		{"after the gap", 9, false, []int{9}},
+		{"gap the log can't fill", 11, true, []int{11}},
+		{"late event delivered late", 10, true, []int{10}},
+		{"duplicate past the gap", 11, false, nil},
+		{"next after the gap closed", 12, false, []int{12}},
+	}
+	seen := map[int]int{}
+	for _, c := range cases {
+		failing = c.failing
+		var got []int
+		for _, msg := range feed.Deliver(live.Message{Sequence: c.sequence}) {
+			got = append(got, msg.Sequence)
+			seen[msg.Sequence]++
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: sequence %d delivered %v, want %v", c.name, c.sequence, got, c.want)
+		}
+	}
+	for sequence := 6; sequence <= 12; sequence++ {
+		if seen[sequence] != 1 {
+			t.Errorf("event %d delivered %d times, want once", sequence, seen[sequence])
+		}
+	}
+	if feed.Sequence != 12 {
+		t.Errorf("feed at sequence %d, want 12", feed.Sequence)
+	}
+}
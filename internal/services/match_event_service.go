@@ -0,0 +1,641 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/narration"
+	"echo-golang/internal/oncourt"
+	"echo-golang/internal/repositories"
+	"echo-golang/internal/shotchart"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMatchNotLive        = errors.New("events can only be recorded while the match is live")
+	ErrGameNotStarted      = errors.New("the first period has not started")
+	ErrInvalidEvent        = errors.New("event fields do not match its event_type")
+	ErrEventTeam           = errors.New("team does not play in this match")
+	ErrEventPlayer         = errors.New("player is not an active member of the team")
+	ErrAlreadyOnCourt      = oncourt.ErrAlreadyOnCourt
+	ErrNotOnCourt          = oncourt.ErrNotOnCourt
+	ErrTooManyOnCourt      = oncourt.ErrTooManyOnCourt
+	ErrShotLocation        = errors.New("shot_x and shot_y go together on two and three-point attempts, and shot_zone must match the shot's value")
+	ErrNoEventToUndo       = errors.New("there is no event to undo")
+	ErrScorekeeperExists   = errors.New("user is already a scorekeeper of this match")
+	ErrScorekeeperNotFound = errors.New("user is not a scorekeeper of this match")
+	ErrInactiveScorekeeper = errors.New("scorekeepers must be active users")
+)
+
+type MatchEventService struct {
+	matchRepo    *repositories.MatchRepository
+	eventRepo    *repositories.MatchEventRepository
+	playerRepo   *repositories.PlayerRepository
+	rosterRepo   *repositories.RosterRepository
+	userRepo     *repositories.UserRepository
+	statsRepo    *repositories.StatisticsRepository
+	matchService *MatchService
+	auditService *AuditService
+}
+
+func NewMatchEventService() *MatchEventService {
+	return &MatchEventService{
+		matchRepo:    repositories.NewMatchRepository(),
+		eventRepo:    repositories.NewMatchEventRepository(),
+		playerRepo:   repositories.NewPlayerRepository(),
+		rosterRepo:   repositories.NewRosterRepository(),
+		userRepo:     repositories.NewUserRepository(),
+		statsRepo:    repositories.NewStatisticsRepository(),
+		matchService: NewMatchService(),
+		auditService: NewAuditService(),
+	}
+}
+
+// RecordEventRequest is one live scoring event. Every event needs a team.
+// Made and missed shots also need a player and the shot's value (1 for a free
+// throw, 2 or 3), and made field goals may credit an assist. Steals and blocks
+// need a player, and substitutions the players entering and leaving. Two and
+// three-point attempts may carry where they were taken from, normalized to the
+// half court, or just the zone; the zone of a located shot is worked out by
+// the server. The period and clock come from the game state.
+type RecordEventRequest struct {
+	Type                string     `json:"event_type" binding:"required,oneof=point miss rebound steal block turnover foul timeout substitution"`
+	TeamID              uuid.UUID  `json:"team_id" binding:"required"`
+	PlayerID            *uuid.UUID `json:"player_id,omitempty"`
+	SubstitutedPlayerID *uuid.UUID `json:"substituted_player_id,omitempty"` // Player leaving the court
+	AssistPlayerID      *uuid.UUID `json:"assist_player_id,omitempty"`
+	Points              int        `json:"points,omitempty" binding:"omitempty,oneof=1 2 3"`
+	ShotX               *float64   `json:"shot_x,omitempty" binding:"omitempty,min=0,max=1"`
+	ShotY               *float64   `json:"shot_y,omitempty" binding:"omitempty,min=0,max=1"`
+	ShotZone            string     `json:"shot_zone,omitempty" binding:"omitempty,oneof=restricted_area paint mid_range left_corner_three right_corner_three above_break_three"`
+	Description         string     `json:"description,omitempty" binding:"max=500"`
+}
+
+type AssignScorekeeperRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
+
+// MatchEventLog is the event log of a match after a sequence number, with the
+// match's running score so clients can resync from any point
+type MatchEventLog struct {
+	MatchID   uuid.UUID           `json:"match_id"`
+	Status    models.MatchStatus  `json:"status"`
+	HomeScore int                 `json:"home_score"`
+	AwayScore int                 `json:"away_score"`
+	Sequence  int                 `json:"sequence"`
+	Events    []models.MatchEvent `json:"events"`
+}
+
+// ListEvents gets the events of a match recorded after a sequence number
+func (s *MatchEventService) ListEvents(viewer *models.User, matchID uuid.UUID, after int) (*MatchEventLog, error) {
+	match, err := s.matchService.viewableMatch(viewer, matchID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.ListByMatch(match.ID, after)
+	if err != nil {
+		return nil, err
+	}
+	return &MatchEventLog{
+		MatchID:   match.ID,
+		Status:    match.Status,
+		HomeScore: match.HomeScore,
+		AwayScore: match.AwayScore,
+		Sequence:  match.EventSequence,
+		Events:    events,
+	}, nil
+}
+
+// Play-by-play page sizes
+const (
+	DefaultPlayByPlayLimit = 25
+	MaxPlayByPlayLimit     = 100
+)
+
+// PlayByPlayQuery selects a page of a match's play-by-play. Before pages back
+// through older plays and After forward through newer ones; without either
+// the page holds the latest plays.
+type PlayByPlayQuery struct {
+	Before      int
+	After       int
+	Limit       int
+	ScoringOnly bool // Only made shots still in effect
+	PlayerID    *uuid.UUID
+	Language    narration.Language
+}
+
+// Play is one event of a match narrated for the play-by-play, with the score
+// once it is in
+type Play struct {
+	Sequence         int                   `json:"sequence"`
+	EventID          uuid.UUID             `json:"event_id"`
+	EventType        models.MatchEventType `json:"event_type"`
+	Period           int                   `json:"period"`
+	PeriodLabel      string                `json:"period_label"`
+	ClockRemainingMs int64                 `json:"clock_remaining_ms"`
+	TimeRemaining    string                `json:"time_remaining,omitempty"`
+	TeamID           *uuid.UUID            `json:"team_id,omitempty"`
+	PlayerID         *uuid.UUID            `json:"player_id,omitempty"`
+	Points           int                   `json:"points,omitempty"`
+	Voided           bool                  `json:"voided"`
+	Text             string                `json:"text"`
+	HomeScore        int                   `json:"home_score"`
+	AwayScore        int                   `json:"away_score"`
+	RecordedAt       time.Time             `json:"recorded_at"`
+}
+
+// PlayByPlay is a page of a match's play-by-play, newest first. Pass
+// older_cursor as before for the previous page and newer_cursor as after to
+// poll for plays recorded since.
+type PlayByPlay struct {
+	MatchID     uuid.UUID          `json:"match_id"`
+	Status      models.MatchStatus `json:"status"`
+	HomeScore   int                `json:"home_score"`
+	AwayScore   int                `json:"away_score"`
+	Language    narration.Language `json:"language"`
+	Plays       []Play             `json:"plays"`
+	HasOlder    bool               `json:"has_older"`
+	HasNewer    bool               `json:"has_newer"`
+	OlderCursor *int               `json:"older_cursor,omitempty"`
+	NewerCursor int                `json:"newer_cursor"`
+}
+
+// GetPlayByPlay narrates a page of the events of a match. The running score
+// of every play is worked out from the whole log, so filtering and paging
+// don't change it.
+func (s *MatchEventService) GetPlayByPlay(viewer *models.User, matchID uuid.UUID, q PlayByPlayQuery) (*PlayByPlay, error) {
+	match, err := s.matchService.viewableMatch(viewer, matchID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.ListByMatch(match.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	var playerIDs []uuid.UUID
+	for _, e := range events {
+		for _, id := range []*uuid.UUID{e.PlayerID, e.SubstitutedPlayerID, e.AssistPlayerID} {
+			if id != nil {
+				playerIDs = append(playerIDs, *id)
+			}
+		}
+	}
+	players, err := s.playerRepo.ListByIDs(playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.Player, len(players))
+	for _, p := range players {
+		byID[p.ID] = p
+	}
+	// Players are named by the number they wore in the match, which a
+	// transfer or a number change since may have replaced
+	numbers, err := jerseyNumbersAsOf(s.rosterRepo, playerIDs, dateOf(match.ScheduledAt))
+	if err != nil {
+		return nil, err
+	}
+	for id, number := range numbers {
+		if p, ok := byID[id]; ok {
+			p.JerseyNumber = number
+			byID[id] = p
+		}
+	}
+
+	var plays []narration.Play
+	for _, play := range narration.Narrate(match, events, byID, q.Language) {
+		e := play.Event
+		if q.ScoringOnly && (e.Type != models.MatchEventPoint || e.Voided) {
+			continue
+		}
+		if q.PlayerID != nil && !namesPlayer(e, *q.PlayerID) {
+			continue
+		}
+		plays = append(plays, play)
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = DefaultPlayByPlayLimit
+	}
+	from, to := len(plays)-q.Limit, len(plays)
+	switch {
+	case q.After > 0:
+		from = sort.Search(len(plays), func(i int) bool { return plays[i].Event.Sequence > q.After })
+		to = from + q.Limit
+	case q.Before > 0:
+		to = sort.Search(len(plays), func(i int) bool { return plays[i].Event.Sequence >= q.Before })
+		from = to - q.Limit
+	}
+	from, to = clamp(from, 0, len(plays)), clamp(to, 0, len(plays))
+
+	feed := &PlayByPlay{
+		MatchID:     match.ID,
+		Status:      match.Status,
+		HomeScore:   match.HomeScore,
+		AwayScore:   match.AwayScore,
+		Language:    q.Language,
+		Plays:       []Play{},
+		HasOlder:    from > 0,
+		HasNewer:    to < len(plays),
+		NewerCursor: match.EventSequence,
+	}
+	for i := to - 1; i >= from; i-- {
+		feed.Plays = append(feed.Plays, playByPlay(match, plays[i]))
+	}
+	if feed.HasOlder {
+		older := plays[from].Event.Sequence
+		if from == to {
+			older = plays[from-1].Event.Sequence + 1
+		}
+		feed.OlderCursor = &older
+	}
+	if feed.HasNewer {
+		feed.NewerCursor = plays[to-1].Event.Sequence
+		if from == to {
+			feed.NewerCursor = plays[to].Event.Sequence - 1
+		}
+	}
+	return feed, nil
+}
+
+func playByPlay(match *models.Match, play narration.Play) Play {
+	e := play.Event
+	return Play{
+		Sequence:         e.Sequence,
+		EventID:          e.ID,
+		EventType:        e.Type,
+		Period:           e.Period,
+		PeriodLabel:      match.PeriodName(e.Period),
+		ClockRemainingMs: e.ClockRemainingMs,
+		TimeRemaining:    e.TimeRemaining,
+		TeamID:           e.TeamID,
+		PlayerID:         e.PlayerID,
+		Points:           e.Points,
+		Voided:           e.Voided,
+		Text:             play.Text,
+		HomeScore:        play.HomeScore,
+		AwayScore:        play.AwayScore,
+		RecordedAt:       e.CreatedAt,
+	}
+}
+
+// namesPlayer checks if an event credits, substitutes or assists a player
+func namesPlayer(e *models.MatchEvent, playerID uuid.UUID) bool {
+	for _, id := range []*uuid.UUID{e.PlayerID, e.SubstitutedPlayerID, e.AssistPlayerID} {
+		if id != nil && *id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+func clamp(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
+
+// RecordEvent appends an event to a live match, updating the running score
+// for points. The match row is locked so sequence numbers stay gapless.
+func (s *MatchEventService) RecordEvent(actor *models.User, matchID uuid.UUID, req RecordEventRequest) (*models.MatchEvent, error) {
+	match, err := s.scoringMatch(actor, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &models.MatchEvent{
+		Type:                models.MatchEventType(req.Type),
+		TeamID:              &req.TeamID,
+		PlayerID:            req.PlayerID,
+		SubstitutedPlayerID: req.SubstitutedPlayerID,
+		AssistPlayerID:      req.AssistPlayerID,
+		Points:              req.Points,
+		ShotX:               req.ShotX,
+		ShotY:               req.ShotY,
+		ShotZone:            models.ShotZone(req.ShotZone),
+		Description:         req.Description,
+		RecordedBy:          &actor.ID,
+	}
+	if err := s.validateEvent(match, event); err != nil {
+		return nil, err
+	}
+	if event.Points > 0 {
+		event.ShotZone = shotchart.Classify(event.Points, event.ShotX, event.ShotY, event.ShotZone)
+	}
+
+	var recorded *models.Match
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		m, err := s.appendEvent(tx, match.ID, event, func(m *models.Match) error {
+			if m.Period == 0 || m.GameState == models.GameStateFinal {
+				return ErrGameNotStarted
+			}
+			if err := s.checkLineup(tx, m.ID, event); err != nil {
+				return err
+			}
+			addPoints(m, event, 1)
+			return nil
+		})
+		recorded = m
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrMatchNotLive) || errors.Is(err, ErrGameNotStarted) ||
+			errors.Is(err, ErrAlreadyOnCourt) || errors.Is(err, ErrNotOnCourt) || errors.Is(err, ErrTooManyOnCourt) {
+			return nil, err
+		}
+		return nil, errors.New("failed to record event")
+	}
+	publishEvent(recorded, event)
+	return event, nil
+}
+
+// UndoLastEvent voids the latest event of a live match that is still in
+// effect. The event stays in the log marked voided, and a void event records
+// the undo with its own sequence number.
+func (s *MatchEventService) UndoLastEvent(actor *models.User, matchID uuid.UUID) (*models.MatchEvent, error) {
+	match, err := s.scoringMatch(actor, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var void *models.MatchEvent
+	var undone *models.Match
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		eventRepo := s.eventRepo.WithTx(tx)
+		// Lock the match before reading the last event so concurrent undos
+		// can't void the same event twice
+		if _, err := s.matchRepo.WithTx(tx).GetForUpdate(match.ID); err != nil {
+			return err
+		}
+		last, err := eventRepo.GetLastActive(match.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoEventToUndo
+		}
+		if err != nil {
+			return err
+		}
+
+		last.Voided = true
+		if err := eventRepo.Update(last); err != nil {
+			return err
+		}
+		void = &models.MatchEvent{
+			Type:         models.MatchEventVoid,
+			TeamID:       last.TeamID,
+			VoidsEventID: &last.ID,
+			RecordedBy:   &actor.ID,
+		}
+		undone, err = s.appendEvent(tx, match.ID, void, func(m *models.Match) error {
+			addPoints(m, last, -1)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrNoEventToUndo) || errors.Is(err, ErrMatchNotLive) {
+			return nil, err
+		}
+		return nil, errors.New("failed to undo event")
+	}
+	publishEvent(undone, void)
+	return void, nil
+}
+
+// ListScorekeepers gets the scorekeepers assigned to a match
+func (s *MatchEventService) ListScorekeepers(matchID uuid.UUID) ([]models.MatchScorekeeper, error) {
+	match, err := s.matchService.GetMatch(matchID)
+	if err != nil {
+		return nil, err
+	}
+	return s.matchRepo.ListScorekeepers(match.ID)
+}
+
+// AssignScorekeeper lets a user record the events of a match the actor manages
+func (s *MatchEventService) AssignScorekeeper(actor *models.User, matchID uuid.UUID, req AssignScorekeeperRequest, actx AuditContext) (*models.MatchScorekeeper, error) {
+	match, err := s.matchService.managedMatch(actor, matchID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsActive() {
+		return nil, ErrInactiveScorekeeper
+	}
+	if _, err := s.matchRepo.GetScorekeeper(match.ID, user.ID); err == nil {
+		return nil, ErrScorekeeperExists
+	}
+
+	scorekeeper := &models.MatchScorekeeper{
+		MatchID:    match.ID,
+		UserID:     user.ID,
+		AssignedBy: &actor.ID,
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.matchRepo.WithTx(tx).AddScorekeeper(scorekeeper); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionMatchAssignScorekeeper,
+			TargetType: models.AuditTargetMatch,
+			TargetID:   &match.ID,
+			After:      scorekeeper,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to assign scorekeeper")
+	}
+
+	scorekeeper.User = user
+	return scorekeeper, nil
+}
+
+// RemoveScorekeeper withdraws a user's scorekeeping rights for a match
+func (s *MatchEventService) RemoveScorekeeper(actor *models.User, matchID, userID uuid.UUID, actx AuditContext) error {
+	match, err := s.matchService.managedMatch(actor, matchID)
+	if err != nil {
+		return err
+	}
+	scorekeeper, err := s.matchRepo.GetScorekeeper(match.ID, userID)
+	if err != nil {
+		return ErrScorekeeperNotFound
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.matchRepo.WithTx(tx).RemoveScorekeeper(scorekeeper.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionMatchRemoveScorekeeper,
+			TargetType: models.AuditTargetMatch,
+			TargetID:   &match.ID,
+			Before:     scorekeeper,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to remove scorekeeper")
+	}
+	return nil
+}
+
+// scoringMatch loads a live match the actor may score: its managers and its
+// assigned scorekeepers
+func (s *MatchEventService) scoringMatch(actor *models.User, matchID uuid.UUID) (*models.Match, error) {
+	match, err := s.matchService.GetMatch(matchID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.matchService.authorize(actor, match); err != nil {
+		if _, err := s.matchRepo.GetScorekeeper(match.ID, actor.ID); err != nil {
+			return nil, ErrForbidden
+		}
+	}
+	if !match.IsLive() {
+		return nil, ErrMatchNotLive
+	}
+	return match, nil
+}
+
+// validateEvent checks the fields each event type needs and that the team
+// plays in the match with the players on its active roster
+func (s *MatchEventService) validateEvent(match *models.Match, event *models.MatchEvent) error {
+	var needsPlayer, allowsPlayer bool
+	switch event.Type {
+	case models.MatchEventPoint, models.MatchEventMiss, models.MatchEventSteal,
+		models.MatchEventBlock, models.MatchEventSubstitution:
+		needsPlayer, allowsPlayer = true, true
+	case models.MatchEventRebound, models.MatchEventTurnover, models.MatchEventFoul:
+		allowsPlayer = true // Team rebounds, turnovers and fouls name no player
+	}
+	isShot := event.Type == models.MatchEventPoint || event.Type == models.MatchEventMiss
+
+	switch {
+	case needsPlayer && event.PlayerID == nil,
+		!allowsPlayer && event.PlayerID != nil,
+		isShot != (event.Points > 0),
+		(event.Type == models.MatchEventSubstitution) != (event.SubstitutedPlayerID != nil),
+		event.AssistPlayerID != nil && (event.Type != models.MatchEventPoint || event.Points == 1):
+		return ErrInvalidEvent
+	}
+	if (event.ShotX == nil) != (event.ShotY == nil) ||
+		((event.ShotX != nil || event.ShotZone != "") && (!isShot || event.Points == 1)) ||
+		(event.ShotZone != "" && event.ShotZone.Value() != event.Points) {
+		return ErrShotLocation
+	}
+
+	if !match.HasTeam(*event.TeamID) {
+		return ErrEventTeam
+	}
+	for _, playerID := range []*uuid.UUID{event.PlayerID, event.SubstitutedPlayerID, event.AssistPlayerID} {
+		if playerID == nil {
+			continue
+		}
+		player, err := s.playerRepo.GetByID(*playerID)
+		if err != nil || player.TeamID != *event.TeamID || player.Status != models.PlayerStatusActive {
+			return ErrEventPlayer
+		}
+	}
+	for _, other := range []*uuid.UUID{event.SubstitutedPlayerID, event.AssistPlayerID} {
+		if other != nil && *other == *event.PlayerID {
+			return ErrInvalidEvent
+		}
+	}
+	return nil
+}
+
+// checkLineup checks the players an event names can be on court, on the
+// court kept with the match's box score or, when that is missing, replayed
+// from its events: a substitution must leave the team with a lineup that can,
+// and the player and assister of any other event but a foul must be playing.
+// Fouls can be called on players on the bench.
+func (s *MatchEventService) checkLineup(tx *gorm.DB, matchID uuid.UUID, event *models.MatchEvent) error {
+	if event.Type == models.MatchEventFoul {
+		return nil
+	}
+	var players []uuid.UUID
+	for _, playerID := range []*uuid.UUID{event.PlayerID, event.AssistPlayerID} {
+		if playerID != nil {
+			players = append(players, *playerID)
+		}
+	}
+	if len(players) == 0 {
+		return nil
+	}
+
+	var court *oncourt.Court
+	state, err := boxScoreState(s.statsRepo.WithTx(tx), matchID)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		court = state.Court
+	} else {
+		events, err := s.eventRepo.WithTx(tx).ListByMatch(matchID, 0)
+		if err != nil {
+			return err
+		}
+		court = oncourt.Replay(events)
+	}
+	if event.Type == models.MatchEventSubstitution {
+		return court.Check(*event.TeamID, *event.PlayerID, *event.SubstitutedPlayerID)
+	}
+	return court.CheckPlaying(*event.TeamID, players...)
+}
+
+// appendEvent locks the match, applies the event's effect on it, stamps the
+// event with the next sequence number and the game clock, stores both and
+// carries the match's box score on with the event. apply may reject the
+// event by returning an error.
+func (s *MatchEventService) appendEvent(tx *gorm.DB, matchID uuid.UUID, event *models.MatchEvent, apply func(*models.Match) error) (*models.Match, error) {
+	matchRepo := s.matchRepo.WithTx(tx)
+	match, err := matchRepo.GetForUpdate(matchID)
+	if err != nil {
+		return nil, err
+	}
+	if !match.IsLive() {
+		return nil, ErrMatchNotLive
+	}
+	if err := apply(match); err != nil {
+		return nil, err
+	}
+
+	remaining := match.ClockRemaining(time.Now())
+	match.EventSequence++
+	event.MatchID = match.ID
+	event.Sequence = match.EventSequence
+	event.Period = match.Period
+	event.ClockRemainingMs = remaining.Milliseconds()
+	event.TimeRemaining = models.FormatClock(remaining)
+	if err := matchRepo.Update(match); err != nil {
+		return nil, err
+	}
+	if err := s.eventRepo.WithTx(tx).Create(event); err != nil {
+		return nil, err
+	}
+	if err := updateStatistics(s.eventRepo.WithTx(tx), s.statsRepo.WithTx(tx), match, event); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// addPoints adds the points of a point event to the scoring team, or takes
+// them away again with sign -1
+func addPoints(match *models.Match, event *models.MatchEvent, sign int) {
+	if event.Type != models.MatchEventPoint || event.TeamID == nil {
+		return
+	}
+	if *event.TeamID == match.HomeTeamID {
+		match.HomeScore += sign * event.Points
+	} else {
+		match.AwayScore += sign * event.Points
+	}
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestPlayByPlayJerseyNumbers(t *testing.T) {
+	withTestDB(t, &models.Organization{}, &models.User{}, &models.Team{}, &models.Player{},
+		&models.RosterMembership{}, &models.Match{}, &models.MatchEvent{},
+		&models.PlayerStatistics{}, &models.TeamStatistics{}, &models.BoxScoreState{})
+	s := NewMatchEventService()
+	org := uuid.New()
+	home := models.Team{ID: uuid.New(), OrganizationID: org, Name: "Home"}
+	away := models.Team{ID: uuid.New(), OrganizationID: org, Name: "Away"}
+	if err := database.DB.Create([]*models.Team{&home, &away}).Error; err != nil {
+		t.Fatal(err)
+	}
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC) }
+	march, june := day(time.March, 1), day(time.June, 1)
+
+	// Smith wore 23 at home until June, then moved to away as 8. Lee changed
+	// number on the day of the match, and Kim has no roster history.
+	smith := models.Player{ID: uuid.New(), TeamID: away.ID, FullName: "Ana Smith", JerseyNumber: 8,
+		Position: models.PositionPointGuard, Status: models.PlayerStatusActive}
+	lee := models.Player{ID: uuid.New(), TeamID: home.ID, FullName: "Jo Lee", JerseyNumber: 12,
+		Position: models.PositionCenter, Status: models.PlayerStatusActive}
+	kim := models.Player{ID: uuid.New(), TeamID: home.ID, FullName: "Min Kim", JerseyNumber: 3,
+		Position: models.PositionSmallForward, Status: models.PlayerStatusActive}
+	if err := database.DB.Create([]*models.Player{&smith, &lee, &kim}).Error; err != nil {
+		t.Fatal(err)
+	}
+	stints := []*models.RosterMembership{
+		{PlayerID: smith.ID, TeamID: home.ID, JerseyNumber: 23, StartDate: day(time.January, 1), EndDate: &june},
+		{PlayerID: smith.ID, TeamID: away.ID, JerseyNumber: 8, StartDate: june},
+		{PlayerID: lee.ID, TeamID: home.ID, JerseyNumber: 5, StartDate: day(time.January, 1), EndDate: &march},
+		{PlayerID: lee.ID, TeamID: home.ID, JerseyNumber: 12, StartDate: march},
+	}
+	if err := database.DB.Create(stints).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	match := models.Match{ID: uuid.New(), HomeTeamID: home.ID, AwayTeamID: away.ID, Venue: "Arena",
+		ScheduledAt: march.Add(19 * time.Hour), Status: models.MatchStatusCompleted, PeriodCount: 4}
+	if err := database.DB.Create(&match).Error; err != nil {
+		t.Fatal(err)
+	}
+	events := []models.MatchEvent{
+		{MatchID: match.ID, Sequence: 1, Type: models.MatchEventPoint, TeamID: &home.ID, PlayerID: &smith.ID,
+			Points: 2, AssistPlayerID: &lee.ID},
+		{MatchID: match.ID, Sequence: 2, Type: models.MatchEventSteal, TeamID: &home.ID, PlayerID: &kim.ID},
+	}
+	if err := database.DB.Create(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	feed, err := s.GetPlayByPlay(nil, match.ID, PlayByPlayQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"#3 Kim steal", "#23 Smith makes 2-pt shot (assist: #12 Lee)"}
+	if len(feed.Plays) != len(want) {
+		t.Fatalf("got %d plays, want %d", len(feed.Plays), len(want))
+	}
+	for i, play := range feed.Plays {
+		if play.Text != want[i] {
+			t.Errorf("play %d: got %q, want %q", i, play.Text, want[i])
+		}
+	}
+
+	// The box score lists the same numbers
+	if err := recordStatistics(s.eventRepo, s.statsRepo, &match); err != nil {
+		t.Fatal(err)
+	}
+	box, err := NewStatisticsService().GetBoxScore(nil, match.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers := map[uuid.UUID]int{smith.ID: 23, lee.ID: 12, kim.ID: 3}
+	for _, line := range box.Home.Players {
+		if line.JerseyNumber != numbers[line.PlayerID] {
+			t.Errorf("box score of %s: got #%d, want #%d", line.FullName, line.JerseyNumber, numbers[line.PlayerID])
+		}
+	}
+	if len(box.Home.Players) != len(numbers) {
+		t.Errorf("got %d home players in the box score, want %d", len(box.Home.Players), len(numbers))
+	}
+}
+
+func TestRecordEvent(t *testing.T) {
+	g := newLiveGame(t)
+	s := NewMatchEventService()
+	home, away := &g.homePlayer.ID, &g.awayPlayer.ID
+
+	cases := []struct {
+		name       string
+		req        RecordEventRequest
+		home, away int
+	}{
+		{"home three", RecordEventRequest{Type: "point", TeamID: g.home.ID, PlayerID: home, Points: 3}, 3, 0},
+		{"away free throw", RecordEventRequest{Type: "point", TeamID: g.away.ID, PlayerID: away, Points: 1}, 3, 1},
+		{"miss", RecordEventRequest{Type: "miss", TeamID: g.home.ID, PlayerID: home, Points: 2}, 3, 1},
+		{"rebound", RecordEventRequest{Type: "rebound", TeamID: g.away.ID, PlayerID: away}, 3, 1},
+		{"team turnover", RecordEventRequest{Type: "turnover", TeamID: g.away.ID}, 3, 1},
+		{"away two", RecordEventRequest{Type: "point", TeamID: g.away.ID, PlayerID: away, Points: 2}, 3, 3},
+		{"timeout", RecordEventRequest{Type: "timeout", TeamID: g.home.ID}, 3, 3},
+	}
+	for i, c := range cases {
+		event, err := s.RecordEvent(g.admin, g.match.ID, c.req)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if event.Sequence != i+1 {
+			t.Errorf("%s: got sequence %d, want %d", c.name, event.Sequence, i+1)
+		}
+		match, err := s.matchService.GetMatch(g.match.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match.HomeScore != c.home || match.AwayScore != c.away || match.EventSequence != i+1 {
+			t.Errorf("%s: got %d-%d at sequence %d, want %d-%d at %d", c.name,
+				match.HomeScore, match.AwayScore, match.EventSequence, c.home, c.away, i+1)
+		}
+	}
+}
+
+func TestRecordEventRejected(t *testing.T) {
+	g := newLiveGame(t)
+	s := NewMatchEventService()
+	point := func(team uuid.UUID, player *uuid.UUID) RecordEventRequest {
+		return RecordEventRequest{Type: "point", TeamID: team, PlayerID: player, Points: 2}
+	}
+	stranger := models.Player{ID: uuid.New(), TeamID: uuid.New(), FullName: "Min Kim", JerseyNumber: 3,
+		Position: models.PositionSmallForward, Status: models.PlayerStatusActive}
+	if err := database.DB.Create(&stranger).Error; err != nil {
+		t.Fatal(err)
+	}
+	setMatch := func(fields map[string]interface{}) {
+		t.Helper()
+		if err := database.DB.Model(&models.Match{}).Where("id = ?", g.match.ID).Updates(fields).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name  string
+		setup map[string]interface{}
+		req   RecordEventRequest
+		err   error
+	}{
+		{"team not in the match", nil, point(stranger.TeamID, &stranger.ID), ErrEventTeam},
+		{"player of another team", nil, point(g.home.ID, &stranger.ID), ErrEventPlayer},
+		{"player of the opponent", nil, point(g.home.ID, &g.awayPlayer.ID), ErrEventPlayer},
+		{"before the first period", map[string]interface{}{"period": 0, "game_state": models.GameStatePreGame},
+			point(g.home.ID, &g.homePlayer.ID), ErrGameNotStarted},
+		{"after the game", map[string]interface{}{"game_state": models.GameStateFinal},
+			point(g.home.ID, &g.homePlayer.ID), ErrGameNotStarted},
+		{"scheduled match", map[string]interface{}{"status": models.MatchStatusScheduled},
+			point(g.home.ID, &g.homePlayer.ID), ErrMatchNotLive},
+		{"completed match", map[string]interface{}{"status": models.MatchStatusCompleted},
+			point(g.home.ID, &g.homePlayer.ID), ErrMatchNotLive},
+	}
+	for _, c := range cases {
+		setMatch(map[string]interface{}{"status": models.MatchStatusLive, "period": 1, "game_state": models.GameStatePeriod})
+		if c.setup != nil {
+			setMatch(c.setup)
+		}
+		if _, err := s.RecordEvent(g.admin, g.match.ID, c.req); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+		}
+	}
+
+	match, err := s.matchService.GetMatch(g.match.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.HomeScore != 0 || match.EventSequence != 0 {
+		t.Errorf("rejected events left score %d at sequence %d, want 0 at 0", match.HomeScore, match.EventSequence)
+	}
+}
+
+func TestRecordEventLineup(t *testing.T) {
+	g := newLiveGame(t)
+	s := NewMatchEventService()
+	p := []*uuid.UUID{&g.homePlayer.ID}
+	for i := 1; i < 6; i++ {
+		player := models.Player{ID: uuid.New(), TeamID: g.home.ID, FullName: "Player " + strconv.Itoa(i),
+			JerseyNumber: 20 + i, Position: models.PositionShootingGuard, Status: models.PlayerStatusActive}
+		if err := database.DB.Create(&player).Error; err != nil {
+			t.Fatal(err)
+		}
+		p = append(p, &player.ID)
+	}
+	point := func(player *uuid.UUID) RecordEventRequest {
+		return RecordEventRequest{Type: "point", TeamID: g.home.ID, PlayerID: player, Points: 2}
+	}
+	foul := func(player *uuid.UUID) RecordEventRequest {
+		return RecordEventRequest{Type: "foul", TeamID: g.home.ID, PlayerID: player, Description: "Technical"}
+	}
+
+	// p0-p4 start and p5 waits on the bench
+	cases := []struct {
+		name string
+		req  RecordEventRequest
+		err  error
+	}{
+		{"first starter", point(p[0]), nil},
+		{"second starter", point(p[1]), nil},
+		{"third starter", point(p[2]), nil},
+		{"fourth starter", point(p[3]), nil},
+		{"fifth starter", point(p[4]), nil},
+		{"foul on the bench", foul(p[5]), nil},
+		{"scoring from the bench", point(p[5]), ErrTooManyOnCourt},
+		{"substitution", RecordEventRequest{Type: "substitution", TeamID: g.home.ID, PlayerID: p[5], SubstitutedPlayerID: p[0]}, nil},
+		{"substitute scoring", point(p[5]), nil},
+		{"foul after going off", foul(p[0]), nil},
+		{"scoring after going off", point(p[0]), ErrNotOnCourt},
+	}
+	for _, c := range cases {
+		if _, err := s.RecordEvent(g.admin, g.match.ID, c.req); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+		}
+	}
+
+	// Without the box score state the court is replayed from the events
+	if err := database.DB.Where("match_id = ?", g.match.ID).Delete(&models.BoxScoreState{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RecordEvent(g.admin, g.match.ID, point(p[0])); !errors.Is(err, ErrNotOnCourt) {
+		t.Errorf("scoring after going off, replayed: got %v, want %v", err, ErrNotOnCourt)
+	}
+	if _, err := s.RecordEvent(g.admin, g.match.ID, point(p[5])); err != nil {
+		t.Errorf("substitute scoring, replayed: %v", err)
+	}
+}
+
+func TestUndoLastEvent(t *testing.T) {
+	g := newLiveGame(t)
+	s := NewMatchEventService()
+
+	if _, err := s.UndoLastEvent(g.admin, g.match.ID); !errors.Is(err, ErrNoEventToUndo) {
+		t.Errorf("undo without events: got %v, want %v", err, ErrNoEventToUndo)
+	}
+	three, err := s.RecordEvent(g.admin, g.match.ID,
+		RecordEventRequest{Type: "point", TeamID: g.home.ID, PlayerID: &g.homePlayer.ID, Points: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	two, err := s.RecordEvent(g.admin, g.match.ID,
+		RecordEventRequest{Type: "point", TeamID: g.away.ID, PlayerID: &g.awayPlayer.ID, Points: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		undoes     *models.MatchEvent
+		sequence   int
+		home, away int
+	}{
+		{two, 3, 3, 0},
+		{three, 4, 0, 0}, // Voids are not undone themselves
+	}
+	for _, c := range cases {
+		void, err := s.UndoLastEvent(g.admin, g.match.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if void.Type != models.MatchEventVoid || void.Sequence != c.sequence ||
+			void.VoidsEventID == nil || *void.VoidsEventID != c.undoes.ID {
+			t.Errorf("undoing %d: got %s at sequence %d, want a void of it at %d",
+				c.undoes.Sequence, void.Type, void.Sequence, c.sequence)
+		}
+		match, err := s.matchService.GetMatch(g.match.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match.HomeScore != c.home || match.AwayScore != c.away || match.EventSequence != c.sequence {
+			t.Errorf("undoing %d: got %d-%d at sequence %d, want %d-%d at %d", c.undoes.Sequence,
+				match.HomeScore, match.AwayScore, match.EventSequence, c.home, c.away, c.sequence)
+		}
+	}
+
+	events, err := s.eventRepo.ListByMatch(g.match.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range events[:2] {
+		if !e.Voided {
+			t.Errorf("event %d was not marked voided", e.Sequence)
+		}
+	}
+	if _, err := s.UndoLastEvent(g.admin, g.match.ID); !errors.Is(err, ErrNoEventToUndo) {
+		t.Errorf("undo with every event voided: got %v, want %v", err, ErrNoEventToUndo)
+	}
+}
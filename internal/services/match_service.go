@@ -0,0 +1,589 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"echo-golang/internal/config"
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMatchNotFound          = errors.New("match not found")
+	ErrSameTeams              = errors.New("home and away teams must be different")
+	ErrTeamNotInTournament    = errors.New("both teams must be registered in the tournament")
+	ErrOutsideTournamentDates = errors.New("match must be scheduled within the tournament dates")
+	ErrInvalidMatchTransition = errors.New("invalid match status transition")
+	ErrMatchTied              = errors.New("a completed match cannot end in a tie")
+	ErrMatchNotScheduled      = errors.New("only scheduled matches can be rescheduled")
+	ErrMatchLocked            = errors.New("live and completed matches cannot be deleted")
+	ErrScoresNotEditable      = errors.New("scores can only be changed once a match is live")
+	ErrScoresFromEvents       = errors.New("the score of a live match with recorded events comes from its events")
+	ErrPrivateMatch           = errors.New("this match is private")
+
+	// ErrScheduleConflict is matched by every ScheduleConflictError
+	ErrScheduleConflict = errors.New("schedule conflict")
+)
+
+// ScheduleConflictError reports the existing match that blocks a booking
+type ScheduleConflictError struct {
+	Reason string // "venue" or "team"
+	Match  models.Match
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("%s is already booked for match %s at %s",
+		e.Reason, e.Match.ID, e.Match.ScheduledAt.UTC().Format(time.RFC3339))
+}
+
+// Is makes errors.Is(err, ErrScheduleConflict) match any conflict
+func (e *ScheduleConflictError) Is(target error) bool {
+	return target == ErrScheduleConflict
+}
+
+type MatchService struct {
+	matchRepo      *repositories.MatchRepository
+	teamRepo       *repositories.TeamRepository
+	tournamentRepo *repositories.TournamentRepository
+	bracketRepo    *repositories.BracketRepository
+	eventRepo      *repositories.MatchEventRepository
+	statsRepo      *repositories.StatisticsRepository
+	standingRepo   *repositories.StandingRepository
+	auditService   *AuditService
+}
+
+func NewMatchService() *MatchService {
+	return &MatchService{
+		matchRepo:      repositories.NewMatchRepository(),
+		teamRepo:       repositories.NewTeamRepository(),
+		tournamentRepo: repositories.NewTournamentRepository(),
+		bracketRepo:    repositories.NewBracketRepository(),
+		eventRepo:      repositories.NewMatchEventRepository(),
+		statsRepo:      repositories.NewStatisticsRepository(),
+		standingRepo:   repositories.NewStandingRepository(),
+		auditService:   NewAuditService(),
+	}
+}
+
+type CreateMatchRequest struct {
+	TournamentID *uuid.UUID `json:"tournament_id,omitempty"`
+	HomeTeamID   uuid.UUID  `json:"home_team_id" binding:"required"`
+	AwayTeamID   uuid.UUID  `json:"away_team_id" binding:"required"`
+	ScheduledAt  time.Time  `json:"scheduled_at" binding:"required"` // RFC3339
+	Venue        string     `json:"venue" binding:"required"`
+	RefereeName  string     `json:"referee_name,omitempty"`
+	Notes        string     `json:"notes,omitempty"`
+	IsPrivate    bool       `json:"is_private,omitempty"`
+}
+
+// UpdateMatchRequest holds the match fields that can change outside of
+// rescheduling and cancellation
+type UpdateMatchRequest struct {
+	RefereeName *string `json:"referee_name,omitempty"`
+	Notes       *string `json:"notes,omitempty"`
+	Status      *string `json:"status,omitempty" binding:"omitempty,oneof=live completed"`
+	HomeScore   *int    `json:"home_score,omitempty" binding:"omitempty,min=0"`
+	AwayScore   *int    `json:"away_score,omitempty" binding:"omitempty,min=0"`
+	IsPrivate   *bool   `json:"is_private,omitempty"`
+}
+
+type RescheduleMatchRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"` // RFC3339
+	Venue       *string   `json:"venue,omitempty" binding:"omitempty,min=1"`
+	Reason      string    `json:"reason" binding:"required,max=500"`
+}
+
+type CancelMatchRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// GetMatch gets a match by ID
+func (s *MatchService) GetMatch(id uuid.UUID) (*models.Match, error) {
+	match, err := s.matchRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrMatchNotFound
+	}
+	return match, nil
+}
+
+// ListMatches gets matches matching the filters with pagination
+func (s *MatchService) ListMatches(offset, limit int, filters map[string]interface{}) ([]models.Match, int64, error) {
+	return s.matchRepo.List(offset, limit, filters)
+}
+
+// CreateMatch schedules a match. Tournament matches need a tournament manager,
+// friendlies an admin of either team's organization.
+func (s *MatchService) CreateMatch(actor *models.User, req CreateMatchRequest, actx AuditContext) (*models.Match, error) {
+	if req.HomeTeamID == req.AwayTeamID {
+		return nil, ErrSameTeams
+	}
+	home, err := s.activeTeam(req.HomeTeamID)
+	if err != nil {
+		return nil, err
+	}
+	away, err := s.activeTeam(req.AwayTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	match := &models.Match{
+		TournamentID: req.TournamentID,
+		HomeTeamID:   home.ID,
+		AwayTeamID:   away.ID,
+		ScheduledAt:  req.ScheduledAt.UTC(),
+		Venue:        strings.TrimSpace(req.Venue),
+		Status:       models.MatchStatusScheduled,
+		RefereeName:  req.RefereeName,
+		Notes:        req.Notes,
+		IsPrivate:    req.IsPrivate,
+		CreatedBy:    &actor.ID,
+		HomeTeam:     home,
+		AwayTeam:     away,
+	}
+	if err := s.authorize(actor, match); err != nil {
+		return nil, err
+	}
+	if match.TournamentID != nil {
+		if err := s.checkTournament(match); err != nil {
+			return nil, err
+		}
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		matchRepo := s.matchRepo.WithTx(tx)
+		if err := s.checkBooking(matchRepo, match); err != nil {
+			return err
+		}
+		if err := matchRepo.Create(match); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionMatchCreate,
+			TargetType: models.AuditTargetMatch,
+			TargetID:   &match.ID,
+			After:      match,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrScheduleConflict) {
+			return nil, err
+		}
+		return nil, errors.New("failed to create match")
+	}
+
+	return s.GetMatch(match.ID)
+}
+
+// UpdateMatch applies the non-nil fields of req to a match the actor manages.
+// Completing a match decides the winner from the scores and, for bracket
+// matches, advances the winner to the next game.
+func (s *MatchService) UpdateMatch(actor *models.User, id uuid.UUID, req UpdateMatchRequest, actx AuditContext) (*models.Match, error) {
+	match, err := s.managedMatch(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.save(match, models.AuditActionMatchUpdate, actx, updateMatch(req))
+	if err != nil {
+		return nil, matchError(err, "failed to update match")
+	}
+	return s.GetMatch(updated.ID)
+}
+
+// updateMatch applies an update to the locked match
+func updateMatch(req UpdateMatchRequest) func(*gorm.DB, *models.Match) error {
+	return func(tx *gorm.DB, m *models.Match) error {
+		before := *m
+		if req.Status != nil && models.MatchStatus(*req.Status) != m.Status {
+			next := models.MatchStatus(*req.Status)
+			if !m.Status.CanTransitionTo(next) {
+				return ErrInvalidMatchTransition
+			}
+			m.Status = next
+		}
+		if req.RefereeName != nil {
+			m.RefereeName = *req.RefereeName
+		}
+		if req.Notes != nil {
+			m.Notes = *req.Notes
+		}
+		if req.IsPrivate != nil {
+			m.IsPrivate = *req.IsPrivate
+		}
+		if req.HomeScore != nil || req.AwayScore != nil {
+			// Corrections to completed results are allowed so standings can be recomputed
+			if !m.IsLive() && !m.IsCompleted() {
+				return ErrScoresNotEditable
+			}
+			// Undoing an event takes its points back off the score, which has
+			// to stay the sum of the log while the match is live
+			if before.IsLive() && before.EventSequence > 0 && scoreChanged(&before, req) {
+				return ErrScoresFromEvents
+			}
+			if req.HomeScore != nil {
+				m.HomeScore = *req.HomeScore
+			}
+			if req.AwayScore != nil {
+				m.AwayScore = *req.AwayScore
+			}
+		}
+		if m.IsCompleted() {
+			if m.HomeScore == m.AwayScore {
+				return ErrMatchTied
+			}
+			finishMatch(m)
+		}
+		return nil
+	}
+}
+
+// scoreChanged reports whether an update sets a score other than the match's
+func scoreChanged(match *models.Match, req UpdateMatchRequest) bool {
+	return (req.HomeScore != nil && *req.HomeScore != match.HomeScore) ||
+		(req.AwayScore != nil && *req.AwayScore != match.AwayScore)
+}
+
+// RescheduleMatch moves a scheduled match to a new time and optionally venue
+func (s *MatchService) RescheduleMatch(actor *models.User, id uuid.UUID, req RescheduleMatchRequest, actx AuditContext) (*models.Match, error) {
+	match, err := s.managedMatch(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if match.TournamentID != nil {
+		moved := *match
+		moved.ScheduledAt = req.ScheduledAt.UTC()
+		if err := s.checkTournament(&moved); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := s.save(match, models.AuditActionMatchReschedule, actx, s.rescheduleMatch(req))
+	if err != nil {
+		return nil, matchError(err, "failed to reschedule match")
+	}
+	return s.GetMatch(updated.ID)
+}
+
+// rescheduleMatch moves the locked match once it is free of conflicts
+func (s *MatchService) rescheduleMatch(req RescheduleMatchRequest) func(*gorm.DB, *models.Match) error {
+	return func(tx *gorm.DB, m *models.Match) error {
+		if m.Status != models.MatchStatusScheduled {
+			return ErrMatchNotScheduled
+		}
+		m.ScheduledAt = req.ScheduledAt.UTC()
+		if req.Venue != nil {
+			m.Venue = strings.TrimSpace(*req.Venue)
+		}
+		if err := s.checkBooking(s.matchRepo.WithTx(tx), m); err != nil {
+			return err
+		}
+		m.RescheduleCount++
+		m.RescheduleReason = req.Reason
+		return nil
+	}
+}
+
+// CancelMatch cancels a scheduled or live match, keeping the reason
+func (s *MatchService) CancelMatch(actor *models.User, id uuid.UUID, req CancelMatchRequest, actx AuditContext) (*models.Match, error) {
+	match, err := s.managedMatch(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.save(match, models.AuditActionMatchCancel, actx, cancelMatch(req))
+	if err != nil {
+		return nil, matchError(err, "failed to cancel match")
+	}
+	return s.GetMatch(updated.ID)
+}
+
+// cancelMatch cancels the locked match
+func cancelMatch(req CancelMatchRequest) func(*gorm.DB, *models.Match) error {
+	return func(tx *gorm.DB, m *models.Match) error {
+		if !m.Status.CanTransitionTo(models.MatchStatusCancelled) {
+			return ErrInvalidMatchTransition
+		}
+		m.Status = models.MatchStatusCancelled
+		m.CancellationReason = req.Reason
+		return nil
+	}
+}
+
+// CreateTournamentMatches schedules a batch of matches of one tournament
+// atomically: if any match conflicts with an existing booking or with an
+// earlier match of the batch, none are created. The caller authorizes the
+// actor against the tournament.
+func (s *MatchService) CreateTournamentMatches(actor *models.User, tournament *models.Tournament, matches []*models.Match, actx AuditContext) error {
+	for _, match := range matches {
+		if !withinTournament(tournament, match.ScheduledAt) {
+			return ErrOutsideTournamentDates
+		}
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		matchRepo := s.matchRepo.WithTx(tx)
+		for _, match := range matches {
+			match.TournamentID = &tournament.ID
+			match.Status = models.MatchStatusScheduled
+			match.CreatedBy = &actor.ID
+			if err := s.checkBooking(matchRepo, match); err != nil {
+				return err
+			}
+			if err := matchRepo.Create(match); err != nil {
+				return err
+			}
+			if err := s.auditService.Record(tx, actx, AuditEntry{
+				Action:     models.AuditActionMatchCreate,
+				TargetType: models.AuditTargetMatch,
+				TargetID:   &match.ID,
+				After:      match,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteMatch soft deletes a scheduled or cancelled match the actor manages
+func (s *MatchService) DeleteMatch(actor *models.User, id uuid.UUID, actx AuditContext) error {
+	match, err := s.managedMatch(actor, id)
+	if err != nil {
+		return err
+	}
+	if match.IsLive() || match.IsCompleted() {
+		return ErrMatchLocked
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.matchRepo.WithTx(tx).Delete(match.ID); err != nil {
+			return err
+		}
+		if err := s.bracketRepo.WithTx(tx).Unlink(match.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionMatchDelete,
+			TargetType: models.AuditTargetMatch,
+			TargetID:   &match.ID,
+			Before:     match,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to delete match")
+	}
+	return nil
+}
+
+// save locks the row of a match the actor was authorized for and applies a
+// change to that copy, so events and clock changes committed since the
+// match was read are kept. apply validates the change against the locked
+// row, so concurrent changes can't both pass, and may reject it by
+// returning an error. The updated match advances its bracket, finalizes its statistics
+// and reranks its tournament once it is completed, and the change is
+// recorded in the audit log. Correcting a completed match reranks the
+// tournament too.
+func (s *MatchService) save(match *models.Match, action models.AuditAction, actx AuditContext, apply func(*gorm.DB, *models.Match) error) (*models.Match, error) {
+	var updated, before *models.Match
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		m, err := s.matchRepo.WithTx(tx).GetForUpdate(match.ID)
+		if err != nil {
+			return err
+		}
+		m.HomeTeam, m.AwayTeam = match.HomeTeam, match.AwayTeam
+		snapshot := *m
+		if err := apply(tx, m); err != nil {
+			return err
+		}
+		updated, before = m, &snapshot
+
+		if err := s.matchRepo.WithTx(tx).Update(m); err != nil {
+			return err
+		}
+		// A cancelled bracket match leaves its game free to be scheduled again
+		// and its teams free to be replaced by a corrected result
+		if m.Status == models.MatchStatusCancelled {
+			if err := s.bracketRepo.WithTx(tx).Unlink(m.ID); err != nil {
+				return err
+			}
+		}
+		if err := advanceBracket(s.bracketRepo.WithTx(tx), m); err != nil {
+			return err
+		}
+		if err := finalizeStatistics(s.eventRepo.WithTx(tx), s.statsRepo.WithTx(tx), m); err != nil {
+			return err
+		}
+		if m.TournamentID != nil && (m.IsCompleted() || before.IsCompleted()) {
+			err := rebuildStandings(s.tournamentRepo.WithTx(tx), s.matchRepo.WithTx(tx), s.standingRepo.WithTx(tx), *m.TournamentID)
+			if err != nil {
+				return err
+			}
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     action,
+			TargetType: models.AuditTargetMatch,
+			TargetID:   &m.ID,
+			Before:     before,
+			After:      m,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated.IsCompleted() || before.IsCompleted() {
+		leaderboards.invalidate(updated)
+	}
+	publishMatch(updated)
+	return updated, nil
+}
+
+// matchError keeps the match errors callers can act on and hides the rest
+func matchError(err error, message string) error {
+	for _, known := range []error{
+		ErrInvalidMatchTransition, ErrMatchTied, ErrMatchNotScheduled, ErrScoresNotEditable,
+		ErrScoresFromEvents, ErrBracketLocked, ErrScheduleConflict,
+	} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+	return errors.New(message)
+}
+
+// viewableMatch loads a match whose live data the viewer may follow. Private
+// matches are limited to the users of either team's organization, the
+// match's managers and its scorekeepers; viewer is nil for anonymous requests.
+func (s *MatchService) viewableMatch(viewer *models.User, id uuid.UUID) (*models.Match, error) {
+	match, err := s.GetMatch(id)
+	if err != nil {
+		return nil, err
+	}
+	if !match.IsPrivate {
+		return match, nil
+	}
+	if viewer == nil {
+		return nil, ErrPrivateMatch
+	}
+	if viewer.OrganizationID != nil &&
+		((match.HomeTeam != nil && match.HomeTeam.OrganizationID == *viewer.OrganizationID) ||
+			(match.AwayTeam != nil && match.AwayTeam.OrganizationID == *viewer.OrganizationID)) {
+		return match, nil
+	}
+	if err := s.authorize(viewer, match); err == nil {
+		return match, nil
+	}
+	if _, err := s.matchRepo.GetScorekeeper(match.ID, viewer.ID); err == nil {
+		return match, nil
+	}
+	return nil, ErrPrivateMatch
+}
+
+// managedMatch loads a match and checks the actor may modify it
+func (s *MatchService) managedMatch(actor *models.User, id uuid.UUID) (*models.Match, error) {
+	match, err := s.GetMatch(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(actor, match); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// authorize checks the actor may manage a match with its teams loaded
+func (s *MatchService) authorize(actor *models.User, match *models.Match) error {
+	if match.TournamentID != nil {
+		tournament, err := s.tournamentRepo.GetByID(*match.TournamentID)
+		if err != nil {
+			return ErrTournamentNotFound
+		}
+		if !tournament.CanBeManagedBy(actor) {
+			return ErrForbidden
+		}
+		return nil
+	}
+	if (match.HomeTeam != nil && actor.CanManageOrganization(match.HomeTeam.OrganizationID)) ||
+		(match.AwayTeam != nil && actor.CanManageOrganization(match.AwayTeam.OrganizationID)) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// checkTournament validates a tournament match against its tournament
+func (s *MatchService) checkTournament(match *models.Match) error {
+	tournament, err := s.tournamentRepo.GetByID(*match.TournamentID)
+	if err != nil {
+		return ErrTournamentNotFound
+	}
+	if tournament.Status == models.TournamentStatusCompleted {
+		return ErrTournamentCompleted
+	}
+	for _, teamID := range []uuid.UUID{match.HomeTeamID, match.AwayTeamID} {
+		if _, err := s.tournamentRepo.GetEntry(tournament.ID, teamID); err != nil {
+			return ErrTeamNotInTournament
+		}
+	}
+	if !withinTournament(tournament, match.ScheduledAt) {
+		return ErrOutsideTournamentDates
+	}
+	return nil
+}
+
+// withinTournament checks if a match time falls on one of the tournament's
+// days
+func withinTournament(tournament *models.Tournament, at time.Time) bool {
+	day := dateOf(at)
+	return !day.Before(dateOf(tournament.StartDate)) && !day.After(dateOf(tournament.EndDate))
+}
+
+// checkBooking locks the venue and teams of a booking and checks it for
+// conflicts. Call it with the repository of the transaction that writes the
+// booking, so no other booking of them can commit in between.
+func (s *MatchService) checkBooking(matchRepo *repositories.MatchRepository, match *models.Match) error {
+	if err := matchRepo.LockBooking(match.Venue, []uuid.UUID{match.HomeTeamID, match.AwayTeamID}); err != nil {
+		return err
+	}
+	return s.checkConflicts(matchRepo, match)
+}
+
+// checkConflicts rejects a booking that overlaps another match at the same
+// venue or of the same teams, including the configured buffer between matches
+func (s *MatchService) checkConflicts(matchRepo *repositories.MatchRepository, match *models.Match) error {
+	conflicts, err := matchRepo.FindConflicts(match.ScheduledAt, schedulingWindow(), match.Venue,
+		[]uuid.UUID{match.HomeTeamID, match.AwayTeamID}, match.ID)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	conflict := conflicts[0]
+	reason := "team"
+	if strings.EqualFold(conflict.Venue, match.Venue) {
+		reason = "venue"
+	}
+	return &ScheduleConflictError{Reason: reason, Match: conflict}
+}
+
+// schedulingWindow is how far apart two matches of the same venue or team
+// must start
+func schedulingWindow() time.Duration {
+	return config.AppConfig.MatchDuration + config.AppConfig.MatchSchedulingBuffer
+}
+
+// activeTeam loads a team that can be scheduled
+func (s *MatchService) activeTeam(id uuid.UUID) (*models.Team, error) {
+	team, err := s.teamRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrTeamNotFound
+	}
+	if !team.IsActive() {
+		return nil, ErrTeamInactive
+	}
+	return team, nil
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"echo-golang/internal/config"
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestUpdateLiveMatchScore(t *testing.T) {
+	withTestDB(t, &models.Organization{}, &models.Team{}, &models.Match{}, &models.BracketNode{},
+		&models.MatchEvent{}, &models.PlayerStatistics{}, &models.TeamStatistics{}, &models.BoxScoreState{},
+		&models.AuditEvent{})
+	s := NewMatchService()
+	admin := &models.User{ID: uuid.New(), Role: models.RoleSuperAdmin}
+	org := uuid.New()
+	home := models.Team{ID: uuid.New(), OrganizationID: org, Name: "Home"}
+	away := models.Team{ID: uuid.New(), OrganizationID: org, Name: "Away"}
+	if err := database.DB.Create([]*models.Team{&home, &away}).Error; err != nil {
+		t.Fatal(err)
+	}
+	score := func(n int) *int { return &n }
+	text := func(s string) *string { return &s }
+
+	cases := []struct {
+		name     string
+		sequence int
+		req      UpdateMatchRequest
+		err      error
+	}{
+		{"before any event", 0, UpdateMatchRequest{HomeScore: score(12)}, nil},
+		{"with events", 3, UpdateMatchRequest{HomeScore: score(12)}, ErrScoresFromEvents},
+		{"away score with events", 3, UpdateMatchRequest{AwayScore: score(1)}, ErrScoresFromEvents},
+		{"completing with another score", 3, UpdateMatchRequest{Status: text("completed"), HomeScore: score(12)}, ErrScoresFromEvents},
+		{"the score the events left", 3, UpdateMatchRequest{HomeScore: score(5), AwayScore: score(2)}, nil},
+		{"other fields", 3, UpdateMatchRequest{Notes: text("Overtime")}, nil},
+	}
+	for _, c := range cases {
+		match := models.Match{ID: uuid.New(), HomeTeamID: home.ID, AwayTeamID: away.ID, Venue: "Arena",
+			ScheduledAt: time.Now(), Status: models.MatchStatusLive, HomeScore: 5, AwayScore: 2, EventSequence: c.sequence}
+		if err := database.DB.Create(&match).Error; err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.UpdateMatch(admin, match.ID, c.req, AuditContext{}); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+		}
+	}
+}
+
+// liveGame is a live match in its first period between two teams with one
+// player each
+type liveGame struct {
+	admin      *models.User
+	home, away models.Team
+	homePlayer models.Player
+	awayPlayer models.Player
+	match      models.Match
+}
+
+func newLiveGame(t *testing.T) *liveGame {
+	t.Helper()
+	withTestDB(t, &models.Organization{}, &models.User{}, &models.Team{}, &models.Player{},
+		&models.Match{}, &models.MatchScorekeeper{}, &models.BracketNode{}, &models.MatchEvent{},
+		&models.PlayerStatistics{}, &models.TeamStatistics{}, &models.BoxScoreState{}, &models.AuditEvent{})
+	org := uuid.New()
+	g := &liveGame{
+		admin: &models.User{ID: uuid.New(), Role: models.RoleSuperAdmin},
+		home:  models.Team{ID: uuid.New(), OrganizationID: org, Name: "Home"},
+		away:  models.Team{ID: uuid.New(), OrganizationID: org, Name: "Away"},
+	}
+	if err := database.DB.Create([]*models.Team{&g.home, &g.away}).Error; err != nil {
+		t.Fatal(err)
+	}
+	g.homePlayer = models.Player{ID: uuid.New(), TeamID: g.home.ID, FullName: "Ana Smith", JerseyNumber: 7,
+		Position: models.PositionPointGuard, Status: models.PlayerStatusActive}
+	g.awayPlayer = models.Player{ID: uuid.New(), TeamID: g.away.ID, FullName: "Jo Lee", JerseyNumber: 12,
+		Position: models.PositionCenter, Status: models.PlayerStatusActive}
+	if err := database.DB.Create([]*models.Player{&g.homePlayer, &g.awayPlayer}).Error; err != nil {
+		t.Fatal(err)
+	}
+	g.match = models.Match{ID: uuid.New(), HomeTeamID: g.home.ID, AwayTeamID: g.away.ID, Venue: "Arena",
+		ScheduledAt: time.Now(), Status: models.MatchStatusLive, GameState: models.GameStatePeriod,
+		Period: 1, PeriodCount: 4, PeriodLength: 600, ClockRemainingMs: 600000}
+	if err := database.DB.Create(&g.match).Error; err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestSaveKeepsLiveChanges(t *testing.T) {
+	g := newLiveGame(t)
+	s := NewMatchService()
+	events := NewMatchEventService()
+
+	// An event is recorded between reading the match and saving the update
+	read, err := s.managedMatch(g.admin, g.match.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	point := RecordEventRequest{Type: "point", TeamID: g.home.ID, PlayerID: &g.homePlayer.ID, Points: 3}
+	if _, err := events.RecordEvent(g.admin, g.match.ID, point); err != nil {
+		t.Fatal(err)
+	}
+	notes := "Played behind closed doors"
+	_, err = s.save(read, models.AuditActionMatchUpdate, AuditContext{}, updateMatch(UpdateMatchRequest{Notes: &notes}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match, err := s.GetMatch(g.match.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.HomeScore != 3 || match.EventSequence != 1 || match.Notes == "" {
+		t.Errorf("got score %d, sequence %d, notes %q, want 3, 1 and the notes",
+			match.HomeScore, match.EventSequence, match.Notes)
+	}
+	// The next event takes the next sequence number
+	event, err := events.RecordEvent(g.admin, g.match.ID, point)
+	if err != nil {
+		t.Fatalf("recording after the update: %v", err)
+	}
+	if event.Sequence != 2 {
+		t.Errorf("got sequence %d, want 2", event.Sequence)
+	}
+}
+
+func TestSaveChecksLockedMatch(t *testing.T) {
+	g := newLiveGame(t)
+	s := NewMatchService()
+	completed := "completed"
+
+	// Both updates read the live match, the cancellation commits first
+	read, err := s.managedMatch(g.admin, g.match.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CancelMatch(g.admin, g.match.ID, CancelMatchRequest{Reason: "Flooded court"}, AuditContext{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.save(read, models.AuditActionMatchUpdate, AuditContext{}, updateMatch(UpdateMatchRequest{Status: &completed}))
+	if !errors.Is(err, ErrInvalidMatchTransition) {
+		t.Errorf("completing a cancelled match: got %v, want %v", err, ErrInvalidMatchTransition)
+	}
+	_, err = s.save(read, models.AuditActionMatchCancel, AuditContext{}, cancelMatch(CancelMatchRequest{Reason: "Again"}))
+	if !errors.Is(err, ErrInvalidMatchTransition) {
+		t.Errorf("cancelling twice: got %v, want %v", err, ErrInvalidMatchTransition)
+	}
+	_, err = s.save(read, models.AuditActionMatchReschedule, AuditContext{},
+		s.rescheduleMatch(RescheduleMatchRequest{ScheduledAt: time.Now().Add(time.Hour), Reason: "Later"}))
+	if !errors.Is(err, ErrMatchNotScheduled) {
+		t.Errorf("rescheduling a cancelled match: got %v, want %v", err, ErrMatchNotScheduled)
+	}
+
+	match, err := s.GetMatch(g.match.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.Status != models.MatchStatusCancelled || match.CancellationReason != "Flooded court" {
+		t.Errorf("got %s with reason %q, want the first cancellation kept", match.Status, match.CancellationReason)
+	}
+}
+
+// holdReads holds the reads of a table made outside a transaction until
+// each of n concurrent calls has made one or returned, so the calls all get
+// past their checks before any of them writes. Each call must run done when
+// it returns.
+func holdReads(t *testing.T, table string, n int) (done func()) {
+	t.Helper()
+	var mu sync.Mutex
+	changed := sync.NewCond(&mu)
+	arrived := 0
+	err := database.DB.Callback().Query().After("gorm:query").Replace("test:hold", func(db *gorm.DB) {
+		if _, inTx := db.Statement.ConnPool.(*sql.Tx); inTx || db.Statement.Table != table {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		arrived++
+		changed.Broadcast()
+		for arrived < n {
+			changed.Wait()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		arrived++
+		changed.Broadcast()
+	}
+}
+
+func TestCreateMatchConcurrently(t *testing.T) {
+	withTestDB(t, &models.Organization{}, &models.Team{}, &models.Match{}, &models.VenueLock{}, &models.AuditEvent{})
+	saved := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = saved })
+	config.AppConfig = &config.Config{MatchDuration: 2 * time.Hour, MatchSchedulingBuffer: 30 * time.Minute}
+	s := NewMatchService()
+	admin := &models.User{ID: uuid.New(), Role: models.RoleSuperAdmin}
+	org := models.Organization{ID: uuid.New(), Name: "League"}
+	if err := database.DB.Create(&org).Error; err != nil {
+		t.Fatal(err)
+	}
+	teams := make([]*models.Team, 4)
+	for i := range teams {
+		teams[i] = &models.Team{ID: uuid.New(), OrganizationID: org.ID, Name: fmt.Sprintf("Team %d", i)}
+	}
+	if err := database.DB.Create(teams).Error; err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().Add(24 * time.Hour)
+
+	cases := []struct {
+		name string
+		reqs []CreateMatchRequest
+	}{
+		{"same teams", []CreateMatchRequest{
+			{HomeTeamID: teams[0].ID, AwayTeamID: teams[1].ID, ScheduledAt: at, Venue: "North Gym"},
+			{HomeTeamID: teams[1].ID, AwayTeamID: teams[0].ID, ScheduledAt: at, Venue: "South Gym"},
+			{HomeTeamID: teams[0].ID, AwayTeamID: teams[1].ID, ScheduledAt: at.Add(time.Hour), Venue: "East Gym"},
+		}},
+		{"same venue", []CreateMatchRequest{
+			{HomeTeamID: teams[0].ID, AwayTeamID: teams[1].ID, ScheduledAt: at.Add(48 * time.Hour), Venue: "Arena"},
+			{HomeTeamID: teams[2].ID, AwayTeamID: teams[3].ID, ScheduledAt: at.Add(48 * time.Hour), Venue: "arena"},
+			{HomeTeamID: teams[3].ID, AwayTeamID: teams[2].ID, ScheduledAt: at.Add(49 * time.Hour), Venue: "Arena "},
+		}},
+	}
+	for _, c := range cases {
+		done := holdReads(t, "matches", len(c.reqs))
+		errs := make(chan error, len(c.reqs))
+		var wg sync.WaitGroup
+		for _, req := range c.reqs {
+			wg.Add(1)
+			go func(req CreateMatchRequest) {
+				defer wg.Done()
+				defer done()
+				_, err := s.CreateMatch(admin, req, AuditContext{})
+				errs <- err
+			}(req)
+		}
+		wg.Wait()
+		close(errs)
+
+		created := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				created++
+			case !errors.Is(err, ErrScheduleConflict):
+				t.Errorf("%s: got %v, want %v", c.name, err, ErrScheduleConflict)
+			}
+		}
+		if created != 1 {
+			t.Errorf("%s: %d matches created, want 1", c.name, created)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"errors"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrInvalidOrgAdmin      = errors.New("admin user must be an active org admin of the organization, or an active user of no organization when creating it")
+)
+
+type OrganizationService struct {
+	orgRepo      *repositories.OrganizationRepository
+	userRepo     *repositories.UserRepository
+	teamRepo     *repositories.TeamRepository
+	auditService *AuditService
+}
+
+func NewOrganizationService() *OrganizationService {
+	return &OrganizationService{
+		orgRepo:      repositories.NewOrganizationRepository(),
+		userRepo:     repositories.NewUserRepository(),
+		teamRepo:     repositories.NewTeamRepository(),
+		auditService: NewAuditService(),
+	}
+}
+
+type CreateOrganizationRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Email       string     `json:"email,omitempty" binding:"omitempty,email"`
+	Phone       string     `json:"phone,omitempty"`
+	Address     string     `json:"address,omitempty"`
+	LogoURL     string     `json:"logo_url,omitempty" binding:"omitempty,url"`
+	AdminUserID *uuid.UUID `json:"admin_user_id,omitempty"`
+	Status      string     `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name        *string    `json:"name,omitempty" binding:"omitempty,min=1"`
+	Email       *string    `json:"email,omitempty" binding:"omitempty,email"`
+	Phone       *string    `json:"phone,omitempty"`
+	Address     *string    `json:"address,omitempty"`
+	LogoURL     *string    `json:"logo_url,omitempty" binding:"omitempty,url"`
+	AdminUserID *uuid.UUID `json:"admin_user_id,omitempty"`
+	Status      *string    `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+}
+
+// UpdateOrganizationProfileRequest holds the fields an organization admin may change
+type UpdateOrganizationProfileRequest struct {
+	Name    *string `json:"name,omitempty" binding:"omitempty,min=1"`
+	Email   *string `json:"email,omitempty" binding:"omitempty,email"`
+	Phone   *string `json:"phone,omitempty"`
+	Address *string `json:"address,omitempty"`
+	LogoURL *string `json:"logo_url,omitempty" binding:"omitempty,url"`
+}
+
+type OrganizationStats struct {
+	TotalMembers  int64 `json:"total_members"`
+	ActiveMembers int64 `json:"active_members"`
+	OrgAdmins     int64 `json:"org_admins"`
+	TeamMembers   int64 `json:"team_members"`
+	TotalTeams    int64 `json:"total_teams"`
+	ActiveTeams   int64 `json:"active_teams"`
+}
+
+// GetOrganization gets an organization by ID
+func (s *OrganizationService) GetOrganization(id uuid.UUID) (*models.Organization, error) {
+	org, err := s.orgRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, nil
+}
+
+// ListOrganizations gets organizations matching the filters with pagination
+func (s *OrganizationService) ListOrganizations(offset, limit int, filters map[string]interface{}) ([]models.Organization, int64, error) {
+	return s.orgRepo.List(offset, limit, filters)
+}
+
+// CreateOrganization creates an organization and records it in the audit log
+func (s *OrganizationService) CreateOrganization(req CreateOrganizationRequest, actx AuditContext) (*models.Organization, error) {
+	status := models.OrgStatusActive
+	if req.Status != "" {
+		status = models.OrganizationStatus(req.Status)
+	}
+
+	org := &models.Organization{
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		LogoURL:     req.LogoURL,
+		AdminUserID: req.AdminUserID,
+		Status:      status,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.orgRepo.WithTx(tx).Create(org); err != nil {
+			return err
+		}
+		if err := s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionOrganizationCreate,
+			TargetType: models.AuditTargetOrganization,
+			TargetID:   &org.ID,
+			After:      org,
+		}); err != nil {
+			return err
+		}
+		if org.AdminUserID == nil {
+			return nil
+		}
+		return s.joinAsAdmin(tx, org, actx)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidOrgAdmin) {
+			return nil, err
+		}
+		return nil, errors.New("failed to create organization")
+	}
+
+	return s.GetOrganization(org.ID)
+}
+
+// UpdateOrganization applies the non-nil fields of req to an organization and records the diff
+func (s *OrganizationService) UpdateOrganization(id uuid.UUID, req UpdateOrganizationRequest, actx AuditContext) (*models.Organization, error) {
+	org, err := s.GetOrganization(id)
+	if err != nil {
+		return nil, err
+	}
+	before := *org
+	org.AdminUser = nil
+
+	if req.Name != nil {
+		org.Name = *req.Name
+	}
+	if req.Email != nil {
+		org.Email = *req.Email
+	}
+	if req.Phone != nil {
+		org.Phone = *req.Phone
+	}
+	if req.Address != nil {
+		org.Address = *req.Address
+	}
+	if req.LogoURL != nil {
+		org.LogoURL = *req.LogoURL
+	}
+	if req.AdminUserID != nil && (org.AdminUserID == nil || *org.AdminUserID != *req.AdminUserID) {
+		if err := s.validateAdminUser(org.ID, *req.AdminUserID); err != nil {
+			return nil, err
+		}
+		org.AdminUserID = req.AdminUserID
+	}
+	if req.Status != nil {
+		org.Status = models.OrganizationStatus(*req.Status)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.orgRepo.WithTx(tx).Update(org); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionOrganizationUpdate,
+			TargetType: models.AuditTargetOrganization,
+			TargetID:   &org.ID,
+			Before:     &before,
+			After:      org,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to update organization")
+	}
+
+	return s.GetOrganization(org.ID)
+}
+
+// DeleteOrganization soft deletes an organization and records it in the audit log
+func (s *OrganizationService) DeleteOrganization(id uuid.UUID, actx AuditContext) error {
+	org, err := s.GetOrganization(id)
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.orgRepo.WithTx(tx).Delete(org.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionOrganizationDelete,
+			TargetType: models.AuditTargetOrganization,
+			TargetID:   &org.ID,
+			Before:     org,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to delete organization")
+	}
+	return nil
+}
+
+// UpdateOrganizationProfile updates the self-service profile fields of an organization
+func (s *OrganizationService) UpdateOrganizationProfile(id uuid.UUID, req UpdateOrganizationProfileRequest, actx AuditContext) (*models.Organization, error) {
+	return s.UpdateOrganization(id, UpdateOrganizationRequest{
+		Name:    req.Name,
+		Email:   req.Email,
+		Phone:   req.Phone,
+		Address: req.Address,
+		LogoURL: req.LogoURL,
+	}, actx)
+}
+
+// GetOrganizationStats collects member statistics for a single organization
+func (s *OrganizationService) GetOrganizationStats(id uuid.UUID) (*OrganizationStats, error) {
+	if _, err := s.GetOrganization(id); err != nil {
+		return nil, err
+	}
+
+	count := func(extra map[string]interface{}) (int64, error) {
+		filters := map[string]interface{}{"organization_id": id}
+		for key, value := range extra {
+			filters[key] = value
+		}
+		return s.userRepo.Count(filters)
+	}
+
+	var stats OrganizationStats
+	var err error
+	if stats.TotalMembers, err = count(nil); err != nil {
+		return nil, err
+	}
+	if stats.ActiveMembers, err = count(map[string]interface{}{"status": models.UserStatusActive}); err != nil {
+		return nil, err
+	}
+	if stats.OrgAdmins, err = count(map[string]interface{}{"role": models.RoleOrgAdmin}); err != nil {
+		return nil, err
+	}
+	if stats.TeamMembers, err = count(map[string]interface{}{"role": models.RoleTeamMember}); err != nil {
+		return nil, err
+	}
+	if stats.TotalTeams, err = s.teamRepo.Count(map[string]interface{}{"organization_id": id}); err != nil {
+		return nil, err
+	}
+	if stats.ActiveTeams, err = s.teamRepo.Count(map[string]interface{}{"organization_id": id, "status": models.TeamStatusActive}); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// validateAdminUser checks a user can be named an organization's admin: an
+// active org admin who is a member of the organization
+func (s *OrganizationService) validateAdminUser(orgID, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || !user.IsActive() || user.Role != models.RoleOrgAdmin ||
+		user.OrganizationID == nil || *user.OrganizationID != orgID {
+		return ErrInvalidOrgAdmin
+	}
+	return nil
+}
+
+// joinAsAdmin makes the admin user named on a new organization its org admin.
+// The user must be active, not a super admin and in no other organization.
+func (s *OrganizationService) joinAsAdmin(tx *gorm.DB, org *models.Organization, actx AuditContext) error {
+	userRepo := s.userRepo.WithTx(tx)
+	user, err := userRepo.GetByID(*org.AdminUserID)
+	if err != nil || !user.IsActive() || user.IsAdmin() || user.OrganizationID != nil {
+		return ErrInvalidOrgAdmin
+	}
+	before := *user
+	user.Organization = nil
+	user.OrganizationID = &org.ID
+	user.Role = models.RoleOrgAdmin
+
+	if err := userRepo.Update(user); err != nil {
+		return err
+	}
+	return s.auditService.Record(tx, actx, AuditEntry{
+		Action:     models.AuditActionUserUpdate,
+		TargetType: models.AuditTargetUser,
+		TargetID:   &user.ID,
+		Before:     &before,
+		After:      user,
+	})
+}
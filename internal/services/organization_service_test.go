@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestOrganizationAdminUser(t *testing.T) {
+	withTestDB(t, &models.Organization{}, &models.User{}, &models.AuditEvent{})
+	s := NewOrganizationService()
+	org, err := s.CreateOrganization(CreateOrganizationRequest{Name: "Club"}, AuditContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.CreateOrganization(CreateOrganizationRequest{Name: "Rivals"}, AuditContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := func(role models.UserRole, status models.UserStatus, orgID *uuid.UUID) uuid.UUID {
+		t.Helper()
+		u := models.User{Email: uuid.NewString() + "@example.com", Password: "hash", FullName: "Sam Lee",
+			Role: role, Status: status, OrganizationID: orgID}
+		if err := database.DB.Create(&u).Error; err != nil {
+			t.Fatal(err)
+		}
+		return u.ID
+	}
+
+	// Naming the admin of an existing organization, who must be one of its
+	// org admins
+	updates := []struct {
+		name string
+		user uuid.UUID
+		err  error
+	}{
+		{"unknown user", uuid.New(), ErrInvalidOrgAdmin},
+		{"inactive user", user(models.RoleOrgAdmin, models.UserStatusInactive, &org.ID), ErrInvalidOrgAdmin},
+		{"super admin", user(models.RoleSuperAdmin, models.UserStatusActive, &org.ID), ErrInvalidOrgAdmin},
+		{"team member", user(models.RoleTeamMember, models.UserStatusActive, &org.ID), ErrInvalidOrgAdmin},
+		{"org admin of another organization", user(models.RoleOrgAdmin, models.UserStatusActive, &other.ID), ErrInvalidOrgAdmin},
+		{"org admin of no organization", user(models.RoleOrgAdmin, models.UserStatusActive, nil), ErrInvalidOrgAdmin},
+		{"active org admin", user(models.RoleOrgAdmin, models.UserStatusActive, &org.ID), nil},
+	}
+	for _, c := range updates {
+		id := c.user
+		if _, err := s.UpdateOrganization(org.ID, UpdateOrganizationRequest{AdminUserID: &id}, AuditContext{}); !errors.Is(err, c.err) {
+			t.Errorf("update with %s: got %v, want %v", c.name, err, c.err)
+		}
+	}
+
+	// Naming the admin of a new organization, who joins it as its org admin
+	creates := []struct {
+		name string
+		user uuid.UUID
+		err  error
+	}{
+		{"unknown user", uuid.New(), ErrInvalidOrgAdmin},
+		{"inactive user", user(models.RoleTeamMember, models.UserStatusInactive, nil), ErrInvalidOrgAdmin},
+		{"super admin", user(models.RoleSuperAdmin, models.UserStatusActive, nil), ErrInvalidOrgAdmin},
+		{"org admin of another organization", user(models.RoleOrgAdmin, models.UserStatusActive, &other.ID), ErrInvalidOrgAdmin},
+		{"member of another organization", user(models.RoleTeamMember, models.UserStatusActive, &other.ID), ErrInvalidOrgAdmin},
+		{"user of no organization", user(models.RolePublic, models.UserStatusActive, nil), nil},
+	}
+	for _, c := range creates {
+		id := c.user
+		created, err := s.CreateOrganization(CreateOrganizationRequest{Name: c.name, AdminUserID: &id}, AuditContext{})
+		if !errors.Is(err, c.err) {
+			t.Errorf("create with %s: got %v, want %v", c.name, err, c.err)
+		}
+		if err != nil {
+			var count int64
+			if err := database.DB.Model(&models.Organization{}).Where("name = ?", c.name).Count(&count).Error; err != nil {
+				t.Fatal(err)
+			}
+			if count != 0 {
+				t.Errorf("create with %s: organization created", c.name)
+			}
+			continue
+		}
+		var admin models.User
+		if err := database.DB.First(&admin, "id = ?", id).Error; err != nil {
+			t.Fatal(err)
+		}
+		if admin.OrganizationID == nil || *admin.OrganizationID != created.ID || admin.Role != models.RoleOrgAdmin {
+			t.Errorf("create with %s: admin has organization %v and role %s, want %s and %s",
+				c.name, admin.OrganizationID, admin.Role, created.ID, models.RoleOrgAdmin)
+		}
+	}
+}
@@ -0,0 +1,589 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPlayerNotFound      = errors.New("player not found")
+	ErrJerseyNumberTaken   = errors.New("jersey number already taken by an active player of this team")
+	ErrInvalidDateOfBirth  = errors.New("date_of_birth must be formatted as YYYY-MM-DD")
+	ErrInvalidPlayerUser   = errors.New("linked user must be an active team member of the team's organization")
+	ErrUserAlreadyLinked   = errors.New("user is already linked to another player")
+	ErrInvalidDate         = errors.New("dates must be formatted as YYYY-MM-DD")
+	ErrSameTeamTransfer    = errors.New("player is already on this team")
+	ErrInvalidTransferDate = errors.New("effective_date must be between the start of the current stint and today")
+	ErrTransferBeforeMatch = errors.New("effective_date must be after the player's matches for the current team")
+)
+
+// dateLayout is the format of calendar dates in requests
+const dateLayout = "2006-01-02"
+
+type PlayerService struct {
+	playerRepo   *repositories.PlayerRepository
+	rosterRepo   *repositories.RosterRepository
+	teamRepo     *repositories.TeamRepository
+	userRepo     *repositories.UserRepository
+	statsRepo    *repositories.StatisticsRepository
+	auditService *AuditService
+}
+
+func NewPlayerService() *PlayerService {
+	return &PlayerService{
+		playerRepo:   repositories.NewPlayerRepository(),
+		rosterRepo:   repositories.NewRosterRepository(),
+		teamRepo:     repositories.NewTeamRepository(),
+		userRepo:     repositories.NewUserRepository(),
+		statsRepo:    repositories.NewStatisticsRepository(),
+		auditService: NewAuditService(),
+	}
+}
+
+type CreatePlayerRequest struct {
+	TeamID       uuid.UUID  `json:"team_id"` // Taken from the path on /teams/:id/players
+	JerseyNumber *int       `json:"jersey_number" binding:"required,min=0,max=99"`
+	FullName     string     `json:"full_name" binding:"required"`
+	Position     string     `json:"position" binding:"required,oneof=PG SG SF PF C"`
+	HeightCM     *int       `json:"height_cm,omitempty" binding:"omitempty,min=100,max=260"`
+	WeightKG     *float64   `json:"weight_kg,omitempty" binding:"omitempty,min=30,max=200"`
+	DateOfBirth  string     `json:"date_of_birth,omitempty"` // YYYY-MM-DD
+	PhotoURL     string     `json:"photo_url,omitempty" binding:"omitempty,url"`
+	Status       string     `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+	UserID       *uuid.UUID `json:"user_id,omitempty"`
+}
+
+type UpdatePlayerRequest struct {
+	JerseyNumber *int       `json:"jersey_number,omitempty" binding:"omitempty,min=0,max=99"`
+	FullName     *string    `json:"full_name,omitempty" binding:"omitempty,min=1"`
+	Position     *string    `json:"position,omitempty" binding:"omitempty,oneof=PG SG SF PF C"`
+	HeightCM     *int       `json:"height_cm,omitempty" binding:"omitempty,min=100,max=260"`
+	WeightKG     *float64   `json:"weight_kg,omitempty" binding:"omitempty,min=30,max=200"`
+	DateOfBirth  *string    `json:"date_of_birth,omitempty"` // YYYY-MM-DD
+	PhotoURL     *string    `json:"photo_url,omitempty" binding:"omitempty,url"`
+	Status       *string    `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+	UserID       *uuid.UUID `json:"user_id,omitempty"`
+}
+
+// TransferPlayerRequest moves a player to another team
+type TransferPlayerRequest struct {
+	TeamID        uuid.UUID `json:"team_id" binding:"required"`
+	JerseyNumber  *int      `json:"jersey_number,omitempty" binding:"omitempty,min=0,max=99"` // Defaults to the current number
+	EffectiveDate string    `json:"effective_date,omitempty"`                                 // YYYY-MM-DD, defaults to today
+}
+
+// GetPlayer gets a player by ID
+func (s *PlayerService) GetPlayer(id uuid.UUID) (*models.Player, error) {
+	player, err := s.playerRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrPlayerNotFound
+	}
+	return player, nil
+}
+
+// GetPlayerForUser gets the player profile linked to a user account
+func (s *PlayerService) GetPlayerForUser(userID uuid.UUID) (*models.Player, error) {
+	player, err := s.playerRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrPlayerNotFound
+	}
+	return player, nil
+}
+
+// ListPlayers gets players matching the filters with pagination
+func (s *PlayerService) ListPlayers(offset, limit int, filters map[string]interface{}) ([]models.Player, int64, error) {
+	return s.playerRepo.List(offset, limit, filters)
+}
+
+// CreatePlayer adds a player to a team the actor manages
+func (s *PlayerService) CreatePlayer(actor *models.User, req CreatePlayerRequest, actx AuditContext) (*models.Player, error) {
+	team, err := s.managedTeam(actor, req.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	dob, err := parseDate(req.DateOfBirth, ErrInvalidDateOfBirth)
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.PlayerStatusActive
+	if req.Status != "" {
+		status = models.PlayerStatus(req.Status)
+	}
+
+	player := &models.Player{
+		TeamID:       team.ID,
+		JerseyNumber: *req.JerseyNumber,
+		FullName:     req.FullName,
+		Position:     models.PlayerPosition(req.Position),
+		HeightCM:     req.HeightCM,
+		WeightKG:     req.WeightKG,
+		DateOfBirth:  dob,
+		PhotoURL:     req.PhotoURL,
+		Status:       status,
+	}
+	if req.UserID != nil {
+		if err := s.validateUserLink(team, *req.UserID, uuid.Nil); err != nil {
+			return nil, err
+		}
+		player.UserID = req.UserID
+	}
+	if err := s.checkJersey(player); err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.playerRepo.WithTx(tx).Create(player); err != nil {
+			return err
+		}
+		if err := s.rosterRepo.WithTx(tx).Create(&models.RosterMembership{
+			PlayerID:     player.ID,
+			TeamID:       player.TeamID,
+			JerseyNumber: player.JerseyNumber,
+			StartDate:    today(),
+		}); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionPlayerCreate,
+			TargetType: models.AuditTargetPlayer,
+			TargetID:   &player.ID,
+			After:      player,
+		})
+	})
+	if err != nil {
+		if conflict := s.playerConflict(player, err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, errors.New("failed to create player")
+	}
+
+	return s.GetPlayer(player.ID)
+}
+
+// UpdatePlayer applies the non-nil fields of req to a player the actor manages
+func (s *PlayerService) UpdatePlayer(actor *models.User, id uuid.UUID, req UpdatePlayerRequest, actx AuditContext) (*models.Player, error) {
+	player, err := s.GetPlayer(id)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.managedTeam(actor, player.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	before := *player
+	player.Team = nil
+
+	if req.JerseyNumber != nil {
+		player.JerseyNumber = *req.JerseyNumber
+	}
+	if req.FullName != nil {
+		player.FullName = *req.FullName
+	}
+	if req.Position != nil {
+		player.Position = models.PlayerPosition(*req.Position)
+	}
+	if req.HeightCM != nil {
+		player.HeightCM = req.HeightCM
+	}
+	if req.WeightKG != nil {
+		player.WeightKG = req.WeightKG
+	}
+	if req.DateOfBirth != nil {
+		dob, err := parseDate(*req.DateOfBirth, ErrInvalidDateOfBirth)
+		if err != nil {
+			return nil, err
+		}
+		player.DateOfBirth = dob
+	}
+	if req.PhotoURL != nil {
+		player.PhotoURL = *req.PhotoURL
+	}
+	if req.Status != nil {
+		player.Status = models.PlayerStatus(*req.Status)
+	}
+	if req.UserID != nil && (player.UserID == nil || *player.UserID != *req.UserID) {
+		if err := s.validateUserLink(team, *req.UserID, player.ID); err != nil {
+			return nil, err
+		}
+		player.UserID = req.UserID
+	}
+	if err := s.checkJersey(player); err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.playerRepo.WithTx(tx).Update(player); err != nil {
+			return err
+		}
+		if err := s.updateStint(tx, &before, player); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionPlayerUpdate,
+			TargetType: models.AuditTargetPlayer,
+			TargetID:   &player.ID,
+			Before:     &before,
+			After:      player,
+		})
+	})
+	if err != nil {
+		if conflict := s.playerConflict(player, err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, errors.New("failed to update player")
+	}
+
+	return s.GetPlayer(player.ID)
+}
+
+// DeletePlayer soft deletes a player the actor manages
+func (s *PlayerService) DeletePlayer(actor *models.User, id uuid.UUID, actx AuditContext) error {
+	player, err := s.GetPlayer(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.managedTeam(actor, player.TeamID); err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.playerRepo.WithTx(tx).Delete(player.ID); err != nil {
+			return err
+		}
+		if current, err := s.rosterRepo.WithTx(tx).GetCurrent(player.ID); err == nil {
+			if err := s.rosterRepo.WithTx(tx).Close(current.ID, today()); err != nil {
+				return err
+			}
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionPlayerDelete,
+			TargetType: models.AuditTargetPlayer,
+			TargetID:   &player.ID,
+			Before:     player,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to delete player")
+	}
+	return nil
+}
+
+// TransferPlayer closes the player's current stint and opens one on the
+// destination team, effective from the given date
+func (s *PlayerService) TransferPlayer(actor *models.User, id uuid.UUID, req TransferPlayerRequest, actx AuditContext) (*models.Player, error) {
+	player, err := s.GetPlayer(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.managedTeam(actor, player.TeamID); err != nil {
+		return nil, err
+	}
+	if req.TeamID == player.TeamID {
+		return nil, ErrSameTeamTransfer
+	}
+	team, err := s.managedTeam(actor, req.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := today()
+	if req.EffectiveDate != "" {
+		date, err := parseDate(req.EffectiveDate, ErrInvalidDate)
+		if err != nil {
+			return nil, err
+		}
+		effective = *date
+	}
+	if effective.After(today()) {
+		return nil, ErrInvalidTransferDate
+	}
+	if player.IsActive() {
+		current, err := s.currentStint(s.rosterRepo, player)
+		if err != nil {
+			return nil, err
+		}
+		if effective.Before(current.StartDate) {
+			return nil, ErrInvalidTransferDate
+		}
+	}
+	// The old stint must still cover the matches the player played for it
+	played, err := s.statsRepo.PlayedForSince(player.ID, player.TeamID, effective)
+	if err != nil {
+		return nil, err
+	}
+	if played {
+		return nil, ErrTransferBeforeMatch
+	}
+
+	if player.UserID != nil {
+		// The linked account must belong to the destination organization
+		if err := s.validateUserLink(team, *player.UserID, player.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	before := *player
+	player.Team = nil
+	player.TeamID = team.ID
+	if req.JerseyNumber != nil {
+		player.JerseyNumber = *req.JerseyNumber
+	}
+	if err := s.checkJersey(player); err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.playerRepo.WithTx(tx).Update(player); err != nil {
+			return err
+		}
+		// Inactive players are on no roster until they are reactivated
+		if player.IsActive() {
+			if err := s.moveStint(tx, &before, player.TeamID, player.JerseyNumber, effective); err != nil {
+				return err
+			}
+		} else if err := closeOpenStint(s.rosterRepo.WithTx(tx), player.ID, effective); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionPlayerTransfer,
+			TargetType: models.AuditTargetPlayer,
+			TargetID:   &player.ID,
+			Before:     &before,
+			After:      player,
+		})
+	})
+	if err != nil {
+		if conflict := s.playerConflict(player, err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, errors.New("failed to transfer player")
+	}
+
+	return s.GetPlayer(player.ID)
+}
+
+// GetPlayerHistory gets every roster stint of a player, most recent first
+func (s *PlayerService) GetPlayerHistory(id uuid.UUID) ([]models.RosterMembership, error) {
+	if _, err := s.GetPlayer(id); err != nil {
+		return nil, err
+	}
+	return s.rosterRepo.ListByPlayer(id)
+}
+
+// GetTeamRoster gets the players who were on a team on the given date
+func (s *PlayerService) GetTeamRoster(teamID uuid.UUID, date time.Time, offset, limit int) ([]models.RosterMembership, int64, error) {
+	if _, err := s.teamRepo.GetByID(teamID); err != nil {
+		return nil, 0, ErrTeamNotFound
+	}
+	return s.rosterRepo.ListByTeamAsOf(teamID, date, offset, limit)
+}
+
+// updateStint keeps the roster history in step with a player update. A
+// deactivated player leaves the roster and a reactivated one rejoins it,
+// while a new number starts a new stint so history keeps the number worn at
+// the time.
+func (s *PlayerService) updateStint(tx *gorm.DB, before, player *models.Player) error {
+	rosterRepo := s.rosterRepo.WithTx(tx)
+	switch {
+	case before.IsActive() && !player.IsActive():
+		current, err := s.currentStint(rosterRepo, before)
+		if err != nil {
+			return err
+		}
+		end := today()
+		if current.ID == uuid.Nil {
+			// Materialize the implied stint of players created before roster history
+			current.EndDate = &end
+			return rosterRepo.Create(current)
+		}
+		return rosterRepo.Close(current.ID, end)
+	case !before.IsActive() && player.IsActive():
+		// Players deactivated before leaving the roster was recorded still
+		// have their stint open
+		if _, err := rosterRepo.GetCurrent(player.ID); err == nil {
+			break
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return rosterRepo.Create(&models.RosterMembership{
+			PlayerID:     player.ID,
+			TeamID:       player.TeamID,
+			JerseyNumber: player.JerseyNumber,
+			StartDate:    today(),
+		})
+	case !player.IsActive():
+		return nil
+	}
+	if player.JerseyNumber != before.JerseyNumber {
+		return s.moveStint(tx, before, player.TeamID, player.JerseyNumber, today())
+	}
+	return nil
+}
+
+// closeOpenStint ends the open stint of a player, if there is one
+func closeOpenStint(rosterRepo *repositories.RosterRepository, playerID uuid.UUID, end time.Time) error {
+	current, err := rosterRepo.GetCurrent(playerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return rosterRepo.Close(current.ID, end)
+}
+
+// moveStint closes the current stint of the player described by before and
+// opens a new one on teamID from the given date
+func (s *PlayerService) moveStint(tx *gorm.DB, before *models.Player, teamID uuid.UUID, jerseyNumber int, from time.Time) error {
+	rosterRepo := s.rosterRepo.WithTx(tx)
+	current, err := s.currentStint(rosterRepo, before)
+	if err != nil {
+		return err
+	}
+	if current.ID != uuid.Nil && current.StartDate.Equal(from) {
+		// A stint that would end the day it started is replaced rather than kept
+		current.TeamID = teamID
+		current.JerseyNumber = jerseyNumber
+		return rosterRepo.Update(current)
+	}
+	if current.ID == uuid.Nil {
+		// Materialize the implied stint of players created before roster history
+		end := from
+		current.EndDate = &end
+		if err := rosterRepo.Create(current); err != nil {
+			return err
+		}
+	} else if err := rosterRepo.Close(current.ID, from); err != nil {
+		return err
+	}
+	return rosterRepo.Create(&models.RosterMembership{
+		PlayerID:     before.ID,
+		TeamID:       teamID,
+		JerseyNumber: jerseyNumber,
+		StartDate:    from,
+	})
+}
+
+// currentStint gets the open stint of a player. Players created before roster
+// history was recorded get an unsaved stint starting on their creation date.
+func (s *PlayerService) currentStint(rosterRepo *repositories.RosterRepository, player *models.Player) (*models.RosterMembership, error) {
+	current, err := rosterRepo.GetCurrent(player.ID)
+	if err == nil {
+		current.StartDate = dateOf(current.StartDate)
+		return current, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return &models.RosterMembership{
+		PlayerID:     player.ID,
+		TeamID:       player.TeamID,
+		JerseyNumber: player.JerseyNumber,
+		StartDate:    dateOf(player.CreatedAt),
+	}, nil
+}
+
+// jerseyNumbersAsOf gets the numbers players wore on a date, from the roster
+// stints covering it. Players without one are left out, so callers fall
+// back to the current number.
+func jerseyNumbersAsOf(rosterRepo *repositories.RosterRepository, playerIDs []uuid.UUID, date time.Time) (map[uuid.UUID]int, error) {
+	stints, err := rosterRepo.ListByPlayersAsOf(playerIDs, date)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make(map[uuid.UUID]int, len(stints))
+	for _, stint := range stints {
+		numbers[stint.PlayerID] = stint.JerseyNumber
+	}
+	return numbers, nil
+}
+
+// managedTeam loads a team and checks the actor manages its organization
+func (s *PlayerService) managedTeam(actor *models.User, teamID uuid.UUID) (*models.Team, error) {
+	team, err := s.teamRepo.GetByID(teamID)
+	if err != nil {
+		return nil, ErrTeamNotFound
+	}
+	if !actor.CanManageOrganization(team.OrganizationID) {
+		return nil, ErrForbidden
+	}
+	return team, nil
+}
+
+// checkJersey rejects the player's number if another active player of the
+// team already wears it. The unique index is the final guard against races.
+func (s *PlayerService) checkJersey(player *models.Player) error {
+	if !player.IsActive() {
+		return nil
+	}
+	taken, err := s.playerRepo.JerseyTaken(player.TeamID, player.JerseyNumber, player.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrJerseyNumberTaken
+	}
+	return nil
+}
+
+// validateUserLink checks a user can be linked to a player of the team
+func (s *PlayerService) validateUserLink(team *models.Team, userID, playerID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.Role != models.RoleTeamMember || !user.IsActive() || user.OrganizationID == nil || *user.OrganizationID != team.OrganizationID {
+		return ErrInvalidPlayerUser
+	}
+	if linked, err := s.playerRepo.GetByUserID(userID); err == nil && linked.ID != playerID {
+		return ErrUserAlreadyLinked
+	}
+	return nil
+}
+
+// playerConflict tells which unique index of a player a duplicate key error
+// hit, when another request got in between the checks and the write. It
+// returns nil for any other error.
+func (s *PlayerService) playerConflict(player *models.Player, err error) error {
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil
+	}
+	if player.UserID != nil {
+		if linked, err := s.playerRepo.GetByUserID(*player.UserID); err == nil && linked.ID != player.ID {
+			return ErrUserAlreadyLinked
+		}
+	}
+	return ErrJerseyNumberTaken
+}
+
+// parseDate parses an optional YYYY-MM-DD date, returning invalid on bad input
+func parseDate(value string, invalid error) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, invalid
+	}
+	return &t, nil
+}
+
+// today returns the current UTC calendar date
+func today() time.Time {
+	return dateOf(time.Now().UTC())
+}
+
+// dateOf returns the calendar date of t as midnight UTC, so dates read back
+// from the database compare equal regardless of the connection time zone
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
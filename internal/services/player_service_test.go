@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// withTestDB points the database at an empty in-memory SQLite database with
+// the given tables
+func withTestDB(t *testing.T, tables ...interface{}) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // Every connection would get its own database
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatal(err)
+	}
+	saved := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = saved
+		sqlDB.Close()
+	})
+}
+
+func TestPlayerUserLink(t *testing.T) {
+	withTestDB(t, &models.Organization{}, &models.User{}, &models.Team{}, &models.Player{})
+	s := &PlayerService{playerRepo: repositories.NewPlayerRepository()}
+	team, user := uuid.New(), uuid.New()
+	player := func(number int) *models.Player {
+		return &models.Player{TeamID: team, UserID: &user, JerseyNumber: number, FullName: "Sam Lee",
+			Position: models.PositionCenter, Status: models.PlayerStatusActive}
+	}
+
+	first := player(4)
+	if err := s.playerRepo.Create(first); err != nil {
+		t.Fatal(err)
+	}
+	// Two requests linking the same user pass the checks, the second write fails
+	err := s.playerRepo.Create(player(5))
+	if conflict := s.playerConflict(player(5), err); !errors.Is(conflict, ErrUserAlreadyLinked) {
+		t.Errorf("second link of a user: got %v from %v, want %v", conflict, err, ErrUserAlreadyLinked)
+	}
+	jersey := player(4)
+	jersey.UserID = nil
+	err = s.playerRepo.Create(jersey)
+	if conflict := s.playerConflict(jersey, err); !errors.Is(conflict, ErrJerseyNumberTaken) {
+		t.Errorf("taken jersey: got %v from %v, want %v", conflict, err, ErrJerseyNumberTaken)
+	}
+	if conflict := s.playerConflict(first, errors.New("connection lost")); conflict != nil {
+		t.Errorf("other error: got %v, want none", conflict)
+	}
+
+	// Deleting the player frees the user for a new one
+	if err := s.playerRepo.Delete(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.playerRepo.Create(player(5)); err != nil {
+		t.Errorf("linking the user of a deleted player: %v", err)
+	}
+}
+
+func TestPlayerStatusStints(t *testing.T) {
+	withTestDB(t, &models.Organization{}, &models.User{}, &models.Team{}, &models.Player{},
+		&models.RosterMembership{}, &models.AuditEvent{})
+	s := NewPlayerService()
+	admin := &models.User{ID: uuid.New(), Role: models.RoleSuperAdmin}
+	team := models.Team{ID: uuid.New(), OrganizationID: uuid.New(), Name: "Home"}
+	if err := database.DB.Create(&team).Error; err != nil {
+		t.Fatal(err)
+	}
+	number := 7
+	player, err := s.CreatePlayer(admin, CreatePlayerRequest{TeamID: team.ID, JerseyNumber: &number,
+		FullName: "Ana Smith", Position: "PG"}, AuditContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := func(s string) *string { return &s }
+	status := func(value string) UpdatePlayerRequest { return UpdatePlayerRequest{Status: &value} }
+	rostered := func() bool {
+		t.Helper()
+		roster, _, err := s.GetTeamRoster(team.ID, today(), 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(roster) == 1
+	}
+
+	cases := []struct {
+		name     string
+		req      UpdatePlayerRequest
+		rostered bool
+		stints   int
+	}{
+		{"deactivated", status("inactive"), false, 1},
+		{"still inactive", UpdatePlayerRequest{Position: text("SG")}, false, 1},
+		{"reactivated", status("active"), true, 2},
+		{"active again", UpdatePlayerRequest{Position: text("PG")}, true, 2},
+	}
+	for _, c := range cases {
+		if _, err := s.UpdatePlayer(admin, player.ID, c.req, AuditContext{}); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got := rostered(); got != c.rostered {
+			t.Errorf("%s: got rostered %v, want %v", c.name, got, c.rostered)
+		}
+		history, err := s.GetPlayerHistory(player.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		open := 0
+		for _, stint := range history {
+			if stint.EndDate == nil {
+				open++
+			}
+		}
+		if len(history) != c.stints || (open == 1) != c.rostered || open > 1 {
+			t.Errorf("%s: got %d stints, %d open, want %d", c.name, len(history), open, c.stints)
+		}
+	}
+}
+
+func TestBackdatedTransfer(t *testing.T) {
+	withTestDB(t, &models.Organization{}, &models.User{}, &models.Team{}, &models.Player{},
+		&models.RosterMembership{}, &models.PlayerStatistics{}, &models.AuditEvent{})
+	s := NewPlayerService()
+	admin := &models.User{ID: uuid.New(), Role: models.RoleSuperAdmin}
+	org := uuid.New()
+	from := models.Team{ID: uuid.New(), OrganizationID: org, Name: "From"}
+	to := models.Team{ID: uuid.New(), OrganizationID: org, Name: "To"}
+	if err := database.DB.Create([]*models.Team{&from, &to}).Error; err != nil {
+		t.Fatal(err)
+	}
+	day := func(daysAgo int) time.Time { return today().AddDate(0, 0, -daysAgo) }
+	player := models.Player{ID: uuid.New(), TeamID: from.ID, FullName: "Ana Smith", JerseyNumber: 7,
+		Position: models.PositionPointGuard, Status: models.PlayerStatusActive}
+	if err := database.DB.Create(&player).Error; err != nil {
+		t.Fatal(err)
+	}
+	stint := models.RosterMembership{PlayerID: player.ID, TeamID: from.ID, JerseyNumber: 7, StartDate: day(30)}
+	if err := database.DB.Create(&stint).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Played for the old team five days ago, in the evening
+	line := models.PlayerStatistics{MatchID: uuid.New(), PlayerID: player.ID, TeamID: from.ID,
+		GameContext: models.GameContext{PlayedAt: day(5).Add(19 * time.Hour)}}
+	if err := database.DB.Create(&line).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		daysAgo int
+		err     error
+	}{
+		{31, ErrInvalidTransferDate},
+		{10, ErrTransferBeforeMatch},
+		{5, ErrTransferBeforeMatch},
+		{4, nil},
+	}
+	for _, c := range cases {
+		req := TransferPlayerRequest{TeamID: to.ID, EffectiveDate: day(c.daysAgo).Format(dateLayout)}
+		if _, err := s.TransferPlayer(admin, player.ID, req, AuditContext{}); !errors.Is(err, c.err) {
+			t.Errorf("effective %d days ago: got %v, want %v", c.daysAgo, err, c.err)
+		}
+	}
+}
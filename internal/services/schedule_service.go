@@ -0,0 +1,278 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"echo-golang/internal/fixtures"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidStartTime   = errors.New("start_times must be formatted as HH:MM")
+	ErrInvalidTimezone    = errors.New("unknown timezone")
+	ErrScheduleIncomplete = errors.New("not every fixture fits the provided dates and venues")
+	ErrScheduleExists     = errors.New("tournament already has scheduled matches")
+	ErrBlackoutTeam       = errors.New("blackout team is not registered in the tournament")
+)
+
+// defaultStartTime is used when a schedule request gives no start times
+const defaultStartTime = "18:00"
+
+type ScheduleService struct {
+	tournamentRepo *repositories.TournamentRepository
+	matchRepo      *repositories.MatchRepository
+	matchService   *MatchService
+}
+
+func NewScheduleService() *ScheduleService {
+	return &ScheduleService{
+		tournamentRepo: repositories.NewTournamentRepository(),
+		matchRepo:      repositories.NewMatchRepository(),
+		matchService:   NewMatchService(),
+	}
+}
+
+// GenerateScheduleRequest describes a round-robin to generate. Matches are
+// placed in the slots formed by every date, start time and venue.
+type GenerateScheduleRequest struct {
+	Format     string         `json:"format,omitempty" binding:"omitempty,oneof=single double"` // Defaults to single
+	Dates      []string       `json:"dates" binding:"required,min=1,dive,required"`             // YYYY-MM-DD
+	StartTimes []string       `json:"start_times,omitempty" binding:"dive,required"`            // HH:MM, defaults to 18:00
+	Venues     []string       `json:"venues" binding:"required,min=1,dive,required"`
+	Timezone   string         `json:"timezone,omitempty"` // IANA name, defaults to UTC
+	Blackouts  []TeamBlackout `json:"blackouts,omitempty" binding:"dive"`
+	Commit     bool           `json:"commit,omitempty"` // Create the matches instead of previewing them
+}
+
+// TeamBlackout lists the dates a team cannot play
+type TeamBlackout struct {
+	TeamID uuid.UUID `json:"team_id" binding:"required"`
+	Dates  []string  `json:"dates" binding:"required,dive,required"` // YYYY-MM-DD
+}
+
+// SchedulePreview is the generated round-robin. Unscheduled fixtures found
+// no slot, and conflicting ones were placed in a slot they can't be booked
+// in; a schedule can only be committed when there are neither.
+type SchedulePreview struct {
+	Format      string                `json:"format"`
+	Rounds      int                   `json:"rounds"`
+	Fixtures    []fixtures.Assignment `json:"fixtures"`
+	Unscheduled []fixtures.Fixture    `json:"unscheduled"`
+	Conflicts   []ScheduleConflict    `json:"conflicts"`
+	Committed   bool                  `json:"committed"`
+}
+
+// ScheduleConflict is a fixture placed in a slot it can't be booked in
+type ScheduleConflict struct {
+	Fixture fixtures.Assignment  `json:"fixture"`
+	Reason  string               `json:"reason"`          // "dates", "venue" or "team"
+	Match   *models.Match        `json:"match,omitempty"` // Existing match in the way
+	With    *fixtures.Assignment `json:"with,omitempty"`  // Fixture of this schedule in the way
+}
+
+// GenerateRoundRobin builds a round-robin for the teams registered in a
+// tournament and, when requested, creates its matches in one transaction
+func (s *ScheduleService) GenerateRoundRobin(actor *models.User, id uuid.UUID, req GenerateScheduleRequest, actx AuditContext) (*SchedulePreview, error) {
+	tournament, err := s.tournamentRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrTournamentNotFound
+	}
+	if !tournament.CanBeManagedBy(actor) {
+		return nil, ErrForbidden
+	}
+	if tournament.Status == models.TournamentStatusCompleted {
+		return nil, ErrTournamentCompleted
+	}
+
+	entries, err := s.tournamentRepo.ListEntries(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) < 2 {
+		return nil, ErrNotEnoughEntries
+	}
+	teams := make([]uuid.UUID, len(entries))
+	registered := make(map[uuid.UUID]bool, len(entries))
+	for i, entry := range entries {
+		teams[i] = entry.TeamID
+		registered[entry.TeamID] = true
+	}
+
+	format, legs := "single", 1
+	if req.Format == "double" {
+		format, legs = "double", 2
+	}
+	generated, err := fixtures.RoundRobin(teams, legs)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := scheduleSlots(req)
+	if err != nil {
+		return nil, err
+	}
+	blackouts, err := scheduleBlackouts(req, registered)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, unassigned := fixtures.Assign(generated, slots, blackouts)
+	booked, conflicts, err := s.book(tournament, assigned)
+	if err != nil {
+		return nil, err
+	}
+	preview := &SchedulePreview{
+		Format:      format,
+		Rounds:      generated[len(generated)-1].Round,
+		Fixtures:    booked,
+		Unscheduled: unassigned,
+		Conflicts:   conflicts,
+	}
+	if !req.Commit {
+		return preview, nil
+	}
+
+	if len(unassigned) > 0 || len(conflicts) > 0 {
+		return nil, ErrScheduleIncomplete
+	}
+	existing, err := s.matchRepo.CountActiveByTournament(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrScheduleExists
+	}
+
+	matches := make([]*models.Match, len(booked))
+	for i, a := range booked {
+		matches[i] = scheduledMatch(a)
+	}
+	if err := s.matchService.CreateTournamentMatches(actor, tournament, matches, actx); err != nil {
+		return nil, err
+	}
+
+	preview.Committed = true
+	return preview, nil
+}
+
+// book runs the checks CreateTournamentMatches makes on commit over the
+// placed fixtures, so a preview without conflicts can be committed: each
+// fixture must fall within the tournament dates and clear both the existing
+// matches and the fixtures booked before it by the scheduling window
+func (s *ScheduleService) book(tournament *models.Tournament, assigned []fixtures.Assignment) ([]fixtures.Assignment, []ScheduleConflict, error) {
+	window := schedulingWindow()
+	booked := []fixtures.Assignment{}
+	conflicts := []ScheduleConflict{}
+	for _, a := range assigned {
+		match := scheduledMatch(a)
+		if !withinTournament(tournament, match.ScheduledAt) {
+			conflicts = append(conflicts, ScheduleConflict{Fixture: a, Reason: "dates"})
+			continue
+		}
+		if with, reason := clash(booked, a, window); with != nil {
+			conflicts = append(conflicts, ScheduleConflict{Fixture: a, Reason: reason, With: with})
+			continue
+		}
+		var conflict *ScheduleConflictError
+		if err := s.matchService.checkConflicts(s.matchRepo, match); errors.As(err, &conflict) {
+			conflicts = append(conflicts, ScheduleConflict{Fixture: a, Reason: conflict.Reason, Match: &conflict.Match})
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+		booked = append(booked, a)
+	}
+	return booked, conflicts, nil
+}
+
+// clash finds a booked fixture starting within the window of a, at the same
+// venue or with one of its teams
+func clash(booked []fixtures.Assignment, a fixtures.Assignment, window time.Duration) (*fixtures.Assignment, string) {
+	for i := range booked {
+		b := &booked[i]
+		gap := a.Start.Sub(b.Start)
+		if gap <= -window || gap >= window {
+			continue
+		}
+		if strings.EqualFold(a.Venue, b.Venue) {
+			return b, "venue"
+		}
+		if a.Home == b.Home || a.Home == b.Away || a.Away == b.Home || a.Away == b.Away {
+			return b, "team"
+		}
+	}
+	return nil, ""
+}
+
+// scheduledMatch is the match a placed fixture is created as
+func scheduledMatch(a fixtures.Assignment) *models.Match {
+	return &models.Match{
+		HomeTeamID:  a.Home,
+		AwayTeamID:  a.Away,
+		ScheduledAt: a.Start.UTC(),
+		Venue:       a.Venue,
+	}
+}
+
+// scheduleSlots expands the dates, start times and venues of a request
+func scheduleSlots(req GenerateScheduleRequest) ([]fixtures.Slot, error) {
+	loc := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	}
+
+	dates := make([]time.Time, len(req.Dates))
+	for i, value := range req.Dates {
+		date, err := time.ParseInLocation(dateLayout, value, loc)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		dates[i] = date
+	}
+
+	startTimes := req.StartTimes
+	if len(startTimes) == 0 {
+		startTimes = []string{defaultStartTime}
+	}
+	offsets := make([]time.Duration, len(startTimes))
+	for i, value := range startTimes {
+		t, err := time.Parse("15:04", value)
+		if err != nil {
+			return nil, ErrInvalidStartTime
+		}
+		offsets[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+
+	venues := make([]string, len(req.Venues))
+	for i, venue := range req.Venues {
+		venues[i] = strings.TrimSpace(venue)
+	}
+
+	return fixtures.Slots(dates, offsets, venues), nil
+}
+
+// scheduleBlackouts parses the blackout dates of registered teams
+func scheduleBlackouts(req GenerateScheduleRequest, registered map[uuid.UUID]bool) (fixtures.Blackouts, error) {
+	blackouts := fixtures.Blackouts{}
+	for _, blackout := range req.Blackouts {
+		if !registered[blackout.TeamID] {
+			return nil, ErrBlackoutTeam
+		}
+		for _, value := range blackout.Dates {
+			date, err := time.Parse(dateLayout, value)
+			if err != nil {
+				return nil, ErrInvalidDate
+			}
+			blackouts[blackout.TeamID] = append(blackouts[blackout.TeamID], date)
+		}
+	}
+	return blackouts, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"echo-golang/internal/fixtures"
+
+	"github.com/google/uuid"
+)
+
+func TestClash(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	six := time.Date(2026, time.March, 7, 18, 0, 0, 0, time.UTC)
+	booked := []fixtures.Assignment{{
+		Fixture: fixtures.Fixture{Round: 1, Leg: 1, Home: a, Away: b},
+		Slot:    fixtures.Slot{Start: six, Venue: "Main Court"},
+	}}
+	window := 150 * time.Minute
+
+	cases := []struct {
+		name   string
+		home   uuid.UUID
+		away   uuid.UUID
+		start  time.Time
+		venue  string
+		reason string
+	}{
+		{"same venue within the window", c, d, six.Add(2 * time.Hour), "main court", "venue"},
+		{"same venue before, within the window", c, d, six.Add(-2 * time.Hour), "Main Court", "venue"},
+		{"same team within the window", c, b, six.Add(time.Hour), "North Gym", "team"},
+		{"same venue at the end of the window", c, d, six.Add(window), "Main Court", ""},
+		{"same team after the window", a, c, six.Add(3 * time.Hour), "North Gym", ""},
+		{"other teams and venue", c, d, six, "North Gym", ""},
+	}
+	for _, tc := range cases {
+		fixture := fixtures.Assignment{
+			Fixture: fixtures.Fixture{Round: 2, Leg: 1, Home: tc.home, Away: tc.away},
+			Slot:    fixtures.Slot{Start: tc.start, Venue: tc.venue},
+		}
+		with, reason := clash(booked, fixture, window)
+		if reason != tc.reason || (with != nil) != (tc.reason != "") {
+			t.Errorf("%s: got %q with %v, want %q", tc.name, reason, with, tc.reason)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"sort"
+
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+	"echo-golang/internal/shotchart"
+
+	"github.com/google/uuid"
+)
+
+// Heat map grid sizes, in cells per side
+const (
+	DefaultShotGridSize = 10
+	MaxShotGridSize     = 50
+)
+
+type ShotChartService struct {
+	eventRepo      *repositories.MatchEventRepository
+	tournamentRepo *repositories.TournamentRepository
+	playerRepo     *repositories.PlayerRepository
+	teamRepo       *repositories.TeamRepository
+	matchService   *MatchService
+}
+
+func NewShotChartService() *ShotChartService {
+	return &ShotChartService{
+		eventRepo:      repositories.NewMatchEventRepository(),
+		tournamentRepo: repositories.NewTournamentRepository(),
+		playerRepo:     repositories.NewPlayerRepository(),
+		teamRepo:       repositories.NewTeamRepository(),
+		matchService:   NewMatchService(),
+	}
+}
+
+// ShotChartQuery narrows a shot chart to a team or player and sets the size
+// of its heat map grid
+type ShotChartQuery struct {
+	TeamID   *uuid.UUID
+	PlayerID *uuid.UUID
+	GridSize int
+}
+
+// ShotTally is the attempts, makes and shooting percentage of a zone or grid
+// cell
+type ShotTally struct {
+	Attempts   int      `json:"attempts"`
+	Makes      int      `json:"makes"`
+	Percentage *float64 `json:"percentage"`
+}
+
+type ShotZoneStats struct {
+	Zone models.ShotZone `json:"zone"`
+	ShotTally
+}
+
+// ShotCell is a heat map cell. Columns run from the left sideline and rows
+// from the baseline, both from 0.
+type ShotCell struct {
+	Column int `json:"column"`
+	Row    int `json:"row"`
+	ShotTally
+}
+
+// ShotChart is the shots of a team or player by zone, with the located ones
+// on the heat map grid. Every zone is listed; only cells with shots are.
+type ShotChart struct {
+	Zones     []ShotZoneStats `json:"zones"`
+	Unlocated ShotTally       `json:"unlocated"` // Field goals recorded without a location or zone
+	Cells     []ShotCell      `json:"cells"`
+}
+
+type TeamShotChart struct {
+	TeamID uuid.UUID `json:"team_id"`
+	Name   string    `json:"name"`
+	ShotChart
+}
+
+type PlayerShotChart struct {
+	PlayerID     uuid.UUID `json:"player_id"`
+	TeamID       uuid.UUID `json:"team_id"` // Team the shots were taken for
+	FullName     string    `json:"full_name"`
+	JerseyNumber int       `json:"jersey_number"`
+	ShotChart
+}
+
+// ShotChartReport is the shot charts of the teams and players of a match or
+// tournament
+type ShotChartReport struct {
+	MatchID      *uuid.UUID        `json:"match_id,omitempty"`
+	TournamentID *uuid.UUID        `json:"tournament_id,omitempty"`
+	GridSize     int               `json:"grid_size"`
+	Teams        []TeamShotChart   `json:"teams"`
+	Players      []PlayerShotChart `json:"players"`
+}
+
+// GetMatchShotChart charts the shots of a match so far
+func (s *ShotChartService) GetMatchShotChart(viewer *models.User, matchID uuid.UUID, q ShotChartQuery) (*ShotChartReport, error) {
+	match, err := s.matchService.viewableMatch(viewer, matchID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.ListByMatch(match.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.report(events, q)
+	if err != nil {
+		return nil, err
+	}
+	report.MatchID = &match.ID
+	return report, nil
+}
+
+// GetTournamentShotChart charts the shots of a tournament's completed matches
+func (s *ShotChartService) GetTournamentShotChart(tournamentID uuid.UUID, q ShotChartQuery) (*ShotChartReport, error) {
+	tournament, err := s.tournamentRepo.GetByID(tournamentID)
+	if err != nil {
+		return nil, ErrTournamentNotFound
+	}
+	events, err := s.eventRepo.ListShotsByTournament(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.report(events, q)
+	if err != nil {
+		return nil, err
+	}
+	report.TournamentID = &tournament.ID
+	return report, nil
+}
+
+// report builds the charts of the events the query selects, teams by name
+// and players by team and jersey number
+func (s *ShotChartService) report(events []models.MatchEvent, q ShotChartQuery) (*ShotChartReport, error) {
+	if q.GridSize <= 0 {
+		q.GridSize = DefaultShotGridSize
+	}
+	var selected []models.MatchEvent
+	playerTeams := map[uuid.UUID]uuid.UUID{}
+	for _, event := range events {
+		if (q.TeamID != nil && (event.TeamID == nil || *event.TeamID != *q.TeamID)) ||
+			(q.PlayerID != nil && (event.PlayerID == nil || *event.PlayerID != *q.PlayerID)) {
+			continue
+		}
+		selected = append(selected, event)
+		if event.PlayerID != nil && event.TeamID != nil {
+			if _, ok := playerTeams[*event.PlayerID]; !ok {
+				playerTeams[*event.PlayerID] = *event.TeamID
+			}
+		}
+	}
+	charts := shotchart.Build(selected, q.GridSize)
+
+	report := &ShotChartReport{GridSize: q.GridSize, Teams: []TeamShotChart{}, Players: []PlayerShotChart{}}
+	teamIDs := make([]uuid.UUID, 0, len(charts.Teams))
+	for id := range charts.Teams {
+		teamIDs = append(teamIDs, id)
+	}
+	teams, err := s.teamRepo.ListByIDs(teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := map[uuid.UUID]string{}
+	for _, team := range teams {
+		names[team.ID] = team.Name
+	}
+	for id, chart := range charts.Teams {
+		report.Teams = append(report.Teams, TeamShotChart{TeamID: id, Name: names[id], ShotChart: shotChart(chart)})
+	}
+	sort.Slice(report.Teams, func(i, j int) bool {
+		if report.Teams[i].Name != report.Teams[j].Name {
+			return report.Teams[i].Name < report.Teams[j].Name
+		}
+		return report.Teams[i].TeamID.String() < report.Teams[j].TeamID.String()
+	})
+
+	playerIDs := make([]uuid.UUID, 0, len(charts.Players))
+	for id := range charts.Players {
+		playerIDs = append(playerIDs, id)
+	}
+	players, err := s.playerRepo.ListByIDs(playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[uuid.UUID]*models.Player{}
+	for i := range players {
+		byID[players[i].ID] = &players[i]
+	}
+	for id, chart := range charts.Players {
+		line := PlayerShotChart{PlayerID: id, TeamID: playerTeams[id], ShotChart: shotChart(chart)}
+		if p, ok := byID[id]; ok {
+			line.FullName, line.JerseyNumber = p.FullName, p.JerseyNumber
+		}
+		report.Players = append(report.Players, line)
+	}
+	teamOrder := map[uuid.UUID]int{}
+	for i, team := range report.Teams {
+		teamOrder[team.TeamID] = i
+	}
+	sort.Slice(report.Players, func(i, j int) bool {
+		a, b := report.Players[i], report.Players[j]
+		if teamOrder[a.TeamID] != teamOrder[b.TeamID] {
+			return teamOrder[a.TeamID] < teamOrder[b.TeamID]
+		}
+		if a.JerseyNumber != b.JerseyNumber {
+			return a.JerseyNumber < b.JerseyNumber
+		}
+		return a.PlayerID.String() < b.PlayerID.String()
+	})
+	return report, nil
+}
+
+func shotChart(chart *shotchart.Chart) ShotChart {
+	out := ShotChart{Unlocated: shotTally(chart.Unlocated), Cells: []ShotCell{}}
+	for _, zone := range models.ShotZones {
+		out.Zones = append(out.Zones, ShotZoneStats{Zone: zone, ShotTally: shotTally(*chart.Zones[zone])})
+	}
+	for cell, tally := range chart.Cells {
+		out.Cells = append(out.Cells, ShotCell{Column: cell[0], Row: cell[1], ShotTally: shotTally(*tally)})
+	}
+	sort.Slice(out.Cells, func(i, j int) bool {
+		if out.Cells[i].Row != out.Cells[j].Row {
+			return out.Cells[i].Row < out.Cells[j].Row
+		}
+		return out.Cells[i].Column < out.Cells[j].Column
+	})
+	return out
+}
+
+func shotTally(t shotchart.Tally) ShotTally {
+	return ShotTally{Attempts: t.Attempts, Makes: t.Makes, Percentage: percentage(t.Makes, t.Attempts)}
+}
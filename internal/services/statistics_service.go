@@ -0,0 +1,572 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"echo-golang/internal/analytics"
+	"echo-golang/internal/boxscore"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidStatisticsQuery = errors.New("scope match needs match_id, tournament needs tournament_id and season needs season; splits don't apply to a single match")
+)
+
+// Statistics scopes
+const (
+	StatScopeMatch = "match"
+)
+
+type StatisticsService struct {
+	statsRepo    *repositories.StatisticsRepository
+	playerRepo   *repositories.PlayerRepository
+	rosterRepo   *repositories.RosterRepository
+	teamRepo     *repositories.TeamRepository
+	matchService *MatchService
+}
+
+func NewStatisticsService() *StatisticsService {
+	return &StatisticsService{
+		statsRepo:    repositories.NewStatisticsRepository(),
+		playerRepo:   repositories.NewPlayerRepository(),
+		rosterRepo:   repositories.NewRosterRepository(),
+		teamRepo:     repositories.NewTeamRepository(),
+		matchService: NewMatchService(),
+	}
+}
+
+// StatisticsQuery selects the games a player's or team's statistics cover:
+// one match, a tournament, a season or a career, optionally split by
+// location, opponent or the latest games
+type StatisticsQuery struct {
+	Scope        string // match, tournament, season or career
+	MatchID      *uuid.UUID
+	TournamentID *uuid.UUID
+	Season       int
+	Location     string // home or away
+	OpponentID   *uuid.UUID
+	LastN        int
+}
+
+func (q StatisticsQuery) hasSplits() bool {
+	return q.Location != "" || q.OpponentID != nil || q.LastN > 0
+}
+
+// Validate checks a query names the match, tournament or season its
+// scope needs, defaulting to the career
+func (q *StatisticsQuery) Validate() error {
+	if q.Scope == "" {
+		q.Scope = models.StatScopeCareer
+	}
+	switch {
+	case q.Scope == StatScopeMatch && (q.MatchID == nil || q.hasSplits()),
+		q.Scope == models.StatScopeTournament && q.TournamentID == nil,
+		q.Scope == models.StatScopeSeason && q.Season == 0:
+		return ErrInvalidStatisticsQuery
+	}
+	return nil
+}
+
+// StatAverages are per-game averages, rounded to one decimal
+type StatAverages struct {
+	Points                 float64  `json:"points"`
+	Rebounds               float64  `json:"rebounds"`
+	OffensiveRebounds      float64  `json:"offensive_rebounds"`
+	DefensiveRebounds      float64  `json:"defensive_rebounds"`
+	Assists                float64  `json:"assists"`
+	Steals                 float64  `json:"steals"`
+	Blocks                 float64  `json:"blocks"`
+	Turnovers              float64  `json:"turnovers"`
+	Fouls                  float64  `json:"fouls"`
+	Minutes                float64  `json:"minutes"`
+	PlusMinus              float64  `json:"plus_minus"`
+	FieldGoalsMade         float64  `json:"field_goals_made"`
+	FieldGoalsAttempted    float64  `json:"field_goals_attempted"`
+	ThreePointersMade      float64  `json:"three_pointers_made"`
+	ThreePointersAttempted float64  `json:"three_pointers_attempted"`
+	FreeThrowsMade         float64  `json:"free_throws_made"`
+	FreeThrowsAttempted    float64  `json:"free_throws_attempted"`
+	OpponentPoints         *float64 `json:"opponent_points,omitempty"` // Teams only
+}
+
+// StatisticsSummary is the totals, per-game averages, shooting percentages
+// and advanced metrics of a player or team over the games a query selects
+type StatisticsSummary struct {
+	SubjectType    string             `json:"subject_type"`
+	SubjectID      uuid.UUID          `json:"subject_id"`
+	Scope          string             `json:"scope"`
+	MatchID        *uuid.UUID         `json:"match_id,omitempty"`
+	TournamentID   *uuid.UUID         `json:"tournament_id,omitempty"`
+	Season         int                `json:"season,omitempty"`
+	Location       string             `json:"location,omitempty"`
+	OpponentID     *uuid.UUID         `json:"opponent_id,omitempty"`
+	LastN          int                `json:"last_n,omitempty"`
+	Games          int                `json:"games"`
+	Wins           int                `json:"wins"`
+	Losses         int                `json:"losses"`
+	Totals         BoxScoreLine       `json:"totals"`
+	OpponentPoints *int               `json:"opponent_points,omitempty"` // Teams only
+	PerGame        *StatAverages      `json:"per_game,omitempty"`        // Left out without games
+	Advanced       *analytics.Metrics `json:"advanced,omitempty"`        // Left out without games
+}
+
+// BoxScoreLine is a box score line with its minutes and shooting percentages.
+// Percentages run from 0 to 100 and are left out without attempts.
+type BoxScoreLine struct {
+	models.StatLine
+	Minutes       string   `json:"minutes"` // M:SS
+	FieldGoalPct  *float64 `json:"field_goal_pct,omitempty"`
+	ThreePointPct *float64 `json:"three_point_pct,omitempty"`
+	FreeThrowPct  *float64 `json:"free_throw_pct,omitempty"`
+}
+
+type PlayerBoxScore struct {
+	PlayerID     uuid.UUID             `json:"player_id"`
+	JerseyNumber int                   `json:"jersey_number"`
+	FullName     string                `json:"full_name"`
+	Position     models.PlayerPosition `json:"position"`
+	BoxScoreLine
+}
+
+type TeamBoxScore struct {
+	TeamID  uuid.UUID        `json:"team_id"`
+	Name    string           `json:"name"`
+	Score   int              `json:"score"`
+	Players []PlayerBoxScore `json:"players"`
+	Totals  BoxScoreLine     `json:"totals"`
+}
+
+// BoxScore is the box score of both teams of a match as of an event sequence
+type BoxScore struct {
+	MatchID  uuid.UUID          `json:"match_id"`
+	Status   models.MatchStatus `json:"status"`
+	Sequence int                `json:"sequence"`
+	Home     TeamBoxScore       `json:"home"`
+	Away     TeamBoxScore       `json:"away"`
+}
+
+// GetBoxScore gets the box score of a match, derived from its event log
+func (s *StatisticsService) GetBoxScore(viewer *models.User, matchID uuid.UUID) (*BoxScore, error) {
+	match, err := s.matchService.viewableMatch(viewer, matchID)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.statsRepo.ListPlayersByMatch(match.ID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.statsRepo.ListTeamsByMatch(match.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Players are listed with the number they wore in the match, as in its
+	// play-by-play
+	playerIDs := make([]uuid.UUID, len(players))
+	for i, stats := range players {
+		playerIDs[i] = stats.PlayerID
+	}
+	numbers, err := jerseyNumbersAsOf(s.rosterRepo, playerIDs, dateOf(match.ScheduledAt))
+	if err != nil {
+		return nil, err
+	}
+
+	box := &BoxScore{
+		MatchID:  match.ID,
+		Status:   match.Status,
+		Sequence: match.EventSequence,
+		Home:     teamBoxScore(match.HomeTeamID, match.HomeTeam, match.HomeScore),
+		Away:     teamBoxScore(match.AwayTeamID, match.AwayTeam, match.AwayScore),
+	}
+	sides := map[uuid.UUID]*TeamBoxScore{match.HomeTeamID: &box.Home, match.AwayTeamID: &box.Away}
+	for _, stats := range teams {
+		if side, ok := sides[stats.TeamID]; ok {
+			side.Totals = boxScoreLine(stats.StatLine)
+		}
+	}
+	for _, stats := range players {
+		side, ok := sides[stats.TeamID]
+		if !ok {
+			continue
+		}
+		line := PlayerBoxScore{PlayerID: stats.PlayerID, BoxScoreLine: boxScoreLine(stats.StatLine)}
+		if stats.Player != nil {
+			line.JerseyNumber = stats.Player.JerseyNumber
+			line.FullName = stats.Player.FullName
+			line.Position = stats.Player.Position
+		}
+		if number, ok := numbers[stats.PlayerID]; ok {
+			line.JerseyNumber = number
+		}
+		side.Players = append(side.Players, line)
+	}
+	for _, side := range sides {
+		sort.SliceStable(side.Players, func(i, j int) bool {
+			return side.Players[i].JerseyNumber < side.Players[j].JerseyNumber
+		})
+	}
+	return box, nil
+}
+
+func teamBoxScore(teamID uuid.UUID, team *models.Team, score int) TeamBoxScore {
+	box := TeamBoxScore{
+		TeamID:  teamID,
+		Score:   score,
+		Players: []PlayerBoxScore{},
+		Totals:  boxScoreLine(models.StatLine{}),
+	}
+	if team != nil {
+		box.Name = team.Name
+	}
+	return box
+}
+
+func boxScoreLine(stats models.StatLine) BoxScoreLine {
+	return BoxScoreLine{
+		StatLine:      stats,
+		Minutes:       fmt.Sprintf("%d:%02d", stats.SecondsPlayed/60, stats.SecondsPlayed%60),
+		FieldGoalPct:  percentage(stats.FieldGoalsMade, stats.FieldGoalsAttempted),
+		ThreePointPct: percentage(stats.ThreePointersMade, stats.ThreePointersAttempted),
+		FreeThrowPct:  percentage(stats.FreeThrowsMade, stats.FreeThrowsAttempted),
+	}
+}
+
+// percentage is made out of attempted as a percentage with one decimal, or
+// nil without attempts
+func percentage(made, attempted int) *float64 {
+	if attempted == 0 {
+		return nil
+	}
+	pct := math.Round(float64(made)/float64(attempted)*1000) / 10
+	return &pct
+}
+
+// GetPlayerStatistics gets a player's statistics over the games a query
+// selects
+func (s *StatisticsService) GetPlayerStatistics(viewer *models.User, playerID uuid.UUID, q StatisticsQuery) (*StatisticsSummary, error) {
+	if _, err := s.playerRepo.GetByID(playerID); err != nil {
+		return nil, ErrPlayerNotFound
+	}
+	if q.Scope != StatScopeMatch {
+		return s.summarize(models.StatSubjectPlayer, playerID, q)
+	}
+
+	match, err := s.matchService.viewableMatch(viewer, *q.MatchID)
+	if err != nil {
+		return nil, err
+	}
+	agg := &models.StatAggregate{SubjectType: models.StatSubjectPlayer, SubjectID: playerID}
+	if stats, err := s.statsRepo.GetPlayerMatch(playerID, match.ID); err == nil {
+		agg.Games, agg.Wins, agg.StatLine = 1, boolToInt(stats.Won), stats.StatLine
+		if team, err := s.statsRepo.GetTeamMatch(stats.TeamID, match.ID); err == nil {
+			agg.TeamFieldGoalsAttempted = team.FieldGoalsAttempted
+			agg.TeamFreeThrowsAttempted = team.FreeThrowsAttempted
+			agg.TeamTurnovers = team.Turnovers
+			agg.TeamSecondsPlayed = team.SecondsPlayed
+		}
+	}
+	return statisticsSummary(agg, q), nil
+}
+
+// GetTeamStatistics gets a team's statistics over the games a query selects
+func (s *StatisticsService) GetTeamStatistics(viewer *models.User, teamID uuid.UUID, q StatisticsQuery) (*StatisticsSummary, error) {
+	if _, err := s.teamRepo.GetByID(teamID); err != nil {
+		return nil, ErrTeamNotFound
+	}
+	if q.Scope != StatScopeMatch {
+		return s.summarize(models.StatSubjectTeam, teamID, q)
+	}
+
+	match, err := s.matchService.viewableMatch(viewer, *q.MatchID)
+	if err != nil {
+		return nil, err
+	}
+	agg := &models.StatAggregate{SubjectType: models.StatSubjectTeam, SubjectID: teamID}
+	if stats, err := s.statsRepo.GetTeamMatch(teamID, match.ID); err == nil {
+		agg.Games, agg.Wins, agg.StatLine, agg.OpponentPoints = 1, boolToInt(stats.Won), stats.StatLine, stats.OpponentPoints
+	}
+	return statisticsSummary(agg, q), nil
+}
+
+// summarize reads the stored aggregate of a tournament, season or career, and
+// totals the final statistics rows when the query splits them further
+func (s *StatisticsService) summarize(subjectType string, subjectID uuid.UUID, q StatisticsQuery) (*StatisticsSummary, error) {
+	if !q.hasSplits() {
+		agg, err := s.statsRepo.GetAggregate(subjectType, subjectID, q.Scope, aggregateKey(q))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			agg = &models.StatAggregate{SubjectType: subjectType, SubjectID: subjectID}
+		} else if err != nil {
+			return nil, err
+		}
+		return statisticsSummary(agg, q), nil
+	}
+
+	filters := scopeFilters(q)
+	if q.Location != "" {
+		filters["home"] = q.Location == "home"
+	}
+	if q.OpponentID != nil {
+		filters["opponent_team_id"] = *q.OpponentID
+	}
+	agg, err := s.statsRepo.Sum(subjectType, subjectID, filters, q.LastN)
+	if err != nil {
+		return nil, err
+	}
+	return statisticsSummary(agg, q), nil
+}
+
+func statisticsSummary(agg *models.StatAggregate, q StatisticsQuery) *StatisticsSummary {
+	summary := &StatisticsSummary{
+		SubjectType: agg.SubjectType,
+		SubjectID:   agg.SubjectID,
+		Scope:       q.Scope,
+		MatchID:     q.MatchID,
+		Location:    q.Location,
+		OpponentID:  q.OpponentID,
+		LastN:       q.LastN,
+		Games:       agg.Games,
+		Wins:        agg.Wins,
+		Losses:      agg.Games - agg.Wins,
+		Totals:      boxScoreLine(agg.StatLine),
+	}
+	switch q.Scope {
+	case models.StatScopeTournament:
+		summary.TournamentID = q.TournamentID
+	case models.StatScopeSeason:
+		summary.Season = q.Season
+	}
+	if agg.SubjectType == models.StatSubjectTeam {
+		summary.OpponentPoints = &agg.OpponentPoints
+	}
+	if agg.Games == 0 {
+		return summary
+	}
+
+	games := float64(agg.Games)
+	avg := func(total int) float64 { return math.Round(float64(total)/games*10) / 10 }
+	l := agg.StatLine
+	summary.PerGame = &StatAverages{
+		Points:                 avg(l.Points),
+		Rebounds:               avg(l.Rebounds),
+		OffensiveRebounds:      avg(l.OffensiveRebounds),
+		DefensiveRebounds:      avg(l.DefensiveRebounds),
+		Assists:                avg(l.Assists),
+		Steals:                 avg(l.Steals),
+		Blocks:                 avg(l.Blocks),
+		Turnovers:              avg(l.Turnovers),
+		Fouls:                  avg(l.Fouls),
+		Minutes:                math.Round(float64(l.SecondsPlayed)/60/games*10) / 10,
+		PlusMinus:              avg(l.PlusMinus),
+		FieldGoalsMade:         avg(l.FieldGoalsMade),
+		FieldGoalsAttempted:    avg(l.FieldGoalsAttempted),
+		ThreePointersMade:      avg(l.ThreePointersMade),
+		ThreePointersAttempted: avg(l.ThreePointersAttempted),
+		FreeThrowsMade:         avg(l.FreeThrowsMade),
+		FreeThrowsAttempted:    avg(l.FreeThrowsAttempted),
+	}
+	if agg.SubjectType == models.StatSubjectTeam {
+		opponent := avg(agg.OpponentPoints)
+		summary.PerGame.OpponentPoints = &opponent
+		advanced := analytics.Team(l, agg.OpponentPoints, agg.Games)
+		summary.Advanced = &advanced
+	} else {
+		advanced := analytics.Player(l, agg.TeamLine(), agg.Games)
+		summary.Advanced = &advanced
+	}
+	return summary
+}
+
+// scopeFilters selects the statistics rows of a query's scope
+func scopeFilters(q StatisticsQuery) map[string]interface{} {
+	switch q.Scope {
+	case models.StatScopeTournament:
+		return map[string]interface{}{"tournament_id": *q.TournamentID}
+	case models.StatScopeSeason:
+		return map[string]interface{}{"season": q.Season}
+	}
+	return map[string]interface{}{}
+}
+
+// aggregateKey is the stored aggregate key of a query's scope
+func aggregateKey(q StatisticsQuery) string {
+	switch q.Scope {
+	case models.StatScopeTournament:
+		return q.TournamentID.String()
+	case models.StatScopeSeason:
+		return strconv.Itoa(q.Season)
+	}
+	return ""
+}
+
+// recordStatistics rebuilds the stored box score of a match from its event
+// log, so an undone event's stats disappear with it
+func recordStatistics(eventRepo *repositories.MatchEventRepository, statsRepo *repositories.StatisticsRepository, match *models.Match) error {
+	events, err := eventRepo.ListByMatch(match.ID, 0)
+	if err != nil {
+		return err
+	}
+	box := boxscore.Compute(match, events)
+	placeLines(match, &box)
+	if err := statsRepo.ReplaceMatch(match.ID, box.Players, box.Teams); err != nil {
+		return err
+	}
+	return saveBoxScoreState(statsRepo, match.ID, box.State)
+}
+
+// updateStatistics carries the stored box score of a match on with its newest
+// event, writing only the lines the event changed. Clock and period events
+// leave the box score alone, so players on court are counted up to the latest
+// event of another type. A void rebuilds the box score from the log, and so
+// does any event when the stored state is missing or unreadable.
+func updateStatistics(eventRepo *repositories.MatchEventRepository, statsRepo *repositories.StatisticsRepository, match *models.Match, event *models.MatchEvent) error {
+	switch event.Type {
+	case models.MatchEventClockStart, models.MatchEventClockStop, models.MatchEventClockSet,
+		models.MatchEventPeriodStart, models.MatchEventQuarterEnd:
+		return nil
+	case models.MatchEventVoid:
+		return recordStatistics(eventRepo, statsRepo, match)
+	}
+
+	state, err := boxScoreState(statsRepo, match.ID)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return recordStatistics(eventRepo, statsRepo, match)
+	}
+	players, err := statsRepo.ListPlayerLinesByMatch(match.ID)
+	if err != nil {
+		return err
+	}
+	teams, err := statsRepo.ListTeamsByMatch(match.ID)
+	if err != nil {
+		return err
+	}
+
+	box := boxscore.Update(match, players, teams, *state, event)
+	placeLines(match, &box)
+	if err := statsRepo.SaveLines(box.Players, box.Teams); err != nil {
+		return err
+	}
+	return saveBoxScoreState(statsRepo, match.ID, box.State)
+}
+
+// boxScoreState reads the stored box score state of a match, which is nil
+// when it is missing or unreadable and must be rebuilt from the event log
+func boxScoreState(statsRepo *repositories.StatisticsRepository, matchID uuid.UUID) (*boxscore.State, error) {
+	stored, err := statsRepo.GetBoxScoreState(matchID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var state boxscore.State
+	if err := json.Unmarshal([]byte(stored.State), &state); err != nil || state.Court == nil || state.Score == nil {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// placeLines places the lines of a match's box score in the match, and sets
+// the team points to the official score, which a manager may have corrected
+func placeLines(match *models.Match, box *boxscore.Result) {
+	for i := range box.Players {
+		box.Players[i].GameContext = gameContext(match, box.Players[i].TeamID)
+	}
+	for i := range box.Teams {
+		team := &box.Teams[i]
+		team.GameContext = gameContext(match, team.TeamID)
+		team.Points, team.OpponentPoints = match.HomeScore, match.AwayScore
+		if !team.Home {
+			team.Points, team.OpponentPoints = match.AwayScore, match.HomeScore
+		}
+		team.PlusMinus = team.Points - team.OpponentPoints
+	}
+}
+
+// gameContext is the context of the statistics rows of a team in a match
+func gameContext(match *models.Match, teamID uuid.UUID) models.GameContext {
+	opponent := match.HomeTeamID
+	if teamID == match.HomeTeamID {
+		opponent = match.AwayTeamID
+	}
+	return models.GameContext{
+		TournamentID:   match.TournamentID,
+		OpponentTeamID: opponent,
+		Home:           teamID == match.HomeTeamID,
+		Season:         match.ScheduledAt.UTC().Year(),
+		PlayedAt:       match.ScheduledAt,
+		Final:          match.IsCompleted(),
+		Won:            match.IsCompleted() && match.WinnerTeamID != nil && *match.WinnerTeamID == teamID,
+	}
+}
+
+func saveBoxScoreState(statsRepo *repositories.StatisticsRepository, matchID uuid.UUID, state boxscore.State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return statsRepo.SaveBoxScoreState(&models.BoxScoreState{MatchID: matchID, State: string(data)})
+}
+
+// finalizeStatistics marks the statistics of a completed match final and
+// rebuilds the tournament, season and career aggregates of its players and
+// teams from the final rows. It does nothing for other matches.
+func finalizeStatistics(eventRepo *repositories.MatchEventRepository, statsRepo *repositories.StatisticsRepository, match *models.Match) error {
+	if !match.IsCompleted() {
+		return nil
+	}
+	if err := recordStatistics(eventRepo, statsRepo, match); err != nil {
+		return err
+	}
+
+	players, err := statsRepo.ListPlayersByMatch(match.ID)
+	if err != nil {
+		return err
+	}
+	type subject struct {
+		kind string
+		id   uuid.UUID
+	}
+	subjects := []subject{{models.StatSubjectTeam, match.HomeTeamID}, {models.StatSubjectTeam, match.AwayTeamID}}
+	for _, p := range players {
+		subjects = append(subjects, subject{models.StatSubjectPlayer, p.PlayerID})
+	}
+
+	season := match.ScheduledAt.UTC().Year()
+	scopes := []StatisticsQuery{
+		{Scope: models.StatScopeCareer},
+		{Scope: models.StatScopeSeason, Season: season},
+	}
+	if match.TournamentID != nil {
+		scopes = append(scopes, StatisticsQuery{Scope: models.StatScopeTournament, TournamentID: match.TournamentID})
+	}
+	for _, sub := range subjects {
+		for _, q := range scopes {
+			agg, err := statsRepo.Sum(sub.kind, sub.id, scopeFilters(q), 0)
+			if err != nil {
+				return err
+			}
+			agg.Scope, agg.ScopeKey = q.Scope, aggregateKey(q)
+			if err := statsRepo.SaveAggregate(agg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
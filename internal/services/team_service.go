@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrOrganizationRequired = errors.New("organization_id is required")
+
+	// ErrForbidden is returned when the actor may not act on the target resource
+	ErrForbidden = errors.New("access denied")
+)
+
+type TeamService struct {
+	teamRepo     *repositories.TeamRepository
+	orgRepo      *repositories.OrganizationRepository
+	auditService *AuditService
+}
+
+func NewTeamService() *TeamService {
+	return &TeamService{
+		teamRepo:     repositories.NewTeamRepository(),
+		orgRepo:      repositories.NewOrganizationRepository(),
+		auditService: NewAuditService(),
+	}
+}
+
+type CreateTeamRequest struct {
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"` // Defaults to the org admin's organization
+	Name           string     `json:"name" binding:"required"`
+	LogoURL        string     `json:"logo_url,omitempty" binding:"omitempty,url"`
+	CoachName      string     `json:"coach_name,omitempty"`
+	CoachPhone     string     `json:"coach_phone,omitempty"`
+	CoachEmail     string     `json:"coach_email,omitempty" binding:"omitempty,email"`
+	Description    string     `json:"description,omitempty"`
+	Status         string     `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+}
+
+type UpdateTeamRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1"`
+	LogoURL     *string `json:"logo_url,omitempty" binding:"omitempty,url"`
+	CoachName   *string `json:"coach_name,omitempty"`
+	CoachPhone  *string `json:"coach_phone,omitempty"`
+	CoachEmail  *string `json:"coach_email,omitempty" binding:"omitempty,email"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+}
+
+// GetTeam gets a team by ID
+func (s *TeamService) GetTeam(id uuid.UUID) (*models.Team, error) {
+	team, err := s.teamRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrTeamNotFound
+	}
+	return team, nil
+}
+
+// ListTeams gets teams matching the filters with pagination
+func (s *TeamService) ListTeams(offset, limit int, filters map[string]interface{}) ([]models.Team, int64, error) {
+	return s.teamRepo.List(offset, limit, filters)
+}
+
+// CreateTeam creates a team in an organization the actor manages
+func (s *TeamService) CreateTeam(actor *models.User, req CreateTeamRequest, actx AuditContext) (*models.Team, error) {
+	orgID := req.OrganizationID
+	if orgID == nil {
+		orgID = actor.OrganizationID
+	}
+	if orgID == nil {
+		return nil, ErrOrganizationRequired
+	}
+	if !actor.CanManageOrganization(*orgID) {
+		return nil, ErrForbidden
+	}
+	if _, err := s.orgRepo.GetByID(*orgID); err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	status := models.TeamStatusActive
+	if req.Status != "" {
+		status = models.TeamStatus(req.Status)
+	}
+
+	team := &models.Team{
+		OrganizationID: *orgID,
+		Name:           req.Name,
+		LogoURL:        req.LogoURL,
+		CoachName:      req.CoachName,
+		CoachPhone:     req.CoachPhone,
+		CoachEmail:     req.CoachEmail,
+		Description:    req.Description,
+		Status:         status,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.teamRepo.WithTx(tx).Create(team); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTeamCreate,
+			TargetType: models.AuditTargetTeam,
+			TargetID:   &team.ID,
+			After:      team,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to create team")
+	}
+
+	return s.GetTeam(team.ID)
+}
+
+// UpdateTeam applies the non-nil fields of req to a team the actor manages
+func (s *TeamService) UpdateTeam(actor *models.User, id uuid.UUID, req UpdateTeamRequest, actx AuditContext) (*models.Team, error) {
+	team, err := s.GetTeam(id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManageOrganization(team.OrganizationID) {
+		return nil, ErrForbidden
+	}
+	before := *team
+	team.Organization = nil
+
+	if req.Name != nil {
+		team.Name = *req.Name
+	}
+	if req.LogoURL != nil {
+		team.LogoURL = *req.LogoURL
+	}
+	if req.CoachName != nil {
+		team.CoachName = *req.CoachName
+	}
+	if req.CoachPhone != nil {
+		team.CoachPhone = *req.CoachPhone
+	}
+	if req.CoachEmail != nil {
+		team.CoachEmail = *req.CoachEmail
+	}
+	if req.Description != nil {
+		team.Description = *req.Description
+	}
+	if req.Status != nil {
+		team.Status = models.TeamStatus(*req.Status)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.teamRepo.WithTx(tx).Update(team); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTeamUpdate,
+			TargetType: models.AuditTargetTeam,
+			TargetID:   &team.ID,
+			Before:     &before,
+			After:      team,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to update team")
+	}
+
+	return s.GetTeam(team.ID)
+}
+
+// DeleteTeam soft deletes a team the actor manages
+func (s *TeamService) DeleteTeam(actor *models.User, id uuid.UUID, actx AuditContext) error {
+	team, err := s.GetTeam(id)
+	if err != nil {
+		return err
+	}
+	if !actor.CanManageOrganization(team.OrganizationID) {
+		return ErrForbidden
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.teamRepo.WithTx(tx).Delete(team.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTeamDelete,
+			TargetType: models.AuditTargetTeam,
+			TargetID:   &team.ID,
+			Before:     team,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to delete team")
+	}
+	return nil
+}
@@ -0,0 +1,560 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+	"echo-golang/internal/standings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTournamentNotFound      = errors.New("tournament not found")
+	ErrInvalidTournamentDates  = errors.New("end_date must not be before start_date")
+	ErrInvalidStatusTransition = errors.New("invalid tournament status transition")
+	ErrNotEnoughEntries        = errors.New("at least two teams must be registered to start a tournament")
+	ErrTournamentCompleted     = errors.New("completed tournaments cannot be modified")
+	ErrRegistrationClosed      = errors.New("teams can only be registered or withdrawn while the tournament is upcoming")
+	ErrTeamAlreadyRegistered   = errors.New("team is already registered in this tournament")
+	ErrTeamNotRegistered       = errors.New("team is not registered in this tournament")
+	ErrTeamInactive            = errors.New("team is not active")
+	ErrInvalidTiebreakers      = errors.New("tiebreakers must be distinct values of head_to_head, head_to_head_point_diff, point_diff, points_for and manual")
+)
+
+type TournamentService struct {
+	tournamentRepo *repositories.TournamentRepository
+	matchRepo      *repositories.MatchRepository
+	teamRepo       *repositories.TeamRepository
+	orgRepo        *repositories.OrganizationRepository
+	standingRepo   *repositories.StandingRepository
+	auditService   *AuditService
+}
+
+func NewTournamentService() *TournamentService {
+	return &TournamentService{
+		tournamentRepo: repositories.NewTournamentRepository(),
+		matchRepo:      repositories.NewMatchRepository(),
+		teamRepo:       repositories.NewTeamRepository(),
+		orgRepo:        repositories.NewOrganizationRepository(),
+		standingRepo:   repositories.NewStandingRepository(),
+		auditService:   NewAuditService(),
+	}
+}
+
+type CreateTournamentRequest struct {
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"` // Defaults to the org admin's organization
+	Name           string     `json:"name" binding:"required"`
+	Description    string     `json:"description,omitempty"`
+	StartDate      string     `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate        string     `json:"end_date" binding:"required"`   // YYYY-MM-DD
+	TournamentRules
+}
+
+type UpdateTournamentRequest struct {
+	Name        *string              `json:"name,omitempty" binding:"omitempty,min=1"`
+	Description *string              `json:"description,omitempty"`
+	StartDate   *string              `json:"start_date,omitempty"` // YYYY-MM-DD
+	EndDate     *string              `json:"end_date,omitempty"`   // YYYY-MM-DD
+	Status      *string              `json:"status,omitempty" binding:"omitempty,oneof=upcoming ongoing completed"`
+	Tiebreakers *[]models.Tiebreaker `json:"tiebreakers,omitempty"` // Empty for the default chain
+	TournamentRules
+}
+
+// TournamentRules sets the game clock of a tournament's matches: quarters or
+// halves, their length and the length of overtime periods. Omitted rules fall
+// back to the server defaults. Matches keep the rules they started with.
+type TournamentRules struct {
+	PeriodCount     *int `json:"period_count,omitempty" binding:"omitempty,oneof=2 4"`
+	PeriodMinutes   *int `json:"period_minutes,omitempty" binding:"omitempty,min=1,max=30"`
+	OvertimeMinutes *int `json:"overtime_minutes,omitempty" binding:"omitempty,min=1,max=10"`
+}
+
+// apply copies the rules that are set onto a tournament
+func (r TournamentRules) apply(tournament *models.Tournament) {
+	if r.PeriodCount != nil {
+		tournament.PeriodCount = r.PeriodCount
+	}
+	if r.PeriodMinutes != nil {
+		tournament.PeriodMinutes = r.PeriodMinutes
+	}
+	if r.OvertimeMinutes != nil {
+		tournament.OvertimeMinutes = r.OvertimeMinutes
+	}
+}
+
+// RegisterTeamRequest registers a team in a tournament
+type RegisterTeamRequest struct {
+	TeamID uuid.UUID `json:"team_id" binding:"required"`
+	Seed   *int      `json:"seed,omitempty" binding:"omitempty,min=1"`
+	Group  string    `json:"group,omitempty" binding:"max=50"`
+}
+
+// UpdateEntryRequest changes how a registered team is ranked
+type UpdateEntryRequest struct {
+	Seed         *int    `json:"seed,omitempty" binding:"omitempty,min=1"`
+	Group        *string `json:"group,omitempty" binding:"omitempty,max=50"`
+	TiebreakRank *int    `json:"tiebreak_rank,omitempty" binding:"omitempty,min=1"`
+}
+
+// GetTournament gets a tournament by ID
+func (s *TournamentService) GetTournament(id uuid.UUID) (*models.Tournament, error) {
+	tournament, err := s.tournamentRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrTournamentNotFound
+	}
+	return tournament, nil
+}
+
+// ListTournaments gets tournaments matching the filters with pagination
+func (s *TournamentService) ListTournaments(offset, limit int, filters map[string]interface{}) ([]models.Tournament, int64, error) {
+	return s.tournamentRepo.List(offset, limit, filters)
+}
+
+// CreateTournament creates a tournament owned by an organization the actor
+// manages. Super admins may create leagues without an owning organization.
+func (s *TournamentService) CreateTournament(actor *models.User, req CreateTournamentRequest, actx AuditContext) (*models.Tournament, error) {
+	orgID := req.OrganizationID
+	if orgID == nil && !actor.IsAdmin() {
+		orgID = actor.OrganizationID
+		if orgID == nil {
+			return nil, ErrOrganizationRequired
+		}
+	}
+	if orgID != nil {
+		if !actor.CanManageOrganization(*orgID) {
+			return nil, ErrForbidden
+		}
+		if _, err := s.orgRepo.GetByID(*orgID); err != nil {
+			return nil, ErrOrganizationNotFound
+		}
+	}
+
+	startDate, err := parseDate(req.StartDate, ErrInvalidDate)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := parseDate(req.EndDate, ErrInvalidDate)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Before(*startDate) {
+		return nil, ErrInvalidTournamentDates
+	}
+
+	tournament := &models.Tournament{
+		OrganizationID: orgID,
+		Name:           req.Name,
+		Description:    req.Description,
+		StartDate:      *startDate,
+		EndDate:        *endDate,
+		Status:         models.TournamentStatusUpcoming,
+		CreatedBy:      &actor.ID,
+	}
+	req.TournamentRules.apply(tournament)
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.tournamentRepo.WithTx(tx).Create(tournament); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentCreate,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+			After:      tournament,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to create tournament")
+	}
+
+	return s.GetTournament(tournament.ID)
+}
+
+// UpdateTournament applies the non-nil fields of req to a tournament the actor
+// manages. Status changes must follow upcoming -> ongoing -> completed.
+func (s *TournamentService) UpdateTournament(actor *models.User, id uuid.UUID, req UpdateTournamentRequest, actx AuditContext) (*models.Tournament, error) {
+	tournament, err := s.managedTournament(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if tournament.Status == models.TournamentStatusCompleted {
+		return nil, ErrTournamentCompleted
+	}
+	before := *tournament
+	tournament.Organization = nil
+
+	if req.Name != nil {
+		tournament.Name = *req.Name
+	}
+	if req.Description != nil {
+		tournament.Description = *req.Description
+	}
+	if req.StartDate != nil {
+		date, err := parseDate(*req.StartDate, ErrInvalidDate)
+		if err != nil || date == nil {
+			return nil, ErrInvalidDate
+		}
+		tournament.StartDate = *date
+	}
+	if req.EndDate != nil {
+		date, err := parseDate(*req.EndDate, ErrInvalidDate)
+		if err != nil || date == nil {
+			return nil, ErrInvalidDate
+		}
+		tournament.EndDate = *date
+	}
+	if tournament.EndDate.Before(tournament.StartDate) {
+		return nil, ErrInvalidTournamentDates
+	}
+	req.TournamentRules.apply(tournament)
+	if req.Tiebreakers != nil {
+		chain, err := tiebreakerChain(*req.Tiebreakers)
+		if err != nil {
+			return nil, err
+		}
+		tournament.Tiebreakers = chain
+	}
+	if req.Status != nil && models.TournamentStatus(*req.Status) != tournament.Status {
+		next := models.TournamentStatus(*req.Status)
+		if !tournament.Status.CanTransitionTo(next) {
+			return nil, ErrInvalidStatusTransition
+		}
+		if next == models.TournamentStatusOngoing {
+			entries, err := s.tournamentRepo.CountEntries(tournament.ID)
+			if err != nil {
+				return nil, err
+			}
+			if entries < 2 {
+				return nil, ErrNotEnoughEntries
+			}
+		}
+		tournament.Status = next
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.tournamentRepo.WithTx(tx).Update(tournament); err != nil {
+			return err
+		}
+		if tournament.Tiebreakers != before.Tiebreakers {
+			if err := s.rebuildStandings(tx, tournament.ID); err != nil {
+				return err
+			}
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentUpdate,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+			Before:     &before,
+			After:      tournament,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to update tournament")
+	}
+
+	return s.GetTournament(tournament.ID)
+}
+
+// DeleteTournament soft deletes a tournament the actor manages
+func (s *TournamentService) DeleteTournament(actor *models.User, id uuid.UUID, actx AuditContext) error {
+	tournament, err := s.managedTournament(actor, id)
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.tournamentRepo.WithTx(tx).Delete(tournament.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentDelete,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+			Before:     tournament,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to delete tournament")
+	}
+	return nil
+}
+
+// ListEntries gets the teams registered in a tournament
+func (s *TournamentService) ListEntries(id uuid.UUID) ([]models.TournamentEntry, error) {
+	if _, err := s.GetTournament(id); err != nil {
+		return nil, err
+	}
+	return s.tournamentRepo.ListEntries(id)
+}
+
+// RegisterTeam enters an active team into an upcoming tournament
+func (s *TournamentService) RegisterTeam(actor *models.User, id uuid.UUID, req RegisterTeamRequest, actx AuditContext) (*models.TournamentEntry, error) {
+	tournament, err := s.managedTournament(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !tournament.IsUpcoming() {
+		return nil, ErrRegistrationClosed
+	}
+	team, err := s.teamRepo.GetByID(req.TeamID)
+	if err != nil {
+		return nil, ErrTeamNotFound
+	}
+	if !team.IsActive() {
+		return nil, ErrTeamInactive
+	}
+	if existing, _ := s.tournamentRepo.GetEntry(tournament.ID, team.ID); existing != nil {
+		return nil, ErrTeamAlreadyRegistered
+	}
+
+	entry := &models.TournamentEntry{
+		TournamentID: tournament.ID,
+		TeamID:       team.ID,
+		Seed:         req.Seed,
+		Group:        strings.TrimSpace(req.Group),
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.tournamentRepo.WithTx(tx).AddEntry(entry); err != nil {
+			return err
+		}
+		if err := s.rebuildStandings(tx, tournament.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentRegisterTeam,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+			After:      entry,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to register team")
+	}
+
+	entry.Team = team
+	return entry, nil
+}
+
+// WithdrawTeam removes a team from an upcoming tournament
+func (s *TournamentService) WithdrawTeam(actor *models.User, id, teamID uuid.UUID, actx AuditContext) error {
+	tournament, err := s.managedTournament(actor, id)
+	if err != nil {
+		return err
+	}
+	if !tournament.IsUpcoming() {
+		return ErrRegistrationClosed
+	}
+	entry, err := s.tournamentRepo.GetEntry(tournament.ID, teamID)
+	if err != nil {
+		return ErrTeamNotRegistered
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.tournamentRepo.WithTx(tx).RemoveEntry(entry.ID); err != nil {
+			return err
+		}
+		if err := s.rebuildStandings(tx, tournament.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentWithdrawTeam,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+			Before:     entry,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to withdraw team")
+	}
+	return nil
+}
+
+// UpdateEntry changes the seed, group or tiebreak rank of a team registered in
+// a tournament the actor manages, and reranks the standings
+func (s *TournamentService) UpdateEntry(actor *models.User, id, teamID uuid.UUID, req UpdateEntryRequest, actx AuditContext) (*models.TournamentEntry, error) {
+	tournament, err := s.managedTournament(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if tournament.Status == models.TournamentStatusCompleted {
+		return nil, ErrTournamentCompleted
+	}
+	entry, err := s.tournamentRepo.GetEntry(tournament.ID, teamID)
+	if err != nil {
+		return nil, ErrTeamNotRegistered
+	}
+	before := *entry
+
+	if req.Seed != nil {
+		entry.Seed = req.Seed
+	}
+	if req.Group != nil {
+		entry.Group = strings.TrimSpace(*req.Group)
+	}
+	if req.TiebreakRank != nil {
+		entry.TiebreakRank = req.TiebreakRank
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.tournamentRepo.WithTx(tx).UpdateEntry(entry); err != nil {
+			return err
+		}
+		if err := s.rebuildStandings(tx, tournament.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentUpdateEntry,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+			Before:     &before,
+			After:      entry,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to update entry")
+	}
+	return entry, nil
+}
+
+// GetStandings gets the standings of a tournament by group then rank. Before
+// any are stored they are computed from the registered teams.
+func (s *TournamentService) GetStandings(id uuid.UUID) ([]models.Standing, error) {
+	tournament, err := s.GetTournament(id)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.standingRepo.ListByTournament(id)
+	if err != nil || len(stored) > 0 {
+		return stored, err
+	}
+	entries, err := s.tournamentRepo.ListEntries(id)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.matchRepo.ListCompletedByTournament(id)
+	if err != nil {
+		return nil, err
+	}
+	return computeStandings(tournament, entries, matches), nil
+}
+
+// RecomputeStandings rebuilds the standings of a tournament the actor manages
+// from scratch
+func (s *TournamentService) RecomputeStandings(actor *models.User, id uuid.UUID, actx AuditContext) ([]models.Standing, error) {
+	tournament, err := s.managedTournament(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.rebuildStandings(tx, tournament.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionTournamentRecompute,
+			TargetType: models.AuditTargetTournament,
+			TargetID:   &tournament.ID,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to recompute standings")
+	}
+	return s.GetStandings(tournament.ID)
+}
+
+func (s *TournamentService) rebuildStandings(tx *gorm.DB, tournamentID uuid.UUID) error {
+	return rebuildStandings(s.tournamentRepo.WithTx(tx), s.matchRepo.WithTx(tx), s.standingRepo.WithTx(tx), tournamentID)
+}
+
+// rebuildStandings recomputes the standings of a tournament from its
+// registered teams and completed matches, and stores them. Run it in the
+// transaction of any change to them, so corrected results propagate.
+func rebuildStandings(tournamentRepo *repositories.TournamentRepository, matchRepo *repositories.MatchRepository, standingRepo *repositories.StandingRepository, tournamentID uuid.UUID) error {
+	tournament, err := tournamentRepo.GetByID(tournamentID)
+	if err != nil {
+		return err
+	}
+	entries, err := tournamentRepo.ListEntries(tournamentID)
+	if err != nil {
+		return err
+	}
+	matches, err := matchRepo.ListCompletedByTournament(tournamentID)
+	if err != nil {
+		return err
+	}
+	return standingRepo.ReplaceTournament(tournamentID, computeStandings(tournament, entries, matches))
+}
+
+// computeStandings ranks the registered teams of a tournament within their
+// groups, with the tournament's tiebreakers
+func computeStandings(tournament *models.Tournament, entries []models.TournamentEntry, matches []models.Match) []models.Standing {
+	teams := make([]standings.Team, len(entries))
+	byTeam := make(map[uuid.UUID]*models.Team, len(entries))
+	for i, entry := range entries {
+		teams[i] = standings.Team{ID: entry.TeamID, Group: entry.Group, TiebreakRank: entry.TiebreakRank}
+		byTeam[entry.TeamID] = entry.Team
+	}
+	results := make([]standings.Result, len(matches))
+	for i, match := range matches {
+		results[i] = standings.Result{
+			HomeTeamID: match.HomeTeamID,
+			AwayTeamID: match.AwayTeamID,
+			HomeScore:  match.HomeScore,
+			AwayScore:  match.AwayScore,
+		}
+	}
+
+	rows := standings.Compute(teams, results, tournament.TiebreakerChain())
+	table := make([]models.Standing, len(rows))
+	for i, row := range rows {
+		table[i] = models.Standing{
+			TournamentID:  tournament.ID,
+			TeamID:        row.TeamID,
+			Group:         row.Group,
+			Rank:          row.Rank,
+			Played:        row.Played,
+			Wins:          row.Wins,
+			Losses:        row.Losses,
+			PointsFor:     row.PointsFor,
+			PointsAgainst: row.PointsAgainst,
+			PointDiff:     row.PointDiff(),
+			WinPercentage: math.Round(row.WinPercentage()*1000) / 1000,
+			Tied:          row.Tied,
+			Team:          byTeam[row.TeamID],
+		}
+	}
+	return table
+}
+
+// managedTournament loads a tournament and checks the actor may modify it
+func (s *TournamentService) managedTournament(actor *models.User, id uuid.UUID) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(id)
+	if err != nil {
+		return nil, err
+	}
+	if !tournament.CanBeManagedBy(actor) {
+		return nil, ErrForbidden
+	}
+	return tournament, nil
+}
+
+// tiebreakerChain validates a tiebreaker chain and joins it for storage
+func tiebreakerChain(chain []models.Tiebreaker) (string, error) {
+	seen := map[models.Tiebreaker]bool{}
+	names := make([]string, len(chain))
+	for i, tiebreaker := range chain {
+		if !tiebreaker.IsValid() || seen[tiebreaker] {
+			return "", ErrInvalidTiebreakers
+		}
+		seen[tiebreaker] = true
+		names[i] = string(tiebreaker)
+	}
+	return strings.Join(names, ","), nil
+}
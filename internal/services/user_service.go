@@ -0,0 +1,306 @@
+package services
+
+import (
+	"errors"
+
+	"echo-golang/internal/database"
+	"echo-golang/internal/models"
+	"echo-golang/internal/repositories"
+	"echo-golang/internal/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrEmailAlreadyTaken = errors.New("email already registered")
+)
+
+type UserService struct {
+	userRepo     *repositories.UserRepository
+	auditService *AuditService
+}
+
+func NewUserService() *UserService {
+	return &UserService{
+		userRepo:     repositories.NewUserRepository(),
+		auditService: NewAuditService(),
+	}
+}
+
+type CreateUserRequest struct {
+	Email          string     `json:"email" binding:"required,email"`
+	Password       string     `json:"password" binding:"required,min=6"`
+	FullName       string     `json:"full_name" binding:"required"`
+	Role           string     `json:"role" binding:"required,oneof=super_admin org_admin team_member public"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	Phone          string     `json:"phone,omitempty"`
+	Status         string     `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+}
+
+type UpdateUserRequest struct {
+	Email          *string    `json:"email,omitempty" binding:"omitempty,email"`
+	Password       *string    `json:"password,omitempty" binding:"omitempty,min=6"`
+	FullName       *string    `json:"full_name,omitempty"`
+	Role           *string    `json:"role,omitempty" binding:"omitempty,oneof=super_admin org_admin team_member public"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	Phone          *string    `json:"phone,omitempty"`
+	Status         *string    `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+}
+
+// CreateMemberRequest creates a user inside an organization
+type CreateMemberRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+	FullName string `json:"full_name" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=org_admin team_member"`
+	Phone    string `json:"phone,omitempty"`
+}
+
+// UpdateMemberRequest holds the member fields an organization admin may change
+type UpdateMemberRequest struct {
+	FullName *string `json:"full_name,omitempty"`
+	Phone    *string `json:"phone,omitempty"`
+	Role     *string `json:"role,omitempty" binding:"omitempty,oneof=org_admin team_member"`
+	Status   *string `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+}
+
+// UserSearchResult is a matched user with its relevance score and the
+// highlighted ranges of each matched field
+type UserSearchResult struct {
+	User       models.User                       `json:"user"`
+	Score      float64                           `json:"score"`
+	Highlights map[string][]utils.HighlightRange `json:"highlights"`
+}
+
+// GetUser gets a user by ID
+func (s *UserService) GetUser(id uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// ListUsers gets users matching the filters with pagination
+func (s *UserService) ListUsers(offset, limit int, filters map[string]interface{}) ([]models.User, int64, error) {
+	return s.userRepo.List(offset, limit, filters)
+}
+
+// SearchUsers finds users by partial name, email or phone, most relevant first
+func (s *UserService) SearchUsers(term string, offset, limit int, filters map[string]interface{}) ([]UserSearchResult, int64, error) {
+	hits, total, err := s.userRepo.Search(term, offset, limit, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]UserSearchResult, len(hits))
+	for i, hit := range hits {
+		highlights := map[string][]utils.HighlightRange{}
+		for field, value := range map[string]string{
+			"full_name": hit.User.FullName,
+			"email":     hit.User.Email,
+			"phone":     hit.User.Phone,
+		} {
+			if ranges := utils.Highlight(value, term); len(ranges) > 0 {
+				highlights[field] = ranges
+			}
+		}
+		results[i] = UserSearchResult{User: hit.User, Score: hit.Score, Highlights: highlights}
+	}
+	return results, total, nil
+}
+
+// CreateUser creates a user and records it in the audit log
+func (s *UserService) CreateUser(req CreateUserRequest, actx AuditContext) (*models.User, error) {
+	if existing, _ := s.userRepo.GetByEmail(req.Email); existing != nil {
+		return nil, ErrEmailAlreadyTaken
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	status := models.UserStatusActive
+	if req.Status != "" {
+		status = models.UserStatus(req.Status)
+	}
+
+	user := &models.User{
+		Email:          req.Email,
+		Password:       hashedPassword,
+		Role:           models.UserRole(req.Role),
+		OrganizationID: req.OrganizationID,
+		FullName:       req.FullName,
+		Phone:          req.Phone,
+		Status:         status,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).Create(user); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionUserCreate,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &user.ID,
+			After:      user,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to create user")
+	}
+
+	return s.GetUser(user.ID)
+}
+
+// UpdateUser applies the non-nil fields of req to a user and records the diff
+func (s *UserService) UpdateUser(id uuid.UUID, req UpdateUserRequest, actx AuditContext) (*models.User, error) {
+	user, err := s.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	before := *user
+	user.Organization = nil
+
+	if req.Email != nil && *req.Email != user.Email {
+		if existing, _ := s.userRepo.GetByEmail(*req.Email); existing != nil {
+			return nil, ErrEmailAlreadyTaken
+		}
+		user.Email = *req.Email
+	}
+	if req.Password != nil {
+		hashedPassword, err := utils.HashPassword(*req.Password)
+		if err != nil {
+			return nil, errors.New("failed to hash password")
+		}
+		user.Password = hashedPassword
+	}
+	if req.FullName != nil {
+		user.FullName = *req.FullName
+	}
+	if req.Role != nil {
+		user.Role = models.UserRole(*req.Role)
+	}
+	if req.OrganizationID != nil {
+		user.OrganizationID = req.OrganizationID
+	}
+	if req.Phone != nil {
+		user.Phone = *req.Phone
+	}
+	if req.Status != nil {
+		user.Status = models.UserStatus(*req.Status)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).Update(user); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionUserUpdate,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &user.ID,
+			Before:     &before,
+			After:      user,
+		})
+	})
+	if err != nil {
+		return nil, errors.New("failed to update user")
+	}
+
+	return s.GetUser(user.ID)
+}
+
+// DeleteUser soft deletes a user and records it in the audit log
+func (s *UserService) DeleteUser(id uuid.UUID, actx AuditContext) error {
+	user, err := s.GetUser(id)
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).Delete(user.ID); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionUserDelete,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &user.ID,
+			Before:     user,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to delete user")
+	}
+	return nil
+}
+
+// GetOrganizationMember gets a user only if they belong to the organization.
+// Super admins are never treated as organization members.
+func (s *UserService) GetOrganizationMember(orgID, userID uuid.UUID) (*models.User, error) {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrganizationID == nil || *user.OrganizationID != orgID || user.IsAdmin() {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// AddOrganizationMember creates a new user in the organization
+func (s *UserService) AddOrganizationMember(orgID uuid.UUID, req CreateMemberRequest, actx AuditContext) (*models.User, error) {
+	return s.CreateUser(CreateUserRequest{
+		Email:          req.Email,
+		Password:       req.Password,
+		FullName:       req.FullName,
+		Role:           req.Role,
+		OrganizationID: &orgID,
+		Phone:          req.Phone,
+	}, actx)
+}
+
+// UpdateOrganizationMember updates a member of the organization
+func (s *UserService) UpdateOrganizationMember(orgID, userID uuid.UUID, req UpdateMemberRequest, actx AuditContext) (*models.User, error) {
+	if _, err := s.GetOrganizationMember(orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.UpdateUser(userID, UpdateUserRequest{
+		FullName: req.FullName,
+		Phone:    req.Phone,
+		Role:     req.Role,
+		Status:   req.Status,
+	}, actx)
+}
+
+// RemoveOrganizationMember detaches a member from the organization. The account
+// is kept but loses its organization role.
+func (s *UserService) RemoveOrganizationMember(orgID, userID uuid.UUID, actx AuditContext) error {
+	user, err := s.GetOrganizationMember(orgID, userID)
+	if err != nil {
+		return err
+	}
+	before := *user
+	user.Organization = nil
+	user.OrganizationID = nil
+	user.Role = models.RolePublic
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).Update(user); err != nil {
+			return err
+		}
+		return s.auditService.Record(tx, actx, AuditEntry{
+			Action:     models.AuditActionUserUpdate,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &user.ID,
+			Before:     &before,
+			After:      user,
+		})
+	})
+	if err != nil {
+		return errors.New("failed to remove member")
+	}
+	return nil
+}
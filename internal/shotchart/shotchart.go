@@ -0,0 +1,149 @@
+// Package shotchart places shots on the court and tallies them by zone and on
+// a grid for heat maps. Locations are normalized to the half court the shot
+// was taken on, so charts don't depend on which basket a team attacked.
+package shotchart
+
+import (
+	"math"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Half court geometry under FIBA rules, in meters
+const (
+	courtWidth     = 15.0
+	halfLength     = 14.0
+	basketX        = courtWidth / 2
+	basketY        = 1.575 // Center of the basket from the baseline
+	restrictedArea = 1.25  // Radius of the no-charge semi-circle
+	keyHalfWidth   = 2.45
+	keyLength      = 5.8  // Baseline to free throw line
+	cornerLength   = 2.99 // Baseline to where the three-point line starts to arc
+)
+
+// Classify gives the zone of a shot worth points. Free throws are always in
+// the free throw zone. Other shots are placed from their location when they
+// have one, and keep the zone the scorekeeper picked otherwise. The shot's
+// value decides between the two and three-point zones, so a three scored with
+// a foot near the line isn't charted as a long two.
+func Classify(points int, x, y *float64, picked models.ShotZone) models.ShotZone {
+	if points == 1 {
+		return models.ShotZoneFreeThrow
+	}
+	if x == nil || y == nil {
+		return picked
+	}
+
+	mx, my := *x*courtWidth, *y*halfLength
+	if points == 3 {
+		switch {
+		case my > cornerLength:
+			return models.ShotZoneAboveBreak
+		case mx < basketX:
+			return models.ShotZoneLeftCorner
+		default:
+			return models.ShotZoneRightCorner
+		}
+	}
+	switch {
+	case math.Hypot(mx-basketX, my-basketY) <= restrictedArea:
+		return models.ShotZoneRestrictedArea
+	case math.Abs(mx-basketX) <= keyHalfWidth && my <= keyLength:
+		return models.ShotZonePaint
+	default:
+		return models.ShotZoneMidRange
+	}
+}
+
+// Tally is the attempts and makes of a zone or grid cell
+type Tally struct {
+	Attempts int
+	Makes    int
+}
+
+// Chart is the shots of a player or team
+type Chart struct {
+	Zones     map[models.ShotZone]*Tally
+	Unlocated Tally             // Field goals recorded without a zone
+	Cells     map[[2]int]*Tally // Located shots by grid column and row
+	gridSize  int
+}
+
+// Charts holds one chart per team and per player
+type Charts struct {
+	Teams   map[uuid.UUID]*Chart
+	Players map[uuid.UUID]*Chart
+}
+
+// Build tallies the made and missed shots among events into team and player
+// charts, with located shots binned on a gridSize by gridSize grid over the
+// half court. Voided events are skipped.
+func Build(events []models.MatchEvent, gridSize int) Charts {
+	charts := Charts{Teams: map[uuid.UUID]*Chart{}, Players: map[uuid.UUID]*Chart{}}
+	for _, event := range events {
+		if event.Voided || event.TeamID == nil ||
+			(event.Type != models.MatchEventPoint && event.Type != models.MatchEventMiss) {
+			continue
+		}
+		made := event.Type == models.MatchEventPoint
+		chart(charts.Teams, *event.TeamID, gridSize).add(event, made)
+		if event.PlayerID != nil {
+			chart(charts.Players, *event.PlayerID, gridSize).add(event, made)
+		}
+	}
+	return charts
+}
+
+func chart(charts map[uuid.UUID]*Chart, id uuid.UUID, gridSize int) *Chart {
+	c, ok := charts[id]
+	if !ok {
+		c = &Chart{Zones: map[models.ShotZone]*Tally{}, Cells: map[[2]int]*Tally{}, gridSize: gridSize}
+		for _, zone := range models.ShotZones {
+			c.Zones[zone] = &Tally{}
+		}
+		charts[id] = c
+	}
+	return c
+}
+
+func (c *Chart) add(event models.MatchEvent, made bool) {
+	zone := Classify(event.Points, event.ShotX, event.ShotY, event.ShotZone)
+	if tally, ok := c.Zones[zone]; ok {
+		tally.record(made)
+	} else {
+		c.Unlocated.record(made)
+	}
+
+	if event.ShotX == nil || event.ShotY == nil || c.gridSize <= 0 {
+		return
+	}
+	cell := [2]int{bin(*event.ShotX, c.gridSize), bin(*event.ShotY, c.gridSize)}
+	tally, ok := c.Cells[cell]
+	if !ok {
+		tally = &Tally{}
+		c.Cells[cell] = tally
+	}
+	tally.record(made)
+}
+
+func (t *Tally) record(made bool) {
+	t.Attempts++
+	if made {
+		t.Makes++
+	}
+}
+
+// bin gives the grid index of a normalized coordinate, the far edge falling
+// in the last cell
+func bin(v float64, gridSize int) int {
+	i := int(v * float64(gridSize))
+	if i >= gridSize {
+		return gridSize - 1
+	}
+	if i < 0 {
+		return 0
+	}
+	return i
+}
@@ -0,0 +1,93 @@
+package shotchart
+
+import (
+	"testing"
+
+	"echo-golang/internal/models"
+)
+
+// at converts a location in meters on the half court to the normalized
+// coordinates shots are recorded in
+func at(mx, my float64) (*float64, *float64) {
+	x, y := mx/courtWidth, my/halfLength
+	return &x, &y
+}
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name   string
+		points int
+		mx, my float64
+		picked models.ShotZone
+		want   models.ShotZone
+	}{
+		{"free throw", 1, 7.5, 5.8, "", models.ShotZoneFreeThrow},
+		{"under the basket", 2, basketX, basketY, "", models.ShotZoneRestrictedArea},
+		{"inside the restricted area", 2, basketX, basketY + 1.249, "", models.ShotZoneRestrictedArea},
+		{"just outside the restricted area", 2, basketX, basketY + 1.251, "", models.ShotZonePaint},
+		{"restricted area on the diagonal", 2, basketX + 0.88, basketY + 0.88, "", models.ShotZoneRestrictedArea},
+		{"paint beside the restricted area", 2, basketX + 0.9, basketY + 0.9, "", models.ShotZonePaint},
+		{"paint at the lane line", 2, basketX + 2.44, 3, "", models.ShotZonePaint},
+		{"outside the lane line", 2, basketX + 2.46, 3, "", models.ShotZoneMidRange},
+		{"paint at the free throw line", 2, basketX - 1, 5.79, "", models.ShotZonePaint},
+		{"past the free throw line", 2, basketX - 1, 5.81, "", models.ShotZoneMidRange},
+		{"baseline two", 2, 1, 0.5, "", models.ShotZoneMidRange},
+		{"left corner three", 3, 0.5, 1, "", models.ShotZoneLeftCorner},
+		{"right corner three", 3, 14.5, 1, "", models.ShotZoneRightCorner},
+		{"corner three where the arc starts", 3, 0.5, 2.99, "", models.ShotZoneLeftCorner},
+		{"three past the corner", 3, 0.5, 3.0, "", models.ShotZoneAboveBreak},
+		{"three at the top of the arc", 3, basketX, 8.5, "", models.ShotZoneAboveBreak},
+		{"location overrides the pick", 2, basketX, basketY, models.ShotZoneMidRange, models.ShotZoneRestrictedArea},
+		{"value overrides the location", 3, basketX, 6, "", models.ShotZoneAboveBreak},
+	}
+	for _, c := range cases {
+		x, y := at(c.mx, c.my)
+		if got := Classify(c.points, x, y, c.picked); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	// Without a location the scorekeeper's pick stands, except for free throws
+	x, _ := at(basketX, basketY)
+	unlocated := []struct {
+		name   string
+		points int
+		x, y   *float64
+		picked models.ShotZone
+		want   models.ShotZone
+	}{
+		{"no location", 2, nil, nil, models.ShotZonePaint, models.ShotZonePaint},
+		{"only x", 2, x, nil, models.ShotZoneMidRange, models.ShotZoneMidRange},
+		{"no zone either", 3, nil, nil, "", ""},
+		{"free throw", 1, nil, nil, models.ShotZonePaint, models.ShotZoneFreeThrow},
+	}
+	for _, c := range unlocated {
+		if got := Classify(c.points, c.x, c.y, c.picked); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestBin(t *testing.T) {
+	cases := []struct {
+		v        float64
+		gridSize int
+		want     int
+	}{
+		{0, 10, 0},
+		{0.099, 10, 0},
+		{0.1, 10, 1},
+		{0.5, 10, 5},
+		{0.999, 10, 9},
+		{1.0, 10, 9}, // The far sideline or half-court line
+		{1.0, 1, 0},
+		{0.75, 4, 3},
+		{-0.01, 10, 0},
+		{1.01, 10, 9},
+	}
+	for _, c := range cases {
+		if got := bin(c.v, c.gridSize); got != c.want {
+			t.Errorf("bin(%v, %d): got %d, want %d", c.v, c.gridSize, got, c.want)
+		}
+	}
+}
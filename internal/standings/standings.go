@@ -0,0 +1,204 @@
+// Package standings ranks the teams of a tournament from its match results.
+// Teams are ranked within their group by win percentage, and teams level on
+// it are separated by the tournament's chain of tiebreakers.
+package standings
+
+import (
+	"math"
+	"sort"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Team is a team taking part, listed in seeding order
+type Team struct {
+	ID           uuid.UUID
+	Group        string
+	TiebreakRank *int // Coin toss or organizer ruling, lower first
+}
+
+// Result is the final score of a completed match
+type Result struct {
+	HomeTeamID uuid.UUID
+	AwayTeamID uuid.UUID
+	HomeScore  int
+	AwayScore  int
+}
+
+// Row is a team's record and rank within its group
+type Row struct {
+	TeamID        uuid.UUID
+	Group         string
+	Rank          int
+	Played        int
+	Wins          int
+	Losses        int
+	PointsFor     int
+	PointsAgainst int
+	Tied          bool // Level after every tiebreaker
+
+	team Team
+	seed int
+}
+
+// PointDiff is the row's point differential
+func (r *Row) PointDiff() int {
+	return r.PointsFor - r.PointsAgainst
+}
+
+// WinPercentage is the share of games won, 0 before any game
+func (r *Row) WinPercentage() float64 {
+	if r.Played == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Played)
+}
+
+// Compute ranks the teams within their groups, groups in name order. Results
+// involving a team not taking part are ignored.
+//
+// Teams level on win percentage go through the tiebreakers in order. As soon
+// as one splits the tied teams, each smaller group still level starts over
+// from the first tiebreaker, counting only the games among its own teams for
+// the head-to-head ones. Teams level after every tiebreaker keep their
+// seeding order and are flagged Tied.
+func Compute(teams []Team, results []Result, chain []models.Tiebreaker) []Row {
+	c := &computation{rows: map[uuid.UUID]*Row{}, chain: chain}
+	groups := map[string][]*Row{}
+	var names []string
+	for i, team := range teams {
+		if _, ok := c.rows[team.ID]; ok {
+			continue
+		}
+		row := &Row{TeamID: team.ID, Group: team.Group, team: team, seed: i}
+		c.rows[team.ID] = row
+		if _, ok := groups[team.Group]; !ok {
+			names = append(names, team.Group)
+		}
+		groups[team.Group] = append(groups[team.Group], row)
+	}
+
+	for _, result := range results {
+		home, away := c.rows[result.HomeTeamID], c.rows[result.AwayTeamID]
+		if home == nil || away == nil {
+			continue
+		}
+		home.record(result.HomeScore, result.AwayScore)
+		away.record(result.AwayScore, result.HomeScore)
+		c.results = append(c.results, result)
+	}
+
+	sort.Strings(names)
+	var ranked []Row
+	for _, name := range names {
+		rows := groups[name]
+		var ordered []*Row
+		for _, tier := range split(rows, func(r *Row) float64 { return r.WinPercentage() }) {
+			ordered = append(ordered, c.order(tier)...)
+		}
+		for i, row := range ordered {
+			row.Rank = i + 1
+			ranked = append(ranked, *row)
+		}
+	}
+	return ranked
+}
+
+type computation struct {
+	rows    map[uuid.UUID]*Row
+	results []Result
+	chain   []models.Tiebreaker
+}
+
+// order sorts teams level on win percentage with the tiebreaker chain
+func (c *computation) order(tied []*Row) []*Row {
+	if len(tied) == 1 {
+		return tied
+	}
+	for _, tiebreaker := range c.chain {
+		tiers := split(tied, c.key(tiebreaker, tied))
+		if len(tiers) == 1 {
+			continue
+		}
+		var ordered []*Row
+		for _, tier := range tiers {
+			ordered = append(ordered, c.order(tier)...)
+		}
+		return ordered
+	}
+
+	for _, row := range tied {
+		row.Tied = true
+	}
+	return tied
+}
+
+// key gives the value a tiebreaker ranks the tied teams by, highest first
+func (c *computation) key(tiebreaker models.Tiebreaker, tied []*Row) func(*Row) float64 {
+	switch tiebreaker {
+	case models.TiebreakHeadToHead, models.TiebreakHeadToHeadPointDiff:
+		among := map[uuid.UUID]*Row{}
+		for _, row := range tied {
+			among[row.TeamID] = &Row{}
+		}
+		for _, result := range c.results {
+			home, away := among[result.HomeTeamID], among[result.AwayTeamID]
+			if home == nil || away == nil {
+				continue
+			}
+			home.record(result.HomeScore, result.AwayScore)
+			away.record(result.AwayScore, result.HomeScore)
+		}
+		if tiebreaker == models.TiebreakHeadToHead {
+			return func(r *Row) float64 { return among[r.TeamID].WinPercentage() }
+		}
+		return func(r *Row) float64 { return float64(among[r.TeamID].PointDiff()) }
+	case models.TiebreakPointDiff:
+		return func(r *Row) float64 { return float64(r.PointDiff()) }
+	case models.TiebreakPointsFor:
+		return func(r *Row) float64 { return float64(r.PointsFor) }
+	case models.TiebreakManual:
+		return func(r *Row) float64 {
+			if r.team.TiebreakRank == nil {
+				return math.Inf(-1)
+			}
+			return -float64(*r.team.TiebreakRank)
+		}
+	}
+	return func(*Row) float64 { return 0 }
+}
+
+// split groups rows by key, highest first, keeping seeding order within each
+// group
+func split(rows []*Row, key func(*Row) float64) [][]*Row {
+	sorted := append([]*Row(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ki, kj := key(sorted[i]), key(sorted[j]); ki != kj {
+			return ki > kj
+		}
+		return sorted[i].seed < sorted[j].seed
+	})
+
+	var tiers [][]*Row
+	for i, row := range sorted {
+		if i == 0 || key(row) != key(sorted[i-1]) {
+			tiers = append(tiers, nil)
+		}
+		tiers[len(tiers)-1] = append(tiers[len(tiers)-1], row)
+	}
+	return tiers
+}
+
+// record adds the result of one game to the row
+func (r *Row) record(scored, conceded int) {
+	r.Played++
+	if scored > conceded {
+		r.Wins++
+	} else {
+		r.Losses++
+	}
+	r.PointsFor += scored
+	r.PointsAgainst += conceded
+}
@@ -0,0 +1,234 @@
+package standings
+
+import (
+	"strings"
+	"testing"
+
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// league names the teams of a test so results and expectations read as
+// letters
+type league map[string]uuid.UUID
+
+func (l league) id(name string) uuid.UUID {
+	if _, ok := l[name]; !ok {
+		l[name] = uuid.New()
+	}
+	return l[name]
+}
+
+func (l league) name(id uuid.UUID) string {
+	for name, known := range l {
+		if known == id {
+			return name
+		}
+	}
+	return "?"
+}
+
+// teams lists the teams in seeding order, each "name" or "group:name"
+func (l league) teams(entries ...string) []Team {
+	var teams []Team
+	for _, entry := range entries {
+		group, name := "", entry
+		if i := strings.Index(entry, ":"); i >= 0 {
+			group, name = entry[:i], entry[i+1:]
+		}
+		teams = append(teams, Team{ID: l.id(name), Group: group})
+	}
+	return teams
+}
+
+// game is a result with the home team's name and score first
+func (l league) game(home string, homeScore, awayScore int, away string) Result {
+	return Result{HomeTeamID: l.id(home), AwayTeamID: l.id(away), HomeScore: homeScore, AwayScore: awayScore}
+}
+
+func rank(n int) *int {
+	return &n
+}
+
+func TestCompute(t *testing.T) {
+	full := []models.Tiebreaker{
+		models.TiebreakHeadToHead,
+		models.TiebreakPointDiff,
+		models.TiebreakPointsFor,
+		models.TiebreakManual,
+	}
+
+	cases := []struct {
+		name    string
+		teams   []string
+		manual  map[string]int
+		results func(l league) []Result
+		chain   []models.Tiebreaker
+		want    string // Group order, "name" or "name=" for Tied rows
+	}{
+		{
+			name:  "win percentage",
+			teams: []string{"C", "B", "A"},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 70, 60, "B"), l.game("A", 70, 60, "C"), l.game("B", 70, 60, "C")}
+			},
+			chain: full,
+			want:  "A B C",
+		},
+		{
+			// A beat both, B beat C, and each is 2-2 after the games against
+			// X in another group
+			name:  "three-way tie broken by head-to-head",
+			teams: []string{"C", "B", "A", "other:X"},
+			results: func(l league) []Result {
+				return []Result{
+					l.game("A", 70, 60, "B"), l.game("A", 70, 60, "C"), l.game("B", 70, 60, "C"),
+					l.game("X", 90, 50, "A"), l.game("X", 90, 50, "A"),
+					l.game("X", 90, 50, "B"), l.game("B", 61, 60, "X"),
+					l.game("C", 90, 50, "X"), l.game("C", 90, 50, "X"),
+				}
+			},
+			chain: full,
+			want:  "A B C X",
+		},
+		{
+			// Point differential splits off A, and B and C, level on it and
+			// 1-1 among all three, are separated by B's win over C alone
+			name:  "split off team restarts the chain for the rest",
+			teams: []string{"C", "B", "A"},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 80, 70, "B"), l.game("C", 72, 70, "A"), l.game("B", 66, 60, "C")}
+			},
+			chain: []models.Tiebreaker{models.TiebreakPointDiff, models.TiebreakHeadToHead},
+			want:  "A B C",
+		},
+		{
+			name:  "cycle falls through to point differential",
+			teams: []string{"A", "B", "C"},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 80, 70, "B"), l.game("B", 75, 70, "C"), l.game("C", 71, 70, "A")}
+			},
+			chain: full,
+			want:  "A C B",
+		},
+		{
+			name:  "cycle falls through to points for",
+			teams: []string{"A", "B", "C"},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 80, 70, "B"), l.game("B", 60, 50, "C"), l.game("C", 90, 80, "A")}
+			},
+			chain: full,
+			want:  "A C B",
+		},
+		{
+			name:   "cycle falls through to manual rank",
+			teams:  []string{"A", "B", "C"},
+			manual: map[string]int{"A": 3, "B": 1, "C": 2},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 70, 60, "B"), l.game("B", 70, 60, "C"), l.game("C", 70, 60, "A")}
+			},
+			chain: full,
+			want:  "B C A",
+		},
+		{
+			// C is split off, and A's win over B decides the rest
+			name:   "teams without a manual rank come last",
+			teams:  []string{"A", "B", "C"},
+			manual: map[string]int{"C": 1},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 70, 60, "B"), l.game("B", 70, 60, "C"), l.game("C", 70, 60, "A")}
+			},
+			chain: full,
+			want:  "C A B",
+		},
+		{
+			name:  "tiebreakers exhausted keep seeding order",
+			teams: []string{"B", "C", "A"},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 70, 60, "B"), l.game("B", 70, 60, "C"), l.game("C", 70, 60, "A")}
+			},
+			chain: full,
+			want:  "B= C= A=",
+		},
+		{
+			name:  "no tiebreakers",
+			teams: []string{"B", "A"},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 90, 60, "B"), l.game("B", 70, 60, "A")}
+			},
+			want: "B= A=",
+		},
+		{
+			name:  "ranked per group, groups in name order",
+			teams: []string{"south:D", "north:A", "south:C", "north:B"},
+			results: func(l league) []Result {
+				return []Result{l.game("B", 70, 60, "A"), l.game("C", 70, 60, "D"), l.game("A", 70, 60, "C")}
+			},
+			chain: full,
+			want:  "B A C D",
+		},
+		{
+			name:  "results against teams not taking part are ignored",
+			teams: []string{"A", "B"},
+			results: func(l league) []Result {
+				return []Result{l.game("A", 70, 60, "B"), l.game("Z", 90, 50, "A"), l.game("Z", 90, 50, "A"), l.game("B", 80, 50, "Z")}
+			},
+			chain: full,
+			want:  "A B",
+		},
+	}
+
+	for _, c := range cases {
+		l := league{}
+		teams := l.teams(c.teams...)
+		for i := range teams {
+			if r, ok := c.manual[l.name(teams[i].ID)]; ok {
+				teams[i].TiebreakRank = rank(r)
+			}
+		}
+		rows := Compute(teams, c.results(l), c.chain)
+
+		var got []string
+		for _, row := range rows {
+			name := l.name(row.TeamID)
+			if row.Tied {
+				name += "="
+			}
+			got = append(got, name)
+		}
+		if strings.Join(got, " ") != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, strings.Join(got, " "), c.want)
+		}
+	}
+}
+
+func TestComputeRecords(t *testing.T) {
+	l := league{}
+	teams := l.teams("east:A", "east:B", "west:C")
+	results := []Result{
+		l.game("A", 70, 60, "B"),
+		l.game("C", 80, 75, "A"),
+		l.game("A", 50, 40, "Z"), // Z isn't taking part
+	}
+	rows := Compute(teams, results, models.DefaultTiebreakers)
+
+	want := map[string]Row{
+		"A": {Group: "east", Rank: 1, Played: 2, Wins: 1, Losses: 1, PointsFor: 145, PointsAgainst: 140},
+		"B": {Group: "east", Rank: 2, Played: 1, Wins: 0, Losses: 1, PointsFor: 60, PointsAgainst: 70},
+		"C": {Group: "west", Rank: 1, Played: 1, Wins: 1, Losses: 0, PointsFor: 80, PointsAgainst: 75},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for _, row := range rows {
+		w := want[l.name(row.TeamID)]
+		if row.Group != w.Group || row.Rank != w.Rank || row.Played != w.Played || row.Wins != w.Wins ||
+			row.Losses != w.Losses || row.PointsFor != w.PointsFor || row.PointsAgainst != w.PointsAgainst {
+			t.Errorf("%s: got %+v, want %+v", l.name(row.TeamID), row, w)
+		}
+	}
+	if a := rows[0]; a.PointDiff() != 5 || a.WinPercentage() != 0.5 {
+		t.Errorf("A: point diff %d, win percentage %v", a.PointDiff(), a.WinPercentage())
+	}
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// HighlightRange marks a matched span as [Start, End) character (rune) offsets
+type HighlightRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Highlight finds case-insensitive matches of term in value. The whole term is
+// tried first; if it doesn't occur, each whitespace separated word is matched.
+// Overlapping ranges are merged.
+func Highlight(value, term string) []HighlightRange {
+	haystack := lowerRunes(value)
+
+	ranges := findAll(haystack, lowerRunes(strings.TrimSpace(term)))
+	if len(ranges) == 0 {
+		for _, word := range strings.Fields(term) {
+			ranges = append(ranges, findAll(haystack, lowerRunes(word))...)
+		}
+	}
+	return mergeRanges(ranges)
+}
+
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func findAll(haystack, needle []rune) []HighlightRange {
+	var ranges []HighlightRange
+	if len(needle) == 0 {
+		return ranges
+	}
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if runesEqual(haystack[i:i+len(needle)], needle) {
+			ranges = append(ranges, HighlightRange{Start: i, End: i + len(needle)})
+			i += len(needle) - 1
+		}
+	}
+	return ranges
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeRanges(ranges []HighlightRange) []HighlightRange {
+	if len(ranges) < 2 {
+		return ranges
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+	merged := []HighlightRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
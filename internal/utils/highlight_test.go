@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		term  string
+		want  []HighlightRange
+	}{
+		{"whole term", "John Smith", "john", []HighlightRange{{0, 4}}},
+		{"case insensitive", "john smith", "SMITH", []HighlightRange{{5, 10}}},
+		{"occurrences don't overlap", "anna banana", "ana", []HighlightRange{{6, 9}}},
+		{"repeated", "abab ab", "ab", []HighlightRange{{0, 4}, {5, 7}}},
+		{"whole term before words", "John Smith, Smith John", "smith john", []HighlightRange{{12, 22}}},
+		{"words when the term doesn't occur", "John Smith", "smith john", []HighlightRange{{0, 4}, {5, 10}}},
+		{"overlapping words merged", "Johnson", "john johnson", []HighlightRange{{0, 7}}},
+		{"surrounding space ignored", "John", "  jo ", []HighlightRange{{0, 2}}},
+		{"rune offsets", "Zoë Ångström", "ång", []HighlightRange{{4, 7}}},
+		{"no match", "John Smith", "zebra", nil},
+		{"empty term", "John Smith", "", nil},
+	}
+	for _, c := range cases {
+		if got := Highlight(c.value, c.term); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
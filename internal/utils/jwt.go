@@ -57,14 +57,66 @@ func GenerateRefreshToken(user *models.User) (string, error) {
 	return token.SignedString([]byte(config.AppConfig.JWTSecret))
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// StreamTicketExpiration is how long a stream ticket can be used to open a
+// live feed. A feed already open stays open after the ticket expires.
+const StreamTicketExpiration = time.Minute
+
+// GenerateStreamTicket generates a ticket that opens the live feed of one
+// match for a user. Browsers can't set headers on WebSocket and EventSource
+// requests, so the ticket goes in the URL, where it gets logged; unlike an
+// access token it's only good for that feed and only for a minute.
+func GenerateStreamTicket(user *models.User, matchID uuid.UUID) (string, time.Time, error) {
+	expiresAt := time.Now().Add(StreamTicketExpiration)
+	claims := JWTClaims{
+		UserID:         user.ID,
+		Email:          user.Email,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    "basketball-app",
+			Subject:   user.ID.String(),
+			Audience:  jwt.ClaimStrings{streamTicketAudience(matchID)},
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	ticket, err := token.SignedString([]byte(config.AppConfig.JWTSecret))
+	return ticket, expiresAt, err
+}
+
+// ValidateStreamTicket validates a stream ticket for the live feed of a match
+// and returns the claims
+func ValidateStreamTicket(ticket string, matchID uuid.UUID) (*JWTClaims, error) {
+	return parseToken(ticket, jwt.WithAudience(streamTicketAudience(matchID)))
+}
+
+func streamTicketAudience(matchID uuid.UUID) string {
+	return "live-stream:" + matchID.String()
+}
+
+// ValidateToken validates a JWT token and returns the claims. Stream tickets
+// are refused: they only open live feeds.
 func ValidateToken(tokenString string) (*JWTClaims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if len(claims.Audience) > 0 {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func parseToken(tokenString string, options ...jwt.ParserOption) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
 		return []byte(config.AppConfig.JWTSecret), nil
-	})
+	}, options...)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"echo-golang/internal/config"
+	"echo-golang/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestStreamTicket(t *testing.T) {
+	saved := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = saved })
+	config.AppConfig = &config.Config{JWTSecret: "test-secret", JWTExpiration: 15 * time.Minute}
+
+	user := &models.User{ID: uuid.New(), Email: "scorer@example.com", Role: models.RoleTeamMember}
+	match := uuid.New()
+	ticket, expiresAt, err := GenerateStreamTicket(user, match)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(expiresAt) > StreamTicketExpiration {
+		t.Errorf("ticket expires at %v, later than %v from now", expiresAt, StreamTicketExpiration)
+	}
+
+	claims, err := ValidateStreamTicket(ticket, match)
+	if err != nil {
+		t.Fatalf("ticket for its match: %v", err)
+	}
+	if claims.UserID != user.ID {
+		t.Errorf("ticket user: got %v, want %v", claims.UserID, user.ID)
+	}
+	if _, err := ValidateStreamTicket(ticket, uuid.New()); err == nil {
+		t.Error("ticket accepted for another match")
+	}
+	if _, err := ValidateToken(ticket); err == nil {
+		t.Error("ticket accepted as an access token")
+	}
+
+	token, err := GenerateToken(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateStreamTicket(token, match); err == nil {
+		t.Error("access token accepted as a ticket")
+	}
+	if _, err := ValidateToken(token); err != nil {
+		t.Errorf("access token: %v", err)
+	}
+}